@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/service"
+)
+
+// runSendUpdatesCommand runs a single update run from the command line and prints its report, e.g.
+//
+//	go run ./cmd/server send-updates -frequency daily -city Kyiv -dry-run
+func runSendUpdatesCommand(emailService *service.EmailService, args []string) int {
+	fs := flag.NewFlagSet("send-updates", flag.ContinueOnError)
+	frequency := fs.String("frequency", "", "subscription frequency to run (hourly or daily)")
+	city := fs.String("city", "", "only send to subscriptions for this city")
+	subscriptionID := fs.Int("subscription", 0, "only send to the subscription with this id")
+	dryRun := fs.Bool("dry-run", false, "render emails and report recipients without sending")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	freq := domain.Frequency(*frequency)
+	if freq != "" && freq != domain.FrequencyDaily && freq != domain.FrequencyHourly {
+		fmt.Fprintf(os.Stderr, "invalid frequency %q\n", *frequency)
+		return 2
+	}
+	if freq == "" && *subscriptionID == 0 {
+		fmt.Fprintln(os.Stderr, "either -frequency or -subscription is required")
+		return 2
+	}
+
+	report, err := emailService.RunUpdates(context.Background(), domain.RunRequest{
+		Frequency:      freq,
+		City:           *city,
+		SubscriptionID: *subscriptionID,
+		DryRun:         *dryRun,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "update run failed: %v\n", err)
+		return 1
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write report: %v\n", err)
+		return 1
+	}
+	if report.Failed > 0 || report.Error != "" {
+		return 1
+	}
+	return 0
+}
@@ -3,22 +3,38 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"github.com/golang-migrate/migrate/v4"
-	"github.com/robfig/cron/v3"
 	"log"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
 	"strconv"
+	"strings"
+	"syscall"
 	"time"
 	"weather-api/internal/core/domain"
 
 	"github.com/gin-gonic/gin"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	apispec "weather-api/api"
+	"weather-api/internal/adapter/captcha"
 	"weather-api/internal/adapter/email"
+	"weather-api/internal/adapter/metrics"
+	"weather-api/internal/adapter/ratelimit"
 	"weather-api/internal/adapter/repository/postgres"
+	"weather-api/internal/adapter/slack"
+	"weather-api/internal/adapter/telegram"
 	"weather-api/internal/adapter/weather"
+	"weather-api/internal/adapter/webhook"
+	"weather-api/internal/core/port"
 	"weather-api/internal/core/service"
 	httphandler "weather-api/internal/handler/http"
+	"weather-api/internal/handler/http/middleware"
 	"weather-api/internal/util"
 )
 
@@ -28,52 +44,308 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	logger, err := util.NewLogger(os.Stderr, cfg.LogFormat, cfg.LogLevel)
+	if err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+	slog.SetDefault(logger)
+
+	shutdownTracing, err := util.InitTracing(context.Background(), cfg.TracesExporter, cfg.TracesSampleRatio)
+	if err != nil {
+		fatal(logger, "Failed to initialize tracing", err)
+	}
+
 	db, err := sql.Open("postgres", cfg.DBConnStr)
 	if err != nil {
-		log.Fatalf("Failed to connect to DB: %v", err)
+		fatal(logger, "Failed to connect to DB", err)
 	}
 	defer db.Close()
 
 	m, err := migrate.New("file://migrations", cfg.DBConnStr)
 	if err != nil {
-		log.Fatalf("Failed to initialize migration: %v", err)
+		fatal(logger, "Failed to initialize migration", err)
 	}
 	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		log.Fatalf("Failed to apply migrations: %v", err)
+		fatal(logger, "Failed to apply migrations", err)
+	}
+
+	repo := postgres.NewSubscriptionRepo(db, logger)
+	appMetrics := metrics.New(repo, logger)
+
+	emailAdapter := email.NewSMTPEmailSender(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUser, cfg.SMTPPass, logger)
+	confirmationSender := metrics.NewEmailService(emailAdapter, appMetrics, metrics.EmailKindConfirmation)
+	updateSender := metrics.NewEmailService(emailAdapter, appMetrics, string(domain.DeliveryKindWeatherUpdate))
+
+	weatherClient, err := weather.NewHTTPClient(weather.ClientConfig{
+		Timeout:   cfg.WeatherAPITimeout,
+		UserAgent: cfg.WeatherAPIUserAgent,
+		ProxyURL:  cfg.WeatherAPIProxy,
+	})
+	if err != nil {
+		fatal(logger, "Failed to configure weather API client", err)
+	}
+	weatherAPI := weather.NewWeatherService(cfg.WeatherAPIBaseURL, cfg.WeatherAPIKey, weatherClient)
+	var weatherAdapter port.WeatherService = metrics.NewWeatherService(weatherAPI, appMetrics, "weatherapi")
+
+	var fallbackWeather port.WeatherService
+	switch cfg.WeatherFallbackProvider {
+	case "", "none":
+	case "openmeteo":
+		fallbackWeather = metrics.NewWeatherService(weather.NewOpenMeteoService(cfg.OpenMeteoGeocodingBaseURL, cfg.OpenMeteoForecastBaseURL, weatherClient), appMetrics, "openmeteo")
+	default:
+		fatal(logger, "Invalid weather fallback provider", fmt.Errorf("unknown provider %q", cfg.WeatherFallbackProvider))
+	}
+	if period := domain.QuotaPeriod(cfg.WeatherQuotaPeriod); period != domain.QuotaPeriodDaily && period != domain.QuotaPeriodMonthly {
+		fatal(logger, "Invalid weather quota period", fmt.Errorf("unknown period %q", cfg.WeatherQuotaPeriod))
+	}
+	quotaAlerts, err := service.ParseQuotaAlerts(cfg.WeatherQuotaAlerts)
+	if err != nil {
+		fatal(logger, "Invalid weather quota alerts", err)
 	}
+	quotaWeather := service.NewQuotaWeatherService(weatherAdapter, fallbackWeather, postgres.NewQuotaRepo(db, logger),
+		metrics.NewEmailService(emailAdapter, appMetrics, metrics.EmailKindQuotaAlert),
+		service.QuotaPolicy{
+			Provider:  "weatherapi",
+			Limit:     cfg.WeatherQuotaLimit,
+			Period:    domain.QuotaPeriod(cfg.WeatherQuotaPeriod),
+			DegradeAt: cfg.WeatherQuotaDegradeAt,
+			AlertAt:   quotaAlerts,
+			OpsEmail:  cfg.OpsEmail,
+		}, logger)
+	appMetrics.ObserveQuota("weatherapi", quotaWeather.CurrentUsage)
+	weatherAdapter = quotaWeather
 
-	emailAdapter := email.NewSMTPEmailSender(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUser, cfg.SMTPPass)
-	weatherAdapter := weather.NewWeatherService(cfg.WeatherAPIKey)
-	repo := postgres.NewSubscriptionRepo(db)
+	resilientWeather := service.NewResilientWeatherService(weatherAdapter,
+		service.RetryPolicy{MaxAttempts: cfg.WeatherRetryAttempts, BaseDelay: cfg.WeatherRetryBaseDelay, MaxDelay: cfg.WeatherRetryMaxDelay},
+		service.BreakerPolicy{FailureThreshold: cfg.WeatherBreakerFailures, OpenTimeout: cfg.WeatherBreakerOpenTimeout},
+		logger)
+	appMetrics.ObserveCircuit("weatherapi", resilientWeather.State)
+	weatherAdapter = resilientWeather
+	var forecasts port.ForecastService = metrics.NewForecastService(weatherAPI, appMetrics, "weatherapi")
+	forecasts = quotaWeather.Forecasts(forecasts)
+	forecasts = resilientWeather.Forecasts(forecasts)
+	forecasts = weather.NewCachedForecastService(forecasts, cfg.FeedForecastMaxAge, appMetrics)
+	observationRepo := postgres.NewObservationRepo(db, logger)
+	weatherAdapter = service.NewObservationRecorder(weatherAdapter, observationRepo, logger)
+	weatherAdapter = weather.NewCachedWeatherService(weatherAdapter, cfg.WeatherCacheTTL, appMetrics)
+	deliveryRepo := postgres.NewDeliveryRepo(db, logger)
+	jobRunRepo := postgres.NewJobRunRepo(db, logger)
 
-	weatherService := service.NewWeatherService(weatherAdapter)
+	var tokenSigner port.SignedTokenService
+	if cfg.TokenSigningKeys != "" {
+		keys, err := service.ParseSigningKeys(cfg.TokenSigningKeys)
+		if err != nil {
+			fatal(logger, "Invalid token signing keys", err)
+		}
+		tokenSigner, err = service.NewHMACTokenService(keys, cfg.ConfirmTokenTTL, cfg.UnsubscribeTokenTTL)
+		if err != nil {
+			fatal(logger, "Failed to initialize token signer", err)
+		}
+	}
+
+	weatherService := service.NewWeatherService(weatherAdapter, cfg.WeatherBatchMax, cfg.WeatherBatchConcurrency)
 	tokenService := service.NewTokenService()
-	subscriptionService := service.NewSubscriptionService(repo, weatherService, emailAdapter, tokenService)
-	emailService := service.NewEmailService(repo, weatherAdapter, emailAdapter)
+	var mxResolver port.MXResolver
+	if cfg.EmailMXCheck {
+		mxResolver = email.NewMXResolver(cfg.EmailMXTimeout)
+	}
+	rateLimits, err := service.ParseRateLimits(cfg.RateLimits)
+	if err != nil {
+		fatal(logger, "Invalid rate limits", err)
+	}
+	var rateLimitStore port.RateLimitStore
+	switch cfg.RateLimitStore {
+	case "postgres":
+		rateLimitStore = postgres.NewRateLimitRepo(db, logger)
+	case "memory":
+		rateLimitStore = ratelimit.NewMemoryStore()
+	default:
+		fatal(logger, "Invalid rate limit store", fmt.Errorf("unknown store %q", cfg.RateLimitStore))
+	}
+	rateLimiter := service.NewRateLimiter(rateLimitStore, rateLimits, logger)
+	chatClient := &http.Client{Timeout: cfg.WebhookTimeout}
+	slackSender := slack.NewClient(chatClient, cfg.SlackWebhookBaseURL)
+	subscriptionService := service.NewSubscriptionService(repo, weatherService, confirmationSender, tokenService, service.SubscriptionOptions{Signer: tokenSigner, MX: mxResolver, Limiter: rateLimiter, Slack: slackSender}, logger)
+	webhookChannel := service.NewWebhookChannel(postgres.NewWebhookRepo(db, logger),
+		webhook.NewSender(&http.Client{Timeout: cfg.WebhookTimeout, Transport: webhook.NewPublicTransport()}, cfg.WebhookUserAgent),
+		service.WebhookPolicy{
+			Retry:        service.RetryPolicy{MaxAttempts: cfg.WebhookRetryAttempts, BaseDelay: cfg.WebhookRetryBaseDelay, MaxDelay: cfg.WebhookRetryMaxDelay},
+			DisableAfter: cfg.WebhookDisableAfter,
+		}, logger)
+	channels := service.NotificationChannels{
+		domain.ChannelEmail:   service.NewEmailChannel(updateSender),
+		domain.ChannelWebhook: webhookChannel,
+		domain.ChannelSlack:   service.NewSlackChannel(slackSender),
+	}
+	var telegramHandler *httphandler.TelegramHandler
+	if cfg.TelegramBotToken != "" {
+		if cfg.TelegramWebhookSecret == "" {
+			fatal(logger, "Invalid Telegram bot", errors.New("TELEGRAM_WEBHOOK_SECRET is required with TELEGRAM_BOT_TOKEN"))
+		}
+		telegramClient := telegram.NewClient(cfg.TelegramAPIBaseURL, cfg.TelegramBotToken, chatClient)
+		channels[domain.ChannelTelegram] = service.NewTelegramChannel(telegramClient)
+		telegramHandler = httphandler.NewTelegramHandler(service.NewTelegramBot(subscriptionService, telegramClient, logger), cfg.TelegramWebhookSecret, logger)
+	}
+	emailService := service.NewEmailService(repo, deliveryRepo, weatherAdapter, channels, tokenSigner, logger)
+	adminService := service.NewAdminService(repo, deliveryRepo, logger)
+
+	apiKeys, err := service.ParseAPIKeys(cfg.AdminAPIKeys)
+	if err != nil {
+		fatal(logger, "Invalid admin API keys", err)
+	}
+	apiKeyService := service.NewAPIKeyService(apiKeys)
+
+	if len(os.Args) > 1 && os.Args[1] == "send-updates" {
+		code := runSendUpdatesCommand(emailService, os.Args[2:])
+		if err := shutdownTracing(context.Background()); err != nil {
+			logger.Error("Failed to flush traces", "error", err)
+		}
+		os.Exit(code)
+	}
+
+	scheduler := service.NewScheduler(jobRunRepo, cfg.SchedulerInstanceID, cfg.SchedulerLockWait, logger)
+	if err := scheduler.AddJob("hourly_updates", "0 * * * *", appMetrics.InstrumentJob("hourly_updates", func(ctx context.Context, run domain.JobRun) error {
+		return emailService.SendScheduledUpdates(ctx, domain.FrequencyHourly, run.ID)
+	})); err != nil {
+		fatal(logger, "Failed to schedule hourly updates", err)
+	}
+	if err := scheduler.AddJob("daily_updates", "0 0 * * *", appMetrics.InstrumentJob("daily_updates", func(ctx context.Context, run domain.JobRun) error {
+		return emailService.SendScheduledUpdates(ctx, domain.FrequencyDaily, run.ID)
+	})); err != nil {
+		fatal(logger, "Failed to schedule daily updates", err)
+	}
+	if err := scheduler.AddJob("prune_rate_limits", "30 * * * *", appMetrics.InstrumentJob("prune_rate_limits", func(ctx context.Context, run domain.JobRun) error {
+		return rateLimiter.Prune(ctx)
+	})); err != nil {
+		fatal(logger, "Failed to schedule rate limit pruning", err)
+	}
+	feedService := service.NewFeedService(weatherAdapter, observationRepo, forecasts, cfg.FeedHistorySize, cfg.FeedForecastDays, logger)
+	if err := scheduler.AddJob("prune_observations", "45 3 * * *", appMetrics.InstrumentJob("prune_observations", func(ctx context.Context, run domain.JobRun) error {
+		return feedService.PruneObservations(ctx, cfg.FeedHistoryRetention)
+	})); err != nil {
+		fatal(logger, "Failed to schedule observation pruning", err)
+	}
+
+	healthService := service.NewHealthService([]port.HealthChecker{
+		postgres.NewHealthChecker(db),
+		weather.NewHealthChecker(cfg.WeatherAPIBaseURL, weatherClient),
+		email.NewHealthChecker(cfg.SMTPHost, cfg.SMTPPort),
+	}, cfg.HealthCheckTimeout, cfg.HealthCheckCacheTTL, scheduler, jobRunRepo, logger)
 
 	weatherHandler := httphandler.NewWeatherHandler(weatherService)
-	subscriptionHandler := httphandler.NewSubscriptionHandler(subscriptionService)
+	weatherStreamer := service.NewWeatherStreamer(weatherAdapter, cfg.WeatherStreamPollInterval, cfg.WeatherStreamMax, logger)
+	weatherStreamHandler := httphandler.NewWeatherStreamHandler(weatherStreamer, cfg.WeatherStreamHeartbeat, logger)
+	feedHandler := httphandler.NewFeedHandler(feedService, cfg.FeedForecastMaxAge)
+	var botVerifiers service.BotVerifiers
+	var powVerifier *service.ProofOfWorkVerifier
+	for _, name := range strings.Split(cfg.BotProtection, ",") {
+		switch strings.TrimSpace(name) {
+		case "", "none":
+		case "honeypot":
+			botVerifiers = append(botVerifiers, service.HoneypotVerifier{})
+		case "pow":
+			if len(cfg.PowSecret) < 32 {
+				fatal(logger, "Invalid bot protection", errors.New("POW_SECRET must be at least 32 bytes long"))
+			}
+			powVerifier = service.NewProofOfWorkVerifier([]byte(cfg.PowSecret), cfg.PowDifficulty, cfg.PowChallengeTTL)
+			botVerifiers = append(botVerifiers, powVerifier)
+		case "captcha":
+			botVerifiers = append(botVerifiers, captcha.NewVerifier(cfg.CaptchaVerifyURL, cfg.CaptchaSecret, &http.Client{Timeout: 5 * time.Second}))
+		case "captcha_stub":
+			botVerifiers = append(botVerifiers, captcha.NewStubVerifier())
+		default:
+			fatal(logger, "Invalid bot protection", fmt.Errorf("unknown check %q", name))
+		}
+	}
 
-	r := gin.Default()
+	subscriptionHandler := httphandler.NewSubscriptionHandler(subscriptionService, botVerifiers, logger)
+	subscriptionResourceHandler := httphandler.NewSubscriptionResourceHandler(subscriptionService, adminService, webhookChannel, botVerifiers, logger)
+	runService := service.NewRunService(scheduler, emailService, repo, jobRunRepo, logger)
+	adminHandler := httphandler.NewAdminHandler(adminService, subscriptionService, runService, quotaWeather, logger)
+	healthHandler := httphandler.NewHealthHandler(healthService)
+
+	r := gin.New()
+	if cfg.TrustedProxies != "" {
+		if err := r.SetTrustedProxies(strings.Split(cfg.TrustedProxies, ",")); err != nil {
+			fatal(logger, "Invalid trusted proxies", err)
+		}
+	}
+	// Lets services read the request id and cancellation from the request context through *gin.Context.
+	r.ContextWithFallback = true
+	r.Use(otelgin.Middleware(util.ServiceName, otelgin.WithFilter(isTracedRequest)), gin.Recovery(), middleware.RequestID(), middleware.Logger(logger), middleware.Metrics(appMetrics))
+
+	r.GET("/metrics", gin.WrapH(appMetrics.Handler()))
+	r.GET("/healthz", healthHandler.Liveness)
+	r.GET("/readyz", healthHandler.Readiness)
 
 	r.Static("/web", "./web")
 
-	api := r.Group("/api")
+	apiSpec, err := apispec.LoadSpec()
+	if err != nil {
+		fatal(logger, "Invalid OpenAPI spec", err)
+	}
+	openAPIHandler, err := httphandler.NewOpenAPIHandler(apiSpec)
+	if err != nil {
+		fatal(logger, "Failed to render OpenAPI spec", err)
+	}
+	validateRequest, err := middleware.ValidateRequest(apiSpec, logger)
+	if err != nil {
+		fatal(logger, "Failed to build OpenAPI request validator", err)
+	}
+
+	r.GET("/api/openapi.json", openAPIHandler.Spec)
+	r.GET("/api/docs", openAPIHandler.SwaggerUI)
+	challengeHandler := httphandler.NewChallengeHandler(powVerifier)
+	r.GET("/api/challenge", middleware.RateLimitByIP(rateLimiter, "challenge_ip"), challengeHandler.IssueChallenge)
+
+	requireRead := middleware.RequireScope(apiKeyService, domain.ScopeRead, logger)
+	requireWrite := middleware.RequireScope(apiKeyService, domain.ScopeWrite, logger)
+	requireTrigger := middleware.RequireScope(apiKeyService, domain.ScopeTrigger, logger)
+
+	api := r.Group("/api", validateRequest)
 	{
-		api.GET("/weather", weatherHandler.GetWeather)
-		api.POST("/subscribe", subscriptionHandler.Subscribe)
-		api.GET("/confirm/:token", subscriptionHandler.Confirm)
-		api.GET("/unsubscribe/:token", subscriptionHandler.Unsubscribe)
+		api.GET("/weather", middleware.RateLimitByIP(rateLimiter, "weather_ip"), weatherHandler.GetWeather)
+		api.GET("/weather/batch", middleware.RateLimitByIP(rateLimiter, "weather_batch_ip"), weatherHandler.GetWeatherBatch)
+		api.POST("/weather/batch", middleware.RateLimitByIP(rateLimiter, "weather_batch_ip"), weatherHandler.GetWeatherBatch)
+		api.GET("/weather/stream", middleware.RateLimitByIP(rateLimiter, "weather_ip"), weatherStreamHandler.Stream)
+		api.GET("/feeds/:file", middleware.RateLimitByIP(rateLimiter, "weather_ip"), feedHandler.GetFeed)
+		api.POST("/subscribe", middleware.RateLimitByIP(rateLimiter, "subscribe_ip"), subscriptionHandler.Subscribe)
+		api.GET("/confirm/:token", middleware.RateLimitByIP(rateLimiter, "token_ip"), subscriptionHandler.Confirm)
+		api.GET("/unsubscribe/:token", middleware.RateLimitByIP(rateLimiter, "token_ip"), subscriptionHandler.Unsubscribe)
+		if telegramHandler != nil {
+			api.POST("/telegram/webhook", telegramHandler.Webhook)
+		}
 	}
 
-	r.NoRoute(func(c *gin.Context) {
-		c.File("./web/index.html")
-	})
+	v2 := api.Group("/v2")
+	{
+		tokenLimit := middleware.RateLimitByIP(rateLimiter, "token_ip")
+		v2.POST("/subscriptions", middleware.RateLimitByIP(rateLimiter, "subscribe_ip"), subscriptionResourceHandler.Create)
+		v2.GET("/subscriptions", requireRead, subscriptionResourceHandler.List)
+		v2.GET("/subscriptions/:id", tokenLimit, subscriptionResourceHandler.Get)
+		v2.PATCH("/subscriptions/:id", tokenLimit, subscriptionResourceHandler.Update)
+		v2.DELETE("/subscriptions/:id", tokenLimit, subscriptionResourceHandler.Delete)
+		v2.POST("/subscriptions/:id/confirmations", tokenLimit, subscriptionResourceHandler.Confirm)
+		v2.POST("/subscriptions/:id/pings", tokenLimit, subscriptionResourceHandler.Ping)
+	}
+	admin := r.Group("/admin")
+	{
+		admin.GET("/subscriptions", requireRead, adminHandler.ListSubscriptions)
+		admin.GET("/subscriptions/:id", requireRead, adminHandler.GetSubscription)
+		admin.POST("/subscriptions/:id/confirm", requireWrite, adminHandler.ConfirmSubscription)
+		admin.POST("/subscriptions/:id/resend-confirmation", requireWrite, adminHandler.ResendConfirmation)
+		admin.DELETE("/subscriptions/:id", requireWrite, adminHandler.DeleteSubscription)
+		admin.GET("/stats", requireRead, adminHandler.GetStats)
+		admin.GET("/status", requireRead, healthHandler.Status)
+		admin.GET("/quota", requireRead, adminHandler.GetQuota)
+		admin.POST("/runs", requireTrigger, adminHandler.TriggerRun)
+		admin.GET("/runs/:id", requireTrigger, adminHandler.GetRun)
+	}
 
-	cron := cron.New()
-	cron.AddFunc("0 * * * *", func() { emailService.SendUpdates(context.Background(), domain.FrequencyHourly) })
-	cron.AddFunc("0 0 * * *", func() { emailService.SendUpdates(context.Background(), domain.FrequencyDaily) })
-	cron.Start()
+	r.NoRoute(httphandler.NoRoute("./web/index.html"))
+
+	scheduler.Start()
+	go scheduler.CatchUp(context.Background(), cfg.SchedulerCatchUp)
 
 	port := strconv.Itoa(cfg.Port)
 
@@ -84,8 +356,53 @@ func main() {
 		WriteTimeout: 10 * time.Second,
 	}
 
-	log.Printf("Server running on %s", srv.Addr)
-	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		log.Fatalf("Server failed: %v", err)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		logger.Info("Server running", "addr", srv.Addr)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			fatal(logger, "Server failed", err)
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+	logger.Info("Shutting down")
+
+	httpCtx, cancelHTTP := context.WithTimeout(context.Background(), cfg.ShutdownHTTPTimeout)
+	defer cancelHTTP()
+	// Open streams would otherwise keep Shutdown waiting until it times out.
+	weatherStreamer.Close()
+	if err := srv.Shutdown(httpCtx); err != nil {
+		logger.Error("HTTP server did not shut down cleanly", "error", err)
+	}
+
+	jobsCtx, cancelJobs := context.WithTimeout(context.Background(), cfg.ShutdownJobsTimeout)
+	defer cancelJobs()
+	if err := scheduler.Shutdown(jobsCtx, cfg.ShutdownInterruptTimeout); err != nil {
+		logger.Error("Scheduler did not shut down cleanly", "error", err)
 	}
+
+	tracesCtx, cancelTraces := context.WithTimeout(context.Background(), cfg.ShutdownHTTPTimeout)
+	defer cancelTraces()
+	if err := shutdownTracing(tracesCtx); err != nil {
+		logger.Error("Failed to flush traces", "error", err)
+	}
+
+	logger.Info("Shutdown complete")
+}
+
+// isTracedRequest leaves probes and metric scrapes out of traces.
+func isTracedRequest(r *http.Request) bool {
+	switch r.URL.Path {
+	case "/healthz", "/readyz", "/metrics":
+		return false
+	}
+	return true
+}
+
+func fatal(logger *slog.Logger, msg string, err error) {
+	logger.Error(msg, "error", err)
+	os.Exit(1)
 }
@@ -0,0 +1,81 @@
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/port"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// Verifier checks CAPTCHA response tokens with the provider's siteverify endpoint. reCAPTCHA,
+// hCaptcha and Cloudflare Turnstile all accept the same form-encoded request.
+type Verifier struct {
+	verifyURL string
+	secret    string
+	client    *http.Client
+}
+
+func NewVerifier(verifyURL, secret string, client *http.Client) port.BotVerifier {
+	traced := *client
+	traced.Transport = otelhttp.NewTransport(client.Transport)
+	return &Verifier{verifyURL: verifyURL, secret: secret, client: &traced}
+}
+
+func (v *Verifier) Verify(ctx context.Context, check domain.BotCheck) error {
+	if check.CaptchaToken == "" {
+		return domain.ErrBotDetected
+	}
+
+	form := url.Values{}
+	form.Set("secret", v.secret)
+	form.Set("response", check.CaptchaToken)
+	if check.ClientIP != "" {
+		form.Set("remoteip", check.ClientIP)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("verifying captcha: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("captcha provider responded with %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Success bool `json:"success"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&result); err != nil {
+		return fmt.Errorf("decoding captcha response: %w", err)
+	}
+	if !result.Success {
+		return domain.ErrBotDetected
+	}
+	return nil
+}
+
+// StubVerifier accepts any non-empty token, for local development and tests.
+type StubVerifier struct{}
+
+func NewStubVerifier() port.BotVerifier {
+	return StubVerifier{}
+}
+
+func (StubVerifier) Verify(_ context.Context, check domain.BotCheck) error {
+	if check.CaptchaToken == "" {
+		return domain.ErrBotDetected
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package captcha
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"weather-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifier_SendsForm(t *testing.T) {
+	tests := []struct {
+		name         string
+		check        domain.BotCheck
+		expectedForm map[string][]string
+	}{
+		{
+			name:         "with client IP",
+			check:        domain.BotCheck{CaptchaToken: "captcha-token", ClientIP: "192.0.2.1"},
+			expectedForm: map[string][]string{"secret": {"captcha-secret"}, "response": {"captcha-token"}, "remoteip": {"192.0.2.1"}},
+		},
+		{
+			name:         "without client IP",
+			check:        domain.BotCheck{CaptchaToken: "captcha-token"},
+			expectedForm: map[string][]string{"secret": {"captcha-secret"}, "response": {"captcha-token"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received *http.Request
+			provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.NoError(t, r.ParseForm())
+				received = r
+				_, _ = w.Write([]byte(`{"success":true,"hostname":"example.com"}`))
+			}))
+			defer provider.Close()
+
+			err := NewVerifier(provider.URL+"/siteverify", "captcha-secret", provider.Client()).Verify(context.Background(), tt.check)
+
+			require.NoError(t, err)
+			assert.Equal(t, http.MethodPost, received.Method)
+			assert.Equal(t, "/siteverify", received.URL.Path)
+			assert.Equal(t, "application/x-www-form-urlencoded", received.Header.Get("Content-Type"))
+			assert.Equal(t, tt.expectedForm, map[string][]string(received.PostForm))
+		})
+	}
+}
+
+func TestVerifier_Failures(t *testing.T) {
+	tests := []struct {
+		name        string
+		handler     http.HandlerFunc
+		expectedBot bool
+		expectedErr string
+	}{
+		{
+			name: "rejected token",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"success":false,"error-codes":["invalid-input-response"]}`))
+			},
+			expectedBot: true,
+		},
+		{
+			name: "provider error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+			expectedErr: "captcha provider responded with 500",
+		},
+		{
+			name: "invalid JSON",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`<html>`))
+			},
+			expectedErr: "decoding captcha response: invalid character '<' looking for beginning of value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := httptest.NewServer(tt.handler)
+			defer provider.Close()
+
+			err := NewVerifier(provider.URL, "captcha-secret", provider.Client()).Verify(context.Background(), domain.BotCheck{CaptchaToken: "captcha-token"})
+
+			if tt.expectedBot {
+				assert.ErrorIs(t, err, domain.ErrBotDetected)
+			} else {
+				// Provider failures are not the client's fault, so they mustn't read as a bot.
+				assert.EqualError(t, err, tt.expectedErr)
+				assert.NotErrorIs(t, err, domain.ErrBotDetected)
+			}
+		})
+	}
+}
+
+func TestVerifier_RejectsMissingTokenWithoutCallingProvider(t *testing.T) {
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("provider was called")
+	}))
+	defer provider.Close()
+
+	err := NewVerifier(provider.URL, "captcha-secret", provider.Client()).Verify(context.Background(), domain.BotCheck{ClientIP: "192.0.2.1"})
+
+	assert.ErrorIs(t, err, domain.ErrBotDetected)
+}
+
+func TestStubVerifier(t *testing.T) {
+	assert.NoError(t, NewStubVerifier().Verify(context.Background(), domain.BotCheck{CaptchaToken: "anything"}))
+	assert.ErrorIs(t, NewStubVerifier().Verify(context.Background(), domain.BotCheck{}), domain.ErrBotDetected)
+}
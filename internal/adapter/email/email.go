@@ -2,7 +2,7 @@ package email
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"net/smtp"
 
 	"github.com/jordan-wright/email"
@@ -14,14 +14,16 @@ type SMTPEmailSender struct {
 	port int
 	user string
 	pass string
+
+	logger *slog.Logger
 }
 
-func NewSMTPEmailSender(host string, port int, user, pass string) port.EmailService {
-	return &SMTPEmailSender{host: host, port: port, user: user, pass: pass}
+func NewSMTPEmailSender(host string, port int, user, pass string, logger *slog.Logger) port.EmailService {
+	return &SMTPEmailSender{host: host, port: port, user: user, pass: pass, logger: logger}
 }
 
 func (e *SMTPEmailSender) SendEmail(to, subject, body string) error {
-	log.Printf("Attempting to send email to: %s, subject: %s, from: %s", to, subject, e.user)
+	e.logger.Debug("Attempting to send email", "email", to, "subject", subject)
 
 	msg := email.NewEmail()
 	msg.From = e.user
@@ -31,10 +33,10 @@ func (e *SMTPEmailSender) SendEmail(to, subject, body string) error {
 
 	err := msg.Send(fmt.Sprintf("%s:%d", e.host, e.port), smtp.PlainAuth("", e.user, e.pass, e.host))
 	if err != nil {
-		log.Printf("Failed to send email to %s: %v", to, err)
+		e.logger.Error("Failed to send email", "email", to, "error", err)
 		return err
 	}
 
-	log.Printf("Successfully sent email to: %s", to)
+	e.logger.Debug("Successfully sent email", "email", to)
 	return nil
 }
@@ -0,0 +1,50 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"weather-api/internal/core/port"
+)
+
+// HealthChecker performs an SMTP handshake without authenticating or sending mail.
+type HealthChecker struct {
+	host string
+	port int
+}
+
+func NewHealthChecker(host string, port int) port.HealthChecker {
+	return &HealthChecker{host: host, port: port}
+}
+
+func (h *HealthChecker) Name() string {
+	return "smtp"
+}
+
+func (h *HealthChecker) Check(ctx context.Context) (map[string]any, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", fmt.Sprintf("%s:%d", h.host, h.port))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, h.host)
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	if err := client.Hello("localhost"); err != nil {
+		return nil, err
+	}
+	starttls, _ := client.Extension("STARTTLS")
+	if err := client.Quit(); err != nil {
+		return nil, err
+	}
+	return map[string]any{"starttls": starttls}, nil
+}
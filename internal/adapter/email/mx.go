@@ -0,0 +1,48 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+	"weather-api/internal/core/port"
+)
+
+type MXResolver struct {
+	resolver *net.Resolver
+	timeout  time.Duration
+}
+
+func NewMXResolver(timeout time.Duration) port.MXResolver {
+	return &MXResolver{resolver: net.DefaultResolver, timeout: timeout}
+}
+
+// AcceptsMail looks up the domain's MX records and, as RFC 5321 allows, falls back to its
+// address records when there are none.
+func (r *MXResolver) AcceptsMail(ctx context.Context, domain string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	records, err := r.resolver.LookupMX(ctx, domain)
+	if err == nil && len(records) > 0 {
+		// A single "." record is a null MX: the domain explicitly accepts no mail.
+		return !(len(records) == 1 && records[0].Host == "."), nil
+	}
+	if err != nil && !isNotFound(err) {
+		return false, err
+	}
+
+	addrs, err := r.resolver.LookupHost(ctx, domain)
+	if err != nil {
+		if isNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return len(addrs) > 0, nil
+}
+
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
@@ -0,0 +1,29 @@
+package metrics
+
+import "weather-api/internal/core/port"
+
+const (
+	EmailKindConfirmation = "confirmation"
+	EmailKindQuotaAlert   = "quota_alert"
+)
+
+type EmailService struct {
+	next    port.EmailService
+	metrics *Metrics
+	kind    string
+}
+
+// NewEmailService counts emails sent through next under the given kind, e.g. "confirmation".
+func NewEmailService(next port.EmailService, metrics *Metrics, kind string) port.EmailService {
+	return &EmailService{next: next, metrics: metrics, kind: kind}
+}
+
+func (e *EmailService) SendEmail(to, subject, body string) error {
+	err := e.next.SendEmail(to, subject, body)
+	result := "sent"
+	if err != nil {
+		result = "failed"
+	}
+	e.metrics.emails.WithLabelValues(e.kind, result).Inc()
+	return err
+}
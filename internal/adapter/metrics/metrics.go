@@ -0,0 +1,139 @@
+package metrics
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/port"
+)
+
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests    *prometheus.CounterVec
+	httpDuration    *prometheus.HistogramVec
+	weatherDuration *prometheus.HistogramVec
+	weatherErrors   *prometheus.CounterVec
+	weatherCache    *prometheus.CounterVec
+	emails          *prometheus.CounterVec
+	jobRuns         *prometheus.CounterVec
+	jobDuration     *prometheus.HistogramVec
+	jobLag          *prometheus.HistogramVec
+}
+
+func New(repo port.SubscriptionRepository, logger *slog.Logger) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "HTTP requests by method, route and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency by method, route and status code.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		weatherDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "weather_provider_request_duration_seconds",
+			Help:    "Weather provider request latency.",
+			Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		}, []string{"provider", "result"}),
+		weatherErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "weather_provider_errors_total",
+			Help: "Failed weather provider requests by error kind.",
+		}, []string{"provider", "kind"}),
+		weatherCache: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "weather_cache_requests_total",
+			Help: "Weather cache lookups by result (hit or miss).",
+		}, []string{"result"}),
+		emails: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "emails_total",
+			Help: "Emails by kind and result (sent or failed).",
+		}, []string{"kind", "result"}),
+		jobRuns: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "scheduler_runs_total",
+			Help: "Scheduled job runs executed by this instance, by job and result.",
+		}, []string{"job", "result"}),
+		jobDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "scheduler_run_duration_seconds",
+			Help:    "Duration of scheduled job runs.",
+			Buckets: []float64{1, 5, 15, 30, 60, 120, 300, 600, 1800},
+		}, []string{"job"}),
+		jobLag: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "scheduler_run_lag_seconds",
+			Help:    "Delay between the scheduled time of a run and its start.",
+			Buckets: []float64{0.1, 0.5, 1, 5, 15, 60, 300, 900, 3600},
+		}, []string{"job"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests, m.httpDuration,
+		m.weatherDuration, m.weatherErrors, m.weatherCache,
+		m.emails,
+		m.jobRuns, m.jobDuration, m.jobLag,
+		newSubscriptionCollector(repo, logger),
+	)
+	return m
+}
+
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+func (m *Metrics) ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	code := strconv.Itoa(status)
+	m.httpRequests.WithLabelValues(method, route, code).Inc()
+	m.httpDuration.WithLabelValues(method, route, code).Observe(duration.Seconds())
+}
+
+func (m *Metrics) ObserveCache(hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.weatherCache.WithLabelValues(result).Inc()
+}
+
+// ObserveCircuit exposes the state of a provider's circuit breaker, one series per state set to 0 or 1.
+func (m *Metrics) ObserveCircuit(provider string, state func() domain.CircuitState) {
+	for _, s := range []domain.CircuitState{domain.CircuitClosed, domain.CircuitOpen, domain.CircuitHalfOpen} {
+		m.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name:        "weather_provider_circuit_state",
+			Help:        "Circuit breaker state of the weather provider.",
+			ConstLabels: prometheus.Labels{"provider": provider, "state": string(s)},
+		}, func() float64 {
+			if state() == s {
+				return 1
+			}
+			return 0
+		}))
+	}
+}
+
+// ObserveQuota exports the provider's calls in the current quota period and its limit.
+func (m *Metrics) ObserveQuota(provider string, usage func() (calls, limit int)) {
+	m.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name:        "weather_provider_quota_used",
+		Help:        "Calls made to the weather provider in the current quota period.",
+		ConstLabels: prometheus.Labels{"provider": provider},
+	}, func() float64 {
+		calls, _ := usage()
+		return float64(calls)
+	}))
+	m.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name:        "weather_provider_quota_limit",
+		Help:        "Calls allowed to the weather provider per quota period, 0 if unlimited.",
+		ConstLabels: prometheus.Labels{"provider": provider},
+	}, func() float64 {
+		_, limit := usage()
+		return float64(limit)
+	}))
+}
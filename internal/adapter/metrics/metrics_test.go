@@ -0,0 +1,103 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/mocks"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMetrics() *Metrics {
+	return New(&mocks.MockSubscriptionRepository{}, slog.New(slog.DiscardHandler))
+}
+
+func TestWeatherService_CountsResults(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedResult string
+		expectedKind   string
+	}{
+		{name: "success", expectedResult: "success"},
+		{name: "unknown city", err: domain.ErrCityNotFound, expectedResult: "not_found"},
+		{name: "provider unavailable", err: &domain.ProviderUnavailableError{}, expectedResult: "error", expectedKind: "unavailable"},
+		{name: "other error", err: errors.New("API error: bad response"), expectedResult: "error", expectedKind: "upstream"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestMetrics()
+			next := &mocks.MockWeatherService{}
+			next.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{}, tt.err)
+
+			_, err := NewWeatherService(next, m, "weatherapi").GetWeather(context.Background(), "Kyiv")
+
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, 1, testutil.CollectAndCount(m.weatherDuration))
+			assert.Equal(t, uint64(1), sampleCount(t, m.weatherDuration.WithLabelValues("weatherapi", tt.expectedResult)))
+			if tt.expectedKind == "" {
+				assert.Equal(t, 0, testutil.CollectAndCount(m.weatherErrors))
+			} else {
+				assert.Equal(t, 1.0, testutil.ToFloat64(m.weatherErrors.WithLabelValues("weatherapi", tt.expectedKind)))
+			}
+		})
+	}
+}
+
+func TestForecastService_CountsResults(t *testing.T) {
+	m := newTestMetrics()
+	next := &mocks.MockForecastService{}
+	next.On("GetForecast", mock.Anything, "Kyiv", 3).Return([]domain.DailyForecast{{Description: "Sunny"}}, nil).Once()
+	next.On("GetForecast", mock.Anything, "Kyiv", 3).Return([]domain.DailyForecast(nil), &domain.ProviderUnavailableError{}).Once()
+	forecasts := NewForecastService(next, m, "weatherapi")
+
+	_, err := forecasts.GetForecast(context.Background(), "Kyiv", 3)
+	require.NoError(t, err)
+	_, err = forecasts.GetForecast(context.Background(), "Kyiv", 3)
+	require.Error(t, err)
+
+	assert.Equal(t, uint64(1), sampleCount(t, m.weatherDuration.WithLabelValues("weatherapi", "success")))
+	assert.Equal(t, uint64(1), sampleCount(t, m.weatherDuration.WithLabelValues("weatherapi", "error")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.weatherErrors.WithLabelValues("weatherapi", "unavailable")))
+}
+
+func sampleCount(t *testing.T, observer prometheus.Observer) uint64 {
+	t.Helper()
+	var metric dto.Metric
+	require.NoError(t, observer.(prometheus.Metric).Write(&metric))
+	return metric.GetHistogram().GetSampleCount()
+}
+
+func TestEmailService_CountsResults(t *testing.T) {
+	m := newTestMetrics()
+	next := &mocks.MockEmailService{}
+	next.On("SendEmail", "user1@example.com", "Confirm Subscription", "body").Return(nil).Once()
+	next.On("SendEmail", "user1@example.com", "Confirm Subscription", "body").Return(errors.New("smtp: connection refused")).Once()
+	emails := NewEmailService(next, m, EmailKindConfirmation)
+
+	assert.NoError(t, emails.SendEmail("user1@example.com", "Confirm Subscription", "body"))
+	assert.Error(t, emails.SendEmail("user1@example.com", "Confirm Subscription", "body"))
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.emails.WithLabelValues(EmailKindConfirmation, "sent")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.emails.WithLabelValues(EmailKindConfirmation, "failed")))
+}
+
+func TestMetrics_ObserveCache(t *testing.T) {
+	m := newTestMetrics()
+
+	m.ObserveCache(true)
+	m.ObserveCache(false)
+	m.ObserveCache(false)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.weatherCache.WithLabelValues("hit")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.weatherCache.WithLabelValues("miss")))
+}
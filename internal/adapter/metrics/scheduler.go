@@ -0,0 +1,28 @@
+package metrics
+
+import (
+	"context"
+	"time"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/service"
+)
+
+// InstrumentJob records the duration, result and start lag of every run of fn.
+func (m *Metrics) InstrumentJob(name string, fn service.JobFunc) service.JobFunc {
+	return func(ctx context.Context, run domain.JobRun) error {
+		start := time.Now()
+		if !run.ScheduledTime.IsZero() {
+			m.jobLag.WithLabelValues(name).Observe(start.Sub(run.ScheduledTime).Seconds())
+		}
+
+		err := fn(ctx, run)
+
+		result := "completed"
+		if err != nil {
+			result = "failed"
+		}
+		m.jobRuns.WithLabelValues(name, result).Inc()
+		m.jobDuration.WithLabelValues(name).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"weather-api/internal/core/port"
+)
+
+const collectTimeout = 5 * time.Second
+
+// subscriptionCollector reads subscription counts from the database at scrape time.
+type subscriptionCollector struct {
+	repo   port.SubscriptionRepository
+	logger *slog.Logger
+	desc   *prometheus.Desc
+}
+
+func newSubscriptionCollector(repo port.SubscriptionRepository, logger *slog.Logger) prometheus.Collector {
+	return &subscriptionCollector{
+		repo:   repo,
+		logger: logger,
+		desc:   prometheus.NewDesc("subscriptions", "Subscriptions by frequency and state.", []string{"frequency", "state"}, nil),
+	}
+}
+
+func (c *subscriptionCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *subscriptionCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+
+	counts, err := c.repo.GetSubscriptionCounts(ctx)
+	if err != nil {
+		c.logger.ErrorContext(ctx, "Failed to collect subscription metrics", "error", err)
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+	for _, count := range counts {
+		state := "pending"
+		if count.IsConfirmed {
+			state = "confirmed"
+		}
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(count.Count), string(count.Frequency), state)
+	}
+}
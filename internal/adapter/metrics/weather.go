@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/port"
+)
+
+type WeatherService struct {
+	next     port.WeatherService
+	metrics  *Metrics
+	provider string
+}
+
+func NewWeatherService(next port.WeatherService, metrics *Metrics, provider string) port.WeatherService {
+	return &WeatherService{next: next, metrics: metrics, provider: provider}
+}
+
+func (w *WeatherService) GetWeather(ctx context.Context, city string) (domain.Weather, error) {
+	start := time.Now()
+	weather, err := w.next.GetWeather(ctx, city)
+	w.metrics.observeWeather(w.provider, start, err)
+	return weather, err
+}
+
+// ForecastService counts forecast lookups with the same provider metrics as WeatherService.
+type ForecastService struct {
+	next     port.ForecastService
+	metrics  *Metrics
+	provider string
+}
+
+func NewForecastService(next port.ForecastService, metrics *Metrics, provider string) port.ForecastService {
+	return &ForecastService{next: next, metrics: metrics, provider: provider}
+}
+
+func (f *ForecastService) GetForecast(ctx context.Context, city string, days int) ([]domain.DailyForecast, error) {
+	start := time.Now()
+	forecast, err := f.next.GetForecast(ctx, city, days)
+	f.metrics.observeWeather(f.provider, start, err)
+	return forecast, err
+}
+
+func (m *Metrics) observeWeather(provider string, start time.Time, err error) {
+	result := "success"
+	switch {
+	case errors.Is(err, domain.ErrCityNotFound):
+		result = "not_found"
+	case errors.Is(err, domain.ErrProviderUnavailable):
+		result = "error"
+		m.weatherErrors.WithLabelValues(provider, "unavailable").Inc()
+	case err != nil:
+		result = "error"
+		m.weatherErrors.WithLabelValues(provider, "upstream").Inc()
+	}
+	m.weatherDuration.WithLabelValues(provider, result).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,47 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/port"
+)
+
+// MemoryStore keeps buckets in process, so limits apply per instance.
+type MemoryStore struct {
+	now func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]domain.TokenBucket
+}
+
+func NewMemoryStore() port.RateLimitStore {
+	return &MemoryStore{now: time.Now, buckets: map[string]domain.TokenBucket{}}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit domain.RateLimit) (domain.RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = limit.NewTokenBucket(now)
+	}
+	bucket, result := limit.Take(bucket, now)
+	s.buckets[key] = bucket
+	return result, nil
+}
+
+func (s *MemoryStore) Prune(_ context.Context, before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, bucket := range s.buckets {
+		if bucket.UpdatedAt.Before(before) {
+			delete(s.buckets, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+	"weather-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore_Take(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore().(*MemoryStore)
+	store.now = func() time.Time { return now }
+	limit := domain.RateLimit{Limit: 2, Period: time.Minute}
+
+	take := func(key string) domain.RateLimitResult {
+		result, err := store.Take(ctx, key, limit)
+		require.NoError(t, err)
+		return result
+	}
+
+	assert.Equal(t, domain.RateLimitResult{Allowed: true, Limit: 2, Remaining: 1, Reset: 30 * time.Second}, take("weather_ip:192.0.2.1"))
+	assert.Equal(t, domain.RateLimitResult{Allowed: true, Limit: 2, Remaining: 0, Reset: time.Minute}, take("weather_ip:192.0.2.1"))
+	assert.Equal(t, domain.RateLimitResult{Limit: 2, Remaining: 0, Reset: time.Minute, RetryAfter: 30 * time.Second}, take("weather_ip:192.0.2.1"))
+	// Keys have buckets of their own.
+	assert.True(t, take("weather_ip:192.0.2.2").Allowed)
+
+	// A token is refilled every 30 seconds.
+	now = now.Add(30 * time.Second)
+	assert.Equal(t, domain.RateLimitResult{Allowed: true, Limit: 2, Remaining: 0, Reset: time.Minute}, take("weather_ip:192.0.2.1"))
+}
+
+func TestMemoryStore_Prune(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore().(*MemoryStore)
+	store.now = func() time.Time { return now }
+	limit := domain.RateLimit{Limit: 2, Period: time.Minute}
+
+	_, err := store.Take(ctx, "old", limit)
+	require.NoError(t, err)
+	now = now.Add(time.Hour)
+	_, err = store.Take(ctx, "recent", limit)
+	require.NoError(t, err)
+
+	require.NoError(t, store.Prune(ctx, now.Add(-time.Minute)))
+
+	assert.NotContains(t, store.buckets, "old")
+	assert.Contains(t, store.buckets, "recent")
+}
@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/port"
+)
+
+type DeliveryRepo struct {
+	db     tracedDB
+	logger *slog.Logger
+}
+
+func NewDeliveryRepo(db *sql.DB, logger *slog.Logger) port.DeliveryRepository {
+	return &DeliveryRepo{db: tracedDB{DB: db, table: "deliveries"}, logger: logger}
+}
+
+func (r *DeliveryRepo) RecordDelivery(ctx context.Context, delivery domain.Delivery) error {
+	query := `INSERT INTO deliveries (job_run_id, subscription_id, kind, status, error) VALUES (NULLIF($1::integer, 0), $2, $3, $4, $5)`
+	_, err := r.db.ExecContext(ctx, query, delivery.RunID, delivery.SubscriptionID, delivery.Kind, delivery.Status, delivery.Error)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to record delivery", "subscription_id", delivery.SubscriptionID, "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *DeliveryRepo) GetDeliveryStats(ctx context.Context, since time.Time) ([]domain.DeliveryStats, error) {
+	query := `SELECT kind, status, COUNT(*), MAX(created_at) FROM deliveries WHERE created_at >= $1 GROUP BY kind, status ORDER BY kind, status`
+	rows, err := r.db.QueryContext(ctx, query, since)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to query delivery stats", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := []domain.DeliveryStats{}
+	for rows.Next() {
+		var s domain.DeliveryStats
+		if err := rows.Scan(&s.Kind, &s.Status, &s.Count, &s.LastAt); err != nil {
+			r.logger.ErrorContext(ctx, "Error scanning delivery stats row", "error", err)
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
+}
+
+func (r *DeliveryRepo) GetDeliveredSubscriptionIDs(ctx context.Context, runID int) (map[int]bool, error) {
+	query := `SELECT DISTINCT subscription_id FROM deliveries WHERE job_run_id = $1 AND status = $2`
+	rows, err := r.db.QueryContext(ctx, query, runID, domain.DeliveryStatusSent)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to query deliveries of run", "run_id", runID, "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	delivered := map[int]bool{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		delivered[id] = true
+	}
+	return delivered, rows.Err()
+}
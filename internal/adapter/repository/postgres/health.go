@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"weather-api/internal/core/port"
+)
+
+type HealthChecker struct {
+	db *sql.DB
+}
+
+func NewHealthChecker(db *sql.DB) port.HealthChecker {
+	return &HealthChecker{db: db}
+}
+
+func (h *HealthChecker) Name() string {
+	return "postgres"
+}
+
+func (h *HealthChecker) Check(ctx context.Context) (map[string]any, error) {
+	if err := h.db.PingContext(ctx); err != nil {
+		return nil, err
+	}
+
+	var version int
+	var dirty bool
+	if err := h.db.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty); err != nil {
+		return nil, fmt.Errorf("reading migration version: %w", err)
+	}
+	details := map[string]any{"migration_version": version, "migration_dirty": dirty}
+	if dirty {
+		return details, fmt.Errorf("migration %d is dirty", version)
+	}
+	return details, nil
+}
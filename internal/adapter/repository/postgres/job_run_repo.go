@@ -0,0 +1,152 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"hash/fnv"
+	"log/slog"
+	"time"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/port"
+)
+
+type JobRunRepo struct {
+	db     *sql.DB
+	logger *slog.Logger
+}
+
+func NewJobRunRepo(db *sql.DB, logger *slog.Logger) port.JobRunRepository {
+	return &JobRunRepo{db: db, logger: logger}
+}
+
+// AcquireLock takes a session-level advisory lock on a dedicated connection, so the lock is
+// dropped by Postgres if this instance dies while holding it.
+func (r *JobRunRepo) AcquireLock(ctx context.Context, job string) (func(), bool, error) {
+	conn, err := r.db.Conn(context.Background())
+	if err != nil {
+		return nil, false, err
+	}
+
+	key := lockKey(job)
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, key); err != nil {
+		discardConn(conn)
+		if ctx.Err() != nil {
+			r.logger.WarnContext(ctx, "Gave up waiting for job lock", "job", job)
+			return nil, false, nil
+		}
+		r.logger.ErrorContext(ctx, "Failed to acquire job lock", "job", job, "error", err)
+		return nil, false, err
+	}
+
+	release := func() {
+		if _, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, key); err != nil {
+			r.logger.Error("Failed to release job lock", "job", job, "error", err)
+			discardConn(conn)
+			return
+		}
+		conn.Close()
+	}
+	return release, true, nil
+}
+
+func (r *JobRunRepo) ClaimRun(ctx context.Context, job string, scheduledTime time.Time, owner string) (domain.JobRun, bool, error) {
+	query := `
+		INSERT INTO job_runs (job, scheduled_time, status, owner) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (job, scheduled_time) DO UPDATE SET owner = EXCLUDED.owner, status = EXCLUDED.status, started_at = NOW()
+		WHERE job_runs.status IN ($3, $5)
+		RETURNING id, job, scheduled_time, status, owner, started_at`
+	var run domain.JobRun
+	err := r.db.QueryRowContext(ctx, query, job, scheduledTime, domain.JobRunRunning, owner, domain.JobRunInterrupted).
+		Scan(&run.ID, &run.Job, &run.ScheduledTime, &run.Status, &run.Owner, &run.StartedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.JobRun{}, false, nil
+		}
+		r.logger.ErrorContext(ctx, "Failed to claim job run", "job", job, "scheduled_time", scheduledTime, "error", err)
+		return domain.JobRun{}, false, err
+	}
+	return run, true, nil
+}
+
+func (r *JobRunRepo) FinishRun(ctx context.Context, id int, status domain.JobRunStatus, errMsg string) error {
+	query := `UPDATE job_runs SET status = $1, error = $2, finished_at = NOW() WHERE id = $3`
+	if _, err := r.db.ExecContext(ctx, query, status, errMsg, id); err != nil {
+		r.logger.ErrorContext(ctx, "Failed to finish job run", "run_id", id, "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *JobRunRepo) GetLatestRuns(ctx context.Context) ([]domain.JobRun, error) {
+	query := `
+		SELECT DISTINCT ON (job) id, job, scheduled_time, status, owner, error, started_at, finished_at
+		FROM job_runs ORDER BY job, scheduled_time DESC`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to query latest job runs", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []domain.JobRun
+	for rows.Next() {
+		var run domain.JobRun
+		if err := rows.Scan(&run.ID, &run.Job, &run.ScheduledTime, &run.Status, &run.Owner, &run.Error, &run.StartedAt, &run.FinishedAt); err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+func (r *JobRunRepo) GetRun(ctx context.Context, id int) (domain.JobRun, error) {
+	query := `
+		SELECT id, job, scheduled_time, status, owner, error, started_at, finished_at, report
+		FROM job_runs WHERE id = $1`
+	var run domain.JobRun
+	var report []byte
+	err := r.db.QueryRowContext(ctx, query, id).
+		Scan(&run.ID, &run.Job, &run.ScheduledTime, &run.Status, &run.Owner, &run.Error, &run.StartedAt, &run.FinishedAt, &report)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.JobRun{}, domain.ErrJobRunNotFound
+		}
+		r.logger.ErrorContext(ctx, "Failed to get job run", "run_id", id, "error", err)
+		return domain.JobRun{}, err
+	}
+	if report != nil {
+		run.Report = &domain.RunReport{}
+		if err := json.Unmarshal(report, run.Report); err != nil {
+			return domain.JobRun{}, err
+		}
+	}
+	return run, nil
+}
+
+func (r *JobRunRepo) SaveReport(ctx context.Context, id int, report domain.RunReport) error {
+	raw, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	if _, err := r.db.ExecContext(ctx, `UPDATE job_runs SET report = $1 WHERE id = $2`, raw, id); err != nil {
+		r.logger.ErrorContext(ctx, "Failed to save job run report", "run_id", id, "error", err)
+		return err
+	}
+	return nil
+}
+
+func lockKey(job string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("job:" + job))
+	return int64(h.Sum64())
+}
+
+// discardConn closes the underlying session instead of returning it to the pool,
+// which also drops any advisory lock it may hold.
+func discardConn(conn *sql.Conn) {
+	conn.Raw(func(any) error { return driver.ErrBadConn })
+	conn.Close()
+}
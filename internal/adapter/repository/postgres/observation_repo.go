@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/port"
+)
+
+type ObservationRepo struct {
+	db     tracedDB
+	logger *slog.Logger
+}
+
+func NewObservationRepo(db *sql.DB, logger *slog.Logger) port.ObservationRepository {
+	return &ObservationRepo{db: tracedDB{DB: db, table: "observations"}, logger: logger}
+}
+
+func (r *ObservationRepo) RecordObservation(ctx context.Context, observation domain.Observation) error {
+	query := `INSERT INTO observations (city, temperature, humidity, description, observed_at) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (lower(city), observed_at) DO NOTHING`
+	w := observation.Weather
+	if _, err := r.db.ExecContext(ctx, query, observation.City, w.Temperature, w.Humidity, w.Description, observation.ObservedAt); err != nil {
+		r.logger.ErrorContext(ctx, "Failed to record observation", "city", observation.City, "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *ObservationRepo) GetObservations(ctx context.Context, city string, limit int) ([]domain.Observation, error) {
+	query := `SELECT city, temperature, humidity, description, observed_at FROM observations
+		WHERE lower(city) = lower($1) ORDER BY observed_at DESC LIMIT $2`
+	rows, err := r.db.QueryContext(ctx, query, city, limit)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to get observations", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	observations := []domain.Observation{}
+	for rows.Next() {
+		var o domain.Observation
+		if err := rows.Scan(&o.City, &o.Weather.Temperature, &o.Weather.Humidity, &o.Weather.Description, &o.ObservedAt); err != nil {
+			r.logger.ErrorContext(ctx, "Error scanning observation row", "error", err)
+			return nil, err
+		}
+		o.Weather.ObservedAt = o.ObservedAt
+		observations = append(observations, o)
+	}
+	return observations, rows.Err()
+}
+
+func (r *ObservationRepo) PruneObservations(ctx context.Context, before time.Time) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM observations WHERE observed_at < $1`, before); err != nil {
+		r.logger.ErrorContext(ctx, "Failed to prune observations", "error", err)
+		return err
+	}
+	return nil
+}
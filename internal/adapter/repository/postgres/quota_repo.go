@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+	"weather-api/internal/core/port"
+)
+
+type QuotaRepo struct {
+	db     tracedDB
+	logger *slog.Logger
+}
+
+func NewQuotaRepo(db *sql.DB, logger *slog.Logger) port.QuotaRepository {
+	return &QuotaRepo{db: tracedDB{DB: db, table: "provider_usage"}, logger: logger}
+}
+
+func (r *QuotaRepo) IncrementUsage(ctx context.Context, provider string, periodStart time.Time) (int, error) {
+	query := `
+		INSERT INTO provider_usage (provider, period_start, calls) VALUES ($1, $2, 1)
+		ON CONFLICT (provider, period_start) DO UPDATE SET calls = provider_usage.calls + 1, updated_at = NOW()
+		RETURNING calls`
+	var calls int
+	if err := r.db.QueryRowContext(ctx, query, provider, periodStart).Scan(&calls); err != nil {
+		r.logger.ErrorContext(ctx, "Failed to record provider call", "provider", provider, "error", err)
+		return 0, err
+	}
+	return calls, nil
+}
+
+func (r *QuotaRepo) GetUsage(ctx context.Context, provider string, periodStart time.Time) (int, error) {
+	query := `SELECT calls FROM provider_usage WHERE provider = $1 AND period_start = $2`
+	var calls int
+	err := r.db.QueryRowContext(ctx, query, provider, periodStart).Scan(&calls)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		r.logger.ErrorContext(ctx, "Failed to get provider usage", "provider", provider, "error", err)
+		return 0, err
+	}
+	return calls, nil
+}
+
+func (r *QuotaRepo) MarkAlerted(ctx context.Context, provider string, periodStart time.Time, percent int) (bool, error) {
+	query := `
+		UPDATE provider_usage SET alerted_percent = $3
+		WHERE provider = $1 AND period_start = $2 AND alerted_percent < $3`
+	result, err := r.db.ExecContext(ctx, query, provider, periodStart, percent)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to mark quota alert", "provider", provider, "error", err)
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/port"
+)
+
+type RateLimitRepo struct {
+	db     tracedDB
+	logger *slog.Logger
+}
+
+// NewRateLimitRepo stores token buckets in Postgres so that limits hold across replicas.
+func NewRateLimitRepo(db *sql.DB, logger *slog.Logger) port.RateLimitStore {
+	return &RateLimitRepo{db: tracedDB{DB: db, table: "rate_limit_buckets"}, logger: logger}
+}
+
+// Take locks the bucket row for the duration of the transaction. The database clock is used, so
+// that replicas with skewed clocks share one notion of time.
+func (r *RateLimitRepo) Take(ctx context.Context, key string, limit domain.RateLimit) (domain.RateLimitResult, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to begin rate limit transaction", "error", err)
+		return domain.RateLimitResult{}, err
+	}
+	defer tx.Rollback()
+
+	insert := `
+		INSERT INTO rate_limit_buckets (key, tokens, updated_at) VALUES ($1, $2, NOW())
+		ON CONFLICT (key) DO NOTHING`
+	if _, err := tx.ExecContext(ctx, insert, key, limit.Limit); err != nil {
+		r.logger.ErrorContext(ctx, "Failed to create rate limit bucket", "error", err)
+		return domain.RateLimitResult{}, err
+	}
+
+	var bucket domain.TokenBucket
+	var now time.Time
+	query := `SELECT tokens, updated_at, NOW() FROM rate_limit_buckets WHERE key = $1 FOR UPDATE`
+	if err := tx.QueryRowContext(ctx, query, key).Scan(&bucket.Tokens, &bucket.UpdatedAt, &now); err != nil {
+		r.logger.ErrorContext(ctx, "Failed to get rate limit bucket", "error", err)
+		return domain.RateLimitResult{}, err
+	}
+
+	bucket, result := limit.Take(bucket, now)
+	update := `UPDATE rate_limit_buckets SET tokens = $2, updated_at = $3 WHERE key = $1`
+	if _, err := tx.ExecContext(ctx, update, key, bucket.Tokens, bucket.UpdatedAt); err != nil {
+		r.logger.ErrorContext(ctx, "Failed to update rate limit bucket", "error", err)
+		return domain.RateLimitResult{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		r.logger.ErrorContext(ctx, "Failed to commit rate limit transaction", "error", err)
+		return domain.RateLimitResult{}, err
+	}
+	return result, nil
+}
+
+func (r *RateLimitRepo) Prune(ctx context.Context, before time.Time) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM rate_limit_buckets WHERE updated_at < $1`, before); err != nil {
+		r.logger.ErrorContext(ctx, "Failed to prune rate limit buckets", "error", err)
+		return err
+	}
+	return nil
+}
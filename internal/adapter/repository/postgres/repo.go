@@ -4,142 +4,347 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"log"
+	"fmt"
+	"log/slog"
+	"strings"
 	"weather-api/internal/core/domain"
 	"weather-api/internal/core/port"
 )
 
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+const subscriptionColumns = `id, email, city, frequency, token, is_confirmed, version, channel, address`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSubscription(row rowScanner) (domain.Subscription, error) {
+	var sub domain.Subscription
+	err := row.Scan(&sub.ID, &sub.Email, &sub.City, &sub.Frequency, &sub.Token, &sub.IsConfirmed, &sub.Version, &sub.Channel, &sub.Address)
+	return sub, err
+}
+
 type SubscriptionRepo struct {
-	db *sql.DB
+	db     tracedDB
+	logger *slog.Logger
 }
 
-func NewSubscriptionRepo(db *sql.DB) port.SubscriptionRepository {
-	return &SubscriptionRepo{db: db}
+func NewSubscriptionRepo(db *sql.DB, logger *slog.Logger) port.SubscriptionRepository {
+	return &SubscriptionRepo{db: tracedDB{DB: db, table: "subscriptions"}, logger: logger}
 }
 
-func (r *SubscriptionRepo) CreateSubscription(ctx context.Context, sub domain.Subscription) error {
-	log.Printf("Creating subscription for city: %s", sub.City)
-	query := `INSERT INTO subscriptions (email, city, frequency, token, is_confirmed) VALUES ($1, $2, $3, $4, $5)`
-	_, err := r.db.ExecContext(ctx, query, sub.Email, sub.City, sub.Frequency, sub.Token, sub.IsConfirmed)
+func (r *SubscriptionRepo) CreateSubscription(ctx context.Context, sub domain.Subscription) (int, error) {
+	r.logger.DebugContext(ctx, "Creating subscription", "city", sub.City, "channel", sub.Channel)
+	channel := sub.Channel
+	if channel == "" {
+		channel = domain.ChannelEmail
+	}
+	query := `INSERT INTO subscriptions (email, city, frequency, token, is_confirmed, channel, address) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+	args := []any{sub.Email, sub.City, sub.Frequency, sub.Token, sub.IsConfirmed, channel, sub.Address}
+	if sub.Webhook != nil {
+		// A single statement, so that the subscription is never stored without its webhook.
+		query = `WITH created AS (` + query + `)
+			INSERT INTO webhooks (subscription_id, url, secret) SELECT id, $8, $9 FROM created RETURNING subscription_id`
+		args = append(args, sub.Webhook.URL, sub.Webhook.Secret)
+	}
+	var id int
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&id)
 	if err != nil {
-		log.Printf("Failed to create subscription: %v", err)
-		return err
+		r.logger.ErrorContext(ctx, "Failed to create subscription", "error", err)
+		return 0, err
 	}
-	log.Printf("Successfully created subscription")
-	return nil
+	r.logger.DebugContext(ctx, "Successfully created subscription", "subscription_id", id)
+	return id, nil
 }
 
 func (r *SubscriptionRepo) GetSubscriptionByToken(ctx context.Context, token string) (domain.Subscription, error) {
-	log.Printf("Looking up subscription")
-	var sub domain.Subscription
-	query := `SELECT id, email, city, frequency, token, is_confirmed FROM subscriptions WHERE token = $1`
-	err := r.db.QueryRowContext(ctx, query, token).Scan(&sub.ID, &sub.Email, &sub.City, &sub.Frequency, &sub.Token, &sub.IsConfirmed)
+	query := `SELECT ` + subscriptionColumns + ` FROM subscriptions WHERE token = $1`
+	sub, err := scanSubscription(r.db.QueryRowContext(ctx, query, token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			r.logger.DebugContext(ctx, "No subscription found")
+			return domain.Subscription{}, domain.ErrSubscriptionNotFound
+		}
+		r.logger.ErrorContext(ctx, "Error getting subscription", "error", err)
+		return domain.Subscription{}, err
+	}
+	return sub, nil
+}
+
+func (r *SubscriptionRepo) GetSubscriptionByID(ctx context.Context, id int) (domain.Subscription, error) {
+	query := `SELECT ` + subscriptionColumns + ` FROM subscriptions WHERE id = $1`
+	sub, err := scanSubscription(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			r.logger.DebugContext(ctx, "No subscription found", "subscription_id", id)
+			return domain.Subscription{}, domain.ErrSubscriptionNotFound
+		}
+		r.logger.ErrorContext(ctx, "Error getting subscription", "error", err)
+		return domain.Subscription{}, err
+	}
+	return sub, nil
+}
+
+func (r *SubscriptionRepo) GetSubscriptionByAddress(ctx context.Context, channel domain.Channel, address string) (domain.Subscription, error) {
+	query := `SELECT ` + subscriptionColumns + ` FROM subscriptions WHERE channel = $1 AND address = $2 ORDER BY id LIMIT 1`
+	sub, err := scanSubscription(r.db.QueryRowContext(ctx, query, channel, address))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			log.Printf("No subscription found")
-			return domain.Subscription{}, errors.New("subscription not found")
+			r.logger.DebugContext(ctx, "No subscription found", "channel", channel)
+			return domain.Subscription{}, domain.ErrSubscriptionNotFound
 		}
-		log.Printf("Error getting subscription: %v", err)
+		r.logger.ErrorContext(ctx, "Error getting subscription", "error", err)
 		return domain.Subscription{}, err
 	}
-	log.Printf("Found subscription")
 	return sub, nil
 }
 
 func (r *SubscriptionRepo) UpdateSubscription(ctx context.Context, sub domain.Subscription) error {
-	log.Printf("Updating subscription")
-	query := `UPDATE subscriptions SET is_confirmed = $1 WHERE token = $2`
+	query := `UPDATE subscriptions SET is_confirmed = $1, version = version + 1 WHERE token = $2`
 	result, err := r.db.ExecContext(ctx, query, sub.IsConfirmed, sub.Token)
 	if err != nil {
-		log.Printf("Failed to update subscription: %v", err)
+		r.logger.ErrorContext(ctx, "Failed to update subscription", "error", err)
 		return err
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		log.Printf("Error getting rows affected: %v", err)
+		r.logger.ErrorContext(ctx, "Error getting rows affected", "error", err)
 		return err
 	}
 
 	if rowsAffected == 0 {
-		log.Printf("No subscription found to update")
-		return errors.New("subscription not found")
+		r.logger.DebugContext(ctx, "No subscription found to update")
+		return domain.ErrSubscriptionNotFound
 	}
 
-	log.Printf("Successfully updated subscription")
+	r.logger.DebugContext(ctx, "Successfully updated subscription")
 	return nil
 }
 
 func (r *SubscriptionRepo) DeleteSubscription(ctx context.Context, token string) error {
-	log.Printf("Deleting subscription")
 	query := `DELETE FROM subscriptions WHERE token = $1`
 	result, err := r.db.ExecContext(ctx, query, token)
 	if err != nil {
-		log.Printf("Failed to delete subscription: %v", err)
+		r.logger.ErrorContext(ctx, "Failed to delete subscription", "error", err)
 		return err
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		log.Printf("Error getting rows affected: %v", err)
+		r.logger.ErrorContext(ctx, "Error getting rows affected", "error", err)
 		return err
 	}
 
 	if rowsAffected == 0 {
-		log.Printf("No subscription found to delete")
-		return errors.New("subscription not found")
+		r.logger.DebugContext(ctx, "No subscription found to delete")
+		return domain.ErrSubscriptionNotFound
 	}
 
-	log.Printf("Successfully deleted subscription")
+	r.logger.DebugContext(ctx, "Successfully deleted subscription")
 	return nil
 }
 
 func (r *SubscriptionRepo) GetSubscriptionsByFrequency(ctx context.Context, frequency string) ([]domain.Subscription, error) {
-	log.Printf("Getting subscriptions for frequency: %s", frequency)
-	query := `SELECT id, email, city, frequency, token, is_confirmed FROM subscriptions WHERE frequency = $1 AND is_confirmed = true`
+	query := `SELECT ` + subscriptionColumns + ` FROM subscriptions WHERE frequency = $1 AND is_confirmed = true`
 	rows, err := r.db.QueryContext(ctx, query, frequency)
 	if err != nil {
-		log.Printf("Failed to query subscriptions: %v", err)
+		r.logger.ErrorContext(ctx, "Failed to query subscriptions", "error", err)
 		return nil, err
 	}
 	defer rows.Close()
 
 	var subs []domain.Subscription
 	for rows.Next() {
-		var sub domain.Subscription
-		if err := rows.Scan(&sub.ID, &sub.Email, &sub.City, &sub.Frequency, &sub.Token, &sub.IsConfirmed); err != nil {
-			log.Printf("Error scanning subscription row: %v", err)
+		sub, err := scanSubscription(rows)
+		if err != nil {
+			r.logger.ErrorContext(ctx, "Error scanning subscription row", "error", err)
 			return nil, err
 		}
 		subs = append(subs, sub)
 	}
 
-	log.Printf("Found %d subscriptions for frequency: %s", len(subs), frequency)
+	r.logger.DebugContext(ctx, "Found subscriptions", "frequency", frequency, "count", len(subs))
 	return subs, nil
 }
 
 func (r *SubscriptionRepo) IsEmailSubscribed(ctx context.Context, email string) (bool, error) {
-	log.Printf("Checking if email is already subscribed: %s", email)
-	query := `SELECT EXISTS(SELECT 1 FROM subscriptions WHERE email = $1)`
+	query := `SELECT EXISTS(SELECT 1 FROM subscriptions WHERE lower(email) = lower($1))`
 	var exists bool
 	err := r.db.QueryRowContext(ctx, query, email).Scan(&exists)
 	if err != nil {
-		log.Printf("Failed to check email subscription: %v", err)
+		r.logger.ErrorContext(ctx, "Failed to check email subscription", "error", err)
 		return false, err
 	}
-	log.Printf("Email subscription check result: %v", exists)
+	r.logger.DebugContext(ctx, "Checked email subscription", "email", email, "exists", exists)
 	return exists, nil
 }
 
 func (r *SubscriptionRepo) IsTokenExists(ctx context.Context, token string) (bool, error) {
-	log.Printf("Checking if token exists: %s", token)
 	query := `SELECT EXISTS(SELECT 1 FROM subscriptions WHERE token = $1)`
 	var exists bool
 	err := r.db.QueryRowContext(ctx, query, token).Scan(&exists)
 	if err != nil {
-		log.Printf("Failed to check token existence: %v", err)
+		r.logger.ErrorContext(ctx, "Failed to check token existence", "error", err)
 		return false, err
 	}
-	log.Printf("Token existence check result: %v", exists)
+	r.logger.DebugContext(ctx, "Checked token existence", "token", token, "exists", exists)
 	return exists, nil
 }
+
+func (r *SubscriptionRepo) ListSubscriptions(ctx context.Context, filter domain.SubscriptionFilter) (domain.SubscriptionPage, error) {
+	where, args := subscriptionConditions(filter)
+	var page domain.SubscriptionPage
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM subscriptions`+where, args...).Scan(&page.Total); err != nil {
+		r.logger.ErrorContext(ctx, "Failed to count subscriptions", "error", err)
+		return domain.SubscriptionPage{}, err
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM subscriptions%s ORDER BY id LIMIT $%d OFFSET $%d`, subscriptionColumns, where, len(args)+1, len(args)+2)
+	rows, err := r.db.QueryContext(ctx, query, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to list subscriptions", "error", err)
+		return domain.SubscriptionPage{}, err
+	}
+	defer rows.Close()
+
+	page.Items = []domain.Subscription{}
+	for rows.Next() {
+		sub, err := scanSubscription(rows)
+		if err != nil {
+			r.logger.ErrorContext(ctx, "Error scanning subscription row", "error", err)
+			return domain.SubscriptionPage{}, err
+		}
+		page.Items = append(page.Items, sub)
+	}
+	if err := rows.Err(); err != nil {
+		return domain.SubscriptionPage{}, err
+	}
+
+	r.logger.DebugContext(ctx, "Listed subscriptions", "count", len(page.Items), "total", page.Total, "limit", filter.Limit, "offset", filter.Offset)
+	return page, nil
+}
+
+func (r *SubscriptionRepo) GetSubscriptionCounts(ctx context.Context) ([]domain.SubscriptionCount, error) {
+	query := `SELECT frequency, is_confirmed, COUNT(*) FROM subscriptions GROUP BY frequency, is_confirmed ORDER BY frequency, is_confirmed`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to count subscriptions", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []domain.SubscriptionCount{}
+	for rows.Next() {
+		var c domain.SubscriptionCount
+		if err := rows.Scan(&c.Frequency, &c.IsConfirmed, &c.Count); err != nil {
+			r.logger.ErrorContext(ctx, "Error scanning subscription count row", "error", err)
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
+
+func (r *SubscriptionRepo) UpdateSubscriptionByID(ctx context.Context, sub domain.Subscription) (domain.Subscription, error) {
+	query := `UPDATE subscriptions SET city = $1, frequency = $2, is_confirmed = $3, version = version + 1
+		WHERE id = $4 AND version = $5 RETURNING ` + subscriptionColumns
+	updated, err := scanSubscription(r.db.QueryRowContext(ctx, query, sub.City, sub.Frequency, sub.IsConfirmed, sub.ID, sub.Version))
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Subscription{}, r.conflictOrNotFound(ctx, sub.ID)
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to update subscription", "error", err)
+		return domain.Subscription{}, err
+	}
+	r.logger.DebugContext(ctx, "Successfully updated subscription", "subscription_id", sub.ID, "version", updated.Version)
+	return updated, nil
+}
+
+func (r *SubscriptionRepo) DeleteSubscriptionByID(ctx context.Context, id, version int) error {
+	query := `DELETE FROM subscriptions WHERE id = $1 AND ($2 = 0 OR version = $2)`
+	result, err := r.db.ExecContext(ctx, query, id, version)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to delete subscription", "error", err)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Error getting rows affected", "error", err)
+		return err
+	}
+	if rowsAffected == 0 {
+		return r.conflictOrNotFound(ctx, id)
+	}
+
+	r.logger.DebugContext(ctx, "Successfully deleted subscription", "subscription_id", id)
+	return nil
+}
+
+func (r *SubscriptionRepo) ListSubscriptionsAfter(ctx context.Context, filter domain.SubscriptionFilter) ([]domain.Subscription, error) {
+	where, args := subscriptionConditions(filter)
+	query := fmt.Sprintf(`SELECT %s FROM subscriptions%s ORDER BY id LIMIT $%d`, subscriptionColumns, where, len(args)+1)
+	rows, err := r.db.QueryContext(ctx, query, append(args, filter.Limit)...)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to list subscriptions", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	subs := []domain.Subscription{}
+	for rows.Next() {
+		sub, err := scanSubscription(rows)
+		if err != nil {
+			r.logger.ErrorContext(ctx, "Error scanning subscription row", "error", err)
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+	return subs, rows.Err()
+}
+
+// conflictOrNotFound tells why a conditional write on subscription id matched no row.
+func (r *SubscriptionRepo) conflictOrNotFound(ctx context.Context, id int) error {
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM subscriptions WHERE id = $1)`, id).Scan(&exists); err != nil {
+		r.logger.ErrorContext(ctx, "Failed to check subscription existence", "error", err)
+		return err
+	}
+	if !exists {
+		r.logger.DebugContext(ctx, "No subscription found", "subscription_id", id)
+		return domain.ErrSubscriptionNotFound
+	}
+	r.logger.DebugContext(ctx, "Subscription version is stale", "subscription_id", id)
+	return domain.ErrPreconditionFailed
+}
+
+func subscriptionConditions(filter domain.SubscriptionFilter) (string, []any) {
+	var conditions []string
+	var args []any
+	addCondition := func(format string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(format, len(args)))
+	}
+	if filter.Query != "" {
+		addCondition("(email ILIKE $%[1]d OR city ILIKE $%[1]d)", "%"+likeEscaper.Replace(filter.Query)+"%")
+	}
+	if filter.City != "" {
+		addCondition("LOWER(city) = LOWER($%d)", filter.City)
+	}
+	if filter.Frequency != "" {
+		addCondition("frequency = $%d", filter.Frequency)
+	}
+	if filter.Confirmed != nil {
+		addCondition("is_confirmed = $%d", *filter.Confirmed)
+	}
+	if filter.AfterID > 0 {
+		addCondition("id > $%d", filter.AfterID)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
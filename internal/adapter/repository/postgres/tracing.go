@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("weather-api/internal/adapter/repository/postgres")
+
+// tracedDB wraps every query on table in a client span. Queries only carry placeholders, so the
+// statement is safe to record.
+type tracedDB struct {
+	*sql.DB
+	table string
+}
+
+func (db tracedDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	ctx, span := db.startSpan(ctx, query)
+	row := db.DB.QueryRowContext(ctx, query, args...)
+	endQuerySpan(span, row.Err())
+	return row
+}
+
+func (db tracedDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	ctx, span := db.startSpan(ctx, query)
+	rows, err := db.DB.QueryContext(ctx, query, args...)
+	endQuerySpan(span, err)
+	return rows, err
+}
+
+func (db tracedDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, span := db.startSpan(ctx, query)
+	result, err := db.DB.ExecContext(ctx, query, args...)
+	endQuerySpan(span, err)
+	return result, err
+}
+
+// BeginTx starts a transaction whose queries are traced like those of db.
+func (db tracedDB) BeginTx(ctx context.Context, opts *sql.TxOptions) (tracedTx, error) {
+	tx, err := db.DB.BeginTx(ctx, opts)
+	return tracedTx{Tx: tx, db: db}, err
+}
+
+type tracedTx struct {
+	*sql.Tx
+	db tracedDB
+}
+
+func (tx tracedTx) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	ctx, span := tx.db.startSpan(ctx, query)
+	row := tx.Tx.QueryRowContext(ctx, query, args...)
+	endQuerySpan(span, row.Err())
+	return row
+}
+
+func (tx tracedTx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, span := tx.db.startSpan(ctx, query)
+	result, err := tx.Tx.ExecContext(ctx, query, args...)
+	endQuerySpan(span, err)
+	return result, err
+}
+
+func (db tracedDB) startSpan(ctx context.Context, query string) (context.Context, trace.Span) {
+	operation, _, _ := strings.Cut(strings.TrimSpace(query), " ")
+	operation = strings.ToUpper(operation)
+	return tracer.Start(ctx, operation+" "+db.table,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system.name", "postgresql"),
+			attribute.String("db.operation.name", operation),
+			attribute.String("db.collection.name", db.table),
+			attribute.String("db.query.text", query),
+		),
+	)
+}
+
+func endQuerySpan(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
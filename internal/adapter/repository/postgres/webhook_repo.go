@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/port"
+)
+
+const webhookColumns = `subscription_id, url, secret, failures, last_error, disabled_at`
+
+func scanWebhook(row rowScanner) (domain.Webhook, error) {
+	var hook domain.Webhook
+	var disabledAt sql.NullTime
+	err := row.Scan(&hook.SubscriptionID, &hook.URL, &hook.Secret, &hook.Failures, &hook.LastError, &disabledAt)
+	hook.DisabledAt = disabledAt.Time
+	return hook, err
+}
+
+type WebhookRepo struct {
+	db     tracedDB
+	logger *slog.Logger
+}
+
+func NewWebhookRepo(db *sql.DB, logger *slog.Logger) port.WebhookRepository {
+	return &WebhookRepo{db: tracedDB{DB: db, table: "webhooks"}, logger: logger}
+}
+
+func (r *WebhookRepo) GetWebhook(ctx context.Context, subscriptionID int) (domain.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE subscription_id = $1`
+	hook, err := scanWebhook(r.db.QueryRowContext(ctx, query, subscriptionID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Webhook{}, domain.ErrWebhookNotFound
+		}
+		r.logger.ErrorContext(ctx, "Failed to get webhook", "subscription_id", subscriptionID, "error", err)
+		return domain.Webhook{}, err
+	}
+	return hook, nil
+}
+
+func (r *WebhookRepo) RecordWebhookSuccess(ctx context.Context, subscriptionID int) error {
+	query := `UPDATE webhooks SET failures = 0, last_error = '', disabled_at = NULL WHERE subscription_id = $1`
+	if _, err := r.db.ExecContext(ctx, query, subscriptionID); err != nil {
+		r.logger.ErrorContext(ctx, "Failed to record webhook success", "subscription_id", subscriptionID, "error", err)
+		return err
+	}
+	return nil
+}
+
+func (r *WebhookRepo) RecordWebhookFailure(ctx context.Context, subscriptionID int, deliveryErr string, disableAfter int) (domain.Webhook, error) {
+	query := `
+		UPDATE webhooks SET failures = failures + 1, last_error = $2,
+			disabled_at = CASE WHEN disabled_at IS NULL AND $3 > 0 AND failures + 1 >= $3 THEN NOW() ELSE disabled_at END
+		WHERE subscription_id = $1
+		RETURNING ` + webhookColumns
+	hook, err := scanWebhook(r.db.QueryRowContext(ctx, query, subscriptionID, deliveryErr, disableAfter))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Webhook{}, domain.ErrWebhookNotFound
+		}
+		r.logger.ErrorContext(ctx, "Failed to record webhook failure", "subscription_id", subscriptionID, "error", err)
+		return domain.Webhook{}, err
+	}
+	return hook, nil
+}
@@ -0,0 +1,67 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"weather-api/internal/core/port"
+	"weather-api/internal/util"
+)
+
+var errNotIncomingWebhook = errors.New("not a Slack incoming webhook URL")
+
+// Client posts messages to Slack incoming webhooks. Slack answers errors with a short code in
+// a plain text body, such as "no_service" for a removed webhook.
+type Client struct {
+	client *http.Client
+	// baseURL is the prefix of the incoming webhook URLs that are posted to; empty accepts any.
+	baseURL string
+}
+
+func NewClient(client *http.Client, baseURL string) port.SlackSender {
+	traced := *client
+	traced.Transport = util.NewHostOnlyTracingTransport(client.Transport)
+	traced.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return &Client{client: &traced, baseURL: baseURL}
+}
+
+func (c *Client) AcceptsWebhookURL(webhookURL string) bool {
+	return strings.HasPrefix(webhookURL, c.baseURL)
+}
+
+func (c *Client) PostMessage(ctx context.Context, webhookURL string, message []byte) error {
+	if !c.AcceptsWebhookURL(webhookURL) {
+		return errNotIncomingWebhook
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(message))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		// The webhook URL is a secret, so don't let it end up in logs.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("posting to slack: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+	if resp.StatusCode != http.StatusOK {
+		if code := strings.TrimSpace(string(body)); code != "" {
+			return fmt.Errorf("slack responded with %d: %s", resp.StatusCode, code)
+		}
+		return fmt.Errorf("slack responded with %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package slack
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestClient_PostMessage(t *testing.T) {
+	var received *http.Request
+	var body []byte
+	slack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer slack.Close()
+
+	err := NewClient(slack.Client(), slack.URL+"/services/").PostMessage(context.Background(), slack.URL+"/services/T000/B000/XXX", []byte(`{"text":"hi"}`))
+
+	require.NoError(t, err)
+	assert.Equal(t, http.MethodPost, received.Method)
+	assert.Equal(t, "/services/T000/B000/XXX", received.URL.Path)
+	assert.Equal(t, "application/json", received.Header.Get("Content-Type"))
+	assert.Equal(t, `{"text":"hi"}`, string(body))
+}
+
+func TestClient_PostMessageErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		handler       http.HandlerFunc
+		expectedError string
+	}{
+		{
+			name: "removed webhook",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte("no_service\n"))
+			},
+			expectedError: "slack responded with 404: no_service",
+		},
+		{
+			name: "redirect is not followed",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Redirect(w, r, "/elsewhere", http.StatusFound)
+			},
+			expectedError: "slack responded with 302",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slack := httptest.NewServer(tt.handler)
+			defer slack.Close()
+
+			err := NewClient(slack.Client(), slack.URL+"/services/").PostMessage(context.Background(), slack.URL+"/services/T000/B000/XXX", []byte(`{}`))
+
+			assert.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestClient_RefusesOtherURLs(t *testing.T) {
+	called := false
+	slack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer slack.Close()
+	client := NewClient(slack.Client(), "https://hooks.slack.com/services/")
+
+	err := client.PostMessage(context.Background(), slack.URL+"/services/T000/B000/XXX", []byte(`{}`))
+
+	assert.EqualError(t, err, "not a Slack incoming webhook URL")
+	assert.False(t, called)
+	assert.True(t, client.AcceptsWebhookURL("https://hooks.slack.com/services/T000/B000/XXX"))
+	assert.False(t, client.AcceptsWebhookURL("https://hooks.slack.com.example.com/services/T000"))
+	assert.True(t, NewClient(slack.Client(), "").AcceptsWebhookURL(slack.URL+"/anything"))
+}
+
+func TestClient_PostMessageKeepsWebhookURLOutOfSpans(t *testing.T) {
+	slack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer slack.Close()
+	recorder := tracetest.NewSpanRecorder()
+	ctx, parent := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test").Start(context.Background(), "parent")
+
+	err := NewClient(slack.Client(), slack.URL+"/services/").PostMessage(ctx, slack.URL+"/services/T000/B000/XXX", []byte(`{"text":"hi"}`))
+	parent.End()
+
+	require.NoError(t, err)
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	for _, attr := range spans[0].Attributes() {
+		assert.NotContains(t, attr.Value.Emit(), "/services/", string(attr.Key))
+	}
+}
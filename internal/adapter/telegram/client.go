@@ -0,0 +1,97 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"weather-api/internal/core/port"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// Client calls the Telegram Bot API at baseURL, https://api.telegram.org in production.
+type Client struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewClient copies client and wraps it with tracing and bot token transports.
+func NewClient(baseURL, token string, client *http.Client) port.TelegramSender {
+	next := client.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	traced := *client
+	traced.Transport = otelhttp.NewTransport(tokenTransport{token: token, next: next})
+	return &Client{baseURL: strings.TrimSuffix(baseURL, "/"), client: &traced}
+}
+
+func (c *Client) SendMessage(ctx context.Context, chatID, text string) error {
+	body, err := json.Marshal(map[string]any{
+		"chat_id":                  chatID,
+		"text":                     text,
+		"parse_mode":               "HTML",
+		"disable_web_page_preview": true,
+	})
+	if err != nil {
+		return err
+	}
+	return c.call(ctx, "sendMessage", body)
+}
+
+func (c *Client) call(ctx context.Context, method string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/bot/"+method, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		// Keep URLs out of logs in case one carries the bot token.
+		return fmt.Errorf("calling telegram %s: %w", method, stripURL(err))
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&result); err != nil {
+		return fmt.Errorf("telegram %s responded with %d", method, resp.StatusCode)
+	}
+	if !result.OK {
+		return fmt.Errorf("telegram %s responded with %d: %s", method, resp.StatusCode, result.Description)
+	}
+	return nil
+}
+
+// tokenTransport puts the bot token into the "/bot/<method>" path below the tracing transport,
+// so the token never ends up in span attributes.
+type tokenTransport struct {
+	token string
+	next  http.RoundTripper
+}
+
+func (t tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	i := strings.LastIndex(req.URL.Path, "/bot/") + len("/bot")
+	req.URL.Path = req.URL.Path[:i] + t.token + req.URL.Path[i:]
+	req.URL.RawPath = ""
+	return t.next.RoundTrip(req)
+}
+
+// stripURL drops the request URL from err.
+func stripURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
@@ -0,0 +1,105 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestClient_SendMessage(t *testing.T) {
+	var path string
+	var body map[string]any
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":1}}`))
+	}))
+	defer api.Close()
+
+	err := NewClient(api.URL+"/", "123:secret", api.Client()).SendMessage(context.Background(), "42", "<b>Weather in Kyiv</b>")
+
+	require.NoError(t, err)
+	assert.Equal(t, "/bot123:secret/sendMessage", path)
+	assert.Equal(t, map[string]any{
+		"chat_id":                  "42",
+		"text":                     "<b>Weather in Kyiv</b>",
+		"parse_mode":               "HTML",
+		"disable_web_page_preview": true,
+	}, body)
+}
+
+func TestClient_SendMessageErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		handler       http.HandlerFunc
+		expectedError string
+	}{
+		{
+			name: "api error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = w.Write([]byte(`{"ok":false,"error_code":403,"description":"Forbidden: bot was blocked by the user"}`))
+			},
+			expectedError: "telegram sendMessage responded with 403: Forbidden: bot was blocked by the user",
+		},
+		{
+			name: "response that isn't JSON",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadGateway)
+			},
+			expectedError: "telegram sendMessage responded with 502",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := httptest.NewServer(tt.handler)
+			defer api.Close()
+
+			err := NewClient(api.URL, "123:secret", api.Client()).SendMessage(context.Background(), "42", "hi")
+
+			assert.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestClient_SendMessageHidesToken(t *testing.T) {
+	api := httptest.NewServer(http.NotFoundHandler())
+	url := api.URL
+	api.Close()
+
+	err := NewClient(url, "123:secret", &http.Client{}).SendMessage(context.Background(), "42", "hi")
+
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "123:secret")
+}
+
+func TestClient_SendMessageKeepsTokenOutOfSpans(t *testing.T) {
+	var path string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer api.Close()
+	recorder := tracetest.NewSpanRecorder()
+	ctx, parent := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test").Start(context.Background(), "parent")
+
+	err := NewClient(api.URL, "123:secret", api.Client()).SendMessage(ctx, "42", "hi")
+	parent.End()
+
+	require.NoError(t, err)
+	assert.Equal(t, "/bot123:secret/sendMessage", path)
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	for _, attr := range spans[0].Attributes() {
+		assert.NotContains(t, attr.Value.Emit(), "123:secret", string(attr.Key))
+	}
+}
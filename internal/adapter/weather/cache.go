@@ -0,0 +1,173 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/port"
+)
+
+const maxCacheEntries = 1000
+
+type CacheObserver interface {
+	ObserveCache(hit bool)
+}
+
+type cacheEntry struct {
+	weather   domain.Weather
+	expiresAt time.Time
+}
+
+// CachedWeatherService keeps successful lookups for ttl so repeated requests for the same city
+// within a run or from the API don't reach the provider. While the provider is unavailable, e.g.
+// because its quota is used up, expired entries are served instead of an error.
+type CachedWeatherService struct {
+	next     port.WeatherService
+	ttl      time.Duration
+	observer CacheObserver
+	now      func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+func NewCachedWeatherService(next port.WeatherService, ttl time.Duration, observer CacheObserver) *CachedWeatherService {
+	return &CachedWeatherService{
+		next:     next,
+		ttl:      ttl,
+		observer: observer,
+		now:      time.Now,
+		entries:  map[string]cacheEntry{},
+	}
+}
+
+func (c *CachedWeatherService) GetWeather(ctx context.Context, city string) (domain.Weather, error) {
+	key := cacheKey(city)
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && c.now().Before(entry.expiresAt) {
+		c.observe(true)
+		return entry.weather, nil
+	}
+	c.observe(false)
+
+	weather, err := c.next.GetWeather(ctx, city)
+	if err != nil {
+		if ok && errors.Is(err, domain.ErrProviderUnavailable) {
+			return entry.weather, nil
+		}
+		return domain.Weather{}, err
+	}
+
+	weather.ExpiresAt = c.now().Add(c.ttl)
+	c.mu.Lock()
+	c.set(key, cacheEntry{weather: weather, expiresAt: weather.ExpiresAt})
+	c.mu.Unlock()
+	return weather, nil
+}
+
+func (c *CachedWeatherService) set(key string, entry cacheEntry) {
+	setEntry(c.entries, key, entry, c.now())
+}
+
+func (e cacheEntry) expiry() time.Time {
+	return e.expiresAt
+}
+
+// setEntry stores entry, first dropping expired entries and then arbitrary ones while the
+// cache holds maxCacheEntries.
+func setEntry[E interface{ expiry() time.Time }](entries map[string]E, key string, entry E, now time.Time) {
+	if len(entries) >= maxCacheEntries {
+		for k, e := range entries {
+			if !now.Before(e.expiry()) {
+				delete(entries, k)
+			}
+		}
+		for k := range entries {
+			if len(entries) < maxCacheEntries {
+				break
+			}
+			delete(entries, k)
+		}
+	}
+	entries[key] = entry
+}
+
+func (c *CachedWeatherService) observe(hit bool) {
+	if c.observer != nil {
+		c.observer.ObserveCache(hit)
+	}
+}
+
+type forecastEntry struct {
+	forecast  []domain.DailyForecast
+	expiresAt time.Time
+}
+
+func (e forecastEntry) expiry() time.Time {
+	return e.expiresAt
+}
+
+// CachedForecastService is CachedWeatherService for forecasts, which change far less often
+// than the current weather and so can be kept longer.
+type CachedForecastService struct {
+	next     port.ForecastService
+	ttl      time.Duration
+	observer CacheObserver
+	now      func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]forecastEntry
+}
+
+func NewCachedForecastService(next port.ForecastService, ttl time.Duration, observer CacheObserver) *CachedForecastService {
+	return &CachedForecastService{
+		next:     next,
+		ttl:      ttl,
+		observer: observer,
+		now:      time.Now,
+		entries:  map[string]forecastEntry{},
+	}
+}
+
+func (c *CachedForecastService) GetForecast(ctx context.Context, city string, days int) ([]domain.DailyForecast, error) {
+	key := cacheKey(city) + "/" + strconv.Itoa(days)
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && c.now().Before(entry.expiresAt) {
+		c.observe(true)
+		return entry.forecast, nil
+	}
+	c.observe(false)
+
+	forecast, err := c.next.GetForecast(ctx, city, days)
+	if err != nil {
+		if ok && errors.Is(err, domain.ErrProviderUnavailable) {
+			return entry.forecast, nil
+		}
+		return nil, err
+	}
+
+	c.mu.Lock()
+	setEntry(c.entries, key, forecastEntry{forecast: forecast, expiresAt: c.now().Add(c.ttl)}, c.now())
+	c.mu.Unlock()
+	return forecast, nil
+}
+
+func (c *CachedForecastService) observe(hit bool) {
+	if c.observer != nil {
+		c.observer.ObserveCache(hit)
+	}
+}
+
+func cacheKey(city string) string {
+	return strings.ToLower(strings.TrimSpace(city))
+}
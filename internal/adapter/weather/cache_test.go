@@ -0,0 +1,183 @@
+package weather
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingObserver struct {
+	hits, misses int
+}
+
+func (o *recordingObserver) ObserveCache(hit bool) {
+	if hit {
+		o.hits++
+	} else {
+		o.misses++
+	}
+}
+
+func TestCachedWeatherService_ExpiresAfterTTL(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	sunny := domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny"}
+	rainy := domain.Weather{Temperature: 15, Humidity: 90, Description: "Rain"}
+	next := &mocks.MockWeatherService{}
+	next.On("GetWeather", mock.Anything, "Kyiv").Return(sunny, nil).Once()
+	next.On("GetWeather", mock.Anything, "Kyiv").Return(rainy, nil).Once()
+	observer := &recordingObserver{}
+	cache := NewCachedWeatherService(next, 10*time.Minute, observer)
+	cache.now = func() time.Time { return now }
+
+	first, err := cache.GetWeather(ctx, "Kyiv")
+	require.NoError(t, err)
+	assert.Equal(t, "Sunny", first.Description)
+	assert.Equal(t, now.Add(10*time.Minute), first.ExpiresAt)
+
+	now = now.Add(9 * time.Minute)
+	cached, err := cache.GetWeather(ctx, " kyiv ")
+	require.NoError(t, err)
+	assert.Equal(t, first, cached)
+
+	now = now.Add(time.Minute)
+	refreshed, err := cache.GetWeather(ctx, "Kyiv")
+	require.NoError(t, err)
+	assert.Equal(t, "Rain", refreshed.Description)
+	assert.Equal(t, now.Add(10*time.Minute), refreshed.ExpiresAt)
+
+	assert.Equal(t, &recordingObserver{hits: 1, misses: 2}, observer)
+	next.AssertExpectations(t)
+}
+
+func TestCachedWeatherService_DoesNotCacheErrors(t *testing.T) {
+	ctx := context.Background()
+	next := &mocks.MockWeatherService{}
+	next.On("GetWeather", mock.Anything, "Atlantis").Return(domain.Weather{}, domain.ErrCityNotFound).Twice()
+	observer := &recordingObserver{}
+	cache := NewCachedWeatherService(next, 10*time.Minute, observer)
+
+	for range 2 {
+		_, err := cache.GetWeather(ctx, "Atlantis")
+		assert.ErrorIs(t, err, domain.ErrCityNotFound)
+	}
+	assert.Equal(t, &recordingObserver{misses: 2}, observer)
+	next.AssertExpectations(t)
+}
+
+func TestCachedWeatherService_EvictsAtMaxEntries(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	next := &mocks.MockWeatherService{}
+	next.On("GetWeather", mock.Anything, mock.Anything).Return(domain.Weather{Description: "Sunny"}, nil)
+	cache := NewCachedWeatherService(next, 10*time.Minute, nil)
+	cache.now = func() time.Time { return now }
+
+	// The first half expires before the cache fills up, so they are the ones evicted.
+	for i := range maxCacheEntries {
+		if i == maxCacheEntries/2 {
+			now = now.Add(5 * time.Minute)
+		}
+		_, err := cache.GetWeather(ctx, "city"+strconv.Itoa(i))
+		require.NoError(t, err)
+	}
+	assert.Len(t, cache.entries, maxCacheEntries)
+
+	now = now.Add(6 * time.Minute)
+	_, err := cache.GetWeather(ctx, "one more")
+	require.NoError(t, err)
+
+	assert.Len(t, cache.entries, maxCacheEntries/2+1)
+	assert.NotContains(t, cache.entries, "city0")
+	assert.Contains(t, cache.entries, "city"+strconv.Itoa(maxCacheEntries-1))
+	assert.Contains(t, cache.entries, "one more")
+}
+
+func TestCachedWeatherService_EvictsLiveEntriesWhenFull(t *testing.T) {
+	ctx := context.Background()
+	next := &mocks.MockWeatherService{}
+	next.On("GetWeather", mock.Anything, mock.Anything).Return(domain.Weather{Description: "Sunny"}, nil)
+	cache := NewCachedWeatherService(next, 10*time.Minute, nil)
+
+	for i := range maxCacheEntries + 10 {
+		_, err := cache.GetWeather(ctx, "city"+strconv.Itoa(i))
+		require.NoError(t, err)
+	}
+
+	assert.Len(t, cache.entries, maxCacheEntries)
+	assert.Contains(t, cache.entries, "city"+strconv.Itoa(maxCacheEntries+9))
+}
+
+func TestCachedWeatherService_ServesExpiredEntryWhenQuotaIsExhausted(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	sunny := domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny"}
+	exhausted := &domain.ProviderUnavailableError{RetryAfter: time.Hour, Err: domain.ErrQuotaExhausted}
+	next := &mocks.MockWeatherService{}
+	next.On("GetWeather", mock.Anything, "Kyiv").Return(sunny, nil).Once()
+	next.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{}, exhausted).Once()
+	next.On("GetWeather", mock.Anything, "Lviv").Return(domain.Weather{}, exhausted).Once()
+	cache := NewCachedWeatherService(next, 10*time.Minute, nil)
+	cache.now = func() time.Time { return now }
+
+	first, err := cache.GetWeather(ctx, "Kyiv")
+	require.NoError(t, err)
+
+	now = now.Add(time.Hour)
+	stale, err := cache.GetWeather(ctx, "Kyiv")
+	require.NoError(t, err)
+	// The entry keeps its expiry, so that responses built from it aren't cached any further.
+	assert.Equal(t, first, stale)
+	assert.True(t, stale.ExpiresAt.Before(now))
+
+	_, err = cache.GetWeather(ctx, "Lviv")
+	assert.ErrorIs(t, err, domain.ErrQuotaExhausted)
+	next.AssertExpectations(t)
+}
+
+func TestCachedForecastService(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	sunny := []domain.DailyForecast{{Date: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), Description: "Sunny"}}
+	rainy := []domain.DailyForecast{{Date: time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), Description: "Rain"}}
+	next := &mocks.MockForecastService{}
+	next.On("GetForecast", mock.Anything, "Kyiv", 3).Return(sunny, nil).Once()
+	next.On("GetForecast", mock.Anything, "Kyiv", 3).Return([]domain.DailyForecast(nil), &domain.ProviderUnavailableError{Err: domain.ErrQuotaExhausted}).Once()
+	next.On("GetForecast", mock.Anything, "Kyiv", 3).Return(rainy, nil).Once()
+	next.On("GetForecast", mock.Anything, "Lviv", 3).Return([]domain.DailyForecast(nil), domain.ErrCityNotFound).Once()
+	observer := &recordingObserver{}
+	cache := NewCachedForecastService(next, time.Hour, observer)
+	cache.now = func() time.Time { return now }
+
+	first, err := cache.GetForecast(ctx, "Kyiv", 3)
+	require.NoError(t, err)
+	assert.Equal(t, sunny, first)
+
+	now = now.Add(59 * time.Minute)
+	cached, err := cache.GetForecast(ctx, " kyiv ", 3)
+	require.NoError(t, err)
+	assert.Equal(t, sunny, cached)
+
+	// Expired, but the provider is unavailable.
+	now = now.Add(time.Minute)
+	stale, err := cache.GetForecast(ctx, "Kyiv", 3)
+	require.NoError(t, err)
+	assert.Equal(t, sunny, stale)
+
+	refreshed, err := cache.GetForecast(ctx, "Kyiv", 3)
+	require.NoError(t, err)
+	assert.Equal(t, rainy, refreshed)
+
+	_, err = cache.GetForecast(ctx, "Lviv", 3)
+	assert.ErrorIs(t, err, domain.ErrCityNotFound)
+
+	assert.Equal(t, &recordingObserver{hits: 1, misses: 4}, observer)
+	next.AssertExpectations(t)
+}
@@ -0,0 +1,45 @@
+package weather
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type ClientConfig struct {
+	Timeout   time.Duration
+	UserAgent string
+	// ProxyURL overrides the HTTP_PROXY/HTTPS_PROXY/NO_PROXY environment variables when set.
+	ProxyURL string
+}
+
+// NewHTTPClient creates the client used to reach the weather provider. Unlike http.DefaultClient
+// it has an overall timeout, so a hung upstream can't block a request or an update run.
+func NewHTTPClient(cfg ClientConfig) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.ProxyURL != "" {
+		proxy, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	var rt http.RoundTripper = transport
+	if cfg.UserAgent != "" {
+		rt = userAgentTransport{userAgent: cfg.UserAgent, next: rt}
+	}
+	return &http.Client{Timeout: cfg.Timeout, Transport: rt}, nil
+}
+
+type userAgentTransport struct {
+	userAgent string
+	next      http.RoundTripper
+}
+
+func (t userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+	return t.next.RoundTrip(req)
+}
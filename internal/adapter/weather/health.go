@@ -0,0 +1,41 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"weather-api/internal/core/port"
+)
+
+// HealthChecker checks that the provider is reachable without spending an API call.
+type HealthChecker struct {
+	client *http.Client
+	url    string
+}
+
+func NewHealthChecker(baseURL string, client *http.Client) port.HealthChecker {
+	return &HealthChecker{client: client, url: strings.TrimSuffix(baseURL, "/") + "/"}
+}
+
+func (h *HealthChecker) Name() string {
+	return "weather_provider"
+}
+
+func (h *HealthChecker) Check(ctx context.Context) (map[string]any, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, h.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	details := map[string]any{"status_code": resp.StatusCode}
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return details, fmt.Errorf("provider responded with %d", resp.StatusCode)
+	}
+	return details, nil
+}
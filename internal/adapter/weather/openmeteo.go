@@ -0,0 +1,148 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"weather-api/internal/core/domain"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// OpenMeteoService is a keyless fallback provider. It resolves the city with the Open-Meteo
+// geocoding API and then asks the forecast API for the current conditions.
+type OpenMeteoService struct {
+	geocodingURL string
+	forecastURL  string
+	client       *http.Client
+}
+
+func NewOpenMeteoService(geocodingURL, forecastURL string, client *http.Client) *OpenMeteoService {
+	traced := *client
+	traced.Transport = otelhttp.NewTransport(client.Transport)
+	return &OpenMeteoService{
+		geocodingURL: strings.TrimSuffix(geocodingURL, "/"),
+		forecastURL:  strings.TrimSuffix(forecastURL, "/"),
+		client:       &traced,
+	}
+}
+
+func (o *OpenMeteoService) GetWeather(ctx context.Context, city string) (domain.Weather, error) {
+	query := url.Values{}
+	query.Set("name", city)
+	query.Set("count", "1")
+	var places struct {
+		Results []struct {
+			Latitude  float64 `json:"latitude"`
+			Longitude float64 `json:"longitude"`
+		} `json:"results"`
+	}
+	if err := o.get(ctx, o.geocodingURL+"/search?"+query.Encode(), &places); err != nil {
+		return domain.Weather{}, err
+	}
+	if len(places.Results) == 0 {
+		return domain.Weather{}, domain.ErrCityNotFound
+	}
+
+	query = url.Values{}
+	query.Set("latitude", strconv.FormatFloat(places.Results[0].Latitude, 'f', -1, 64))
+	query.Set("longitude", strconv.FormatFloat(places.Results[0].Longitude, 'f', -1, 64))
+	query.Set("current", "temperature_2m,relative_humidity_2m,weather_code")
+	query.Set("timeformat", "unixtime")
+	var forecast struct {
+		Current struct {
+			Time        int64   `json:"time"`
+			Temperature float64 `json:"temperature_2m"`
+			Humidity    int     `json:"relative_humidity_2m"`
+			WeatherCode int     `json:"weather_code"`
+		} `json:"current"`
+	}
+	if err := o.get(ctx, o.forecastURL+"/forecast?"+query.Encode(), &forecast); err != nil {
+		return domain.Weather{}, err
+	}
+
+	weather := domain.Weather{
+		Temperature: forecast.Current.Temperature,
+		Humidity:    forecast.Current.Humidity,
+		Description: describeWeatherCode(forecast.Current.WeatherCode),
+	}
+	if forecast.Current.Time > 0 {
+		weather.ObservedAt = time.Unix(forecast.Current.Time, 0)
+	}
+	return weather, nil
+}
+
+func (o *OpenMeteoService) get(ctx context.Context, rawURL string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := o.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return &domain.ProviderUnavailableError{Err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+		return &domain.ProviderUnavailableError{
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+			Err:        fmt.Errorf("provider responded with %d", resp.StatusCode),
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API error: provider responded with %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return &domain.ProviderUnavailableError{Err: err}
+	}
+	return json.Unmarshal(body, out)
+}
+
+// describeWeatherCode maps WMO weather interpretation codes to text.
+func describeWeatherCode(code int) string {
+	switch code {
+	case 0:
+		return "Clear sky"
+	case 1:
+		return "Mainly clear"
+	case 2:
+		return "Partly cloudy"
+	case 3:
+		return "Overcast"
+	case 45, 48:
+		return "Fog"
+	case 51, 53, 55:
+		return "Drizzle"
+	case 56, 57:
+		return "Freezing drizzle"
+	case 61, 63, 65:
+		return "Rain"
+	case 66, 67:
+		return "Freezing rain"
+	case 71, 73, 75:
+		return "Snow"
+	case 77:
+		return "Snow grains"
+	case 80, 81, 82:
+		return "Rain showers"
+	case 85, 86:
+		return "Snow showers"
+	case 95:
+		return "Thunderstorm"
+	case 96, 99:
+		return "Thunderstorm with hail"
+	default:
+		return "Unknown"
+	}
+}
@@ -0,0 +1,101 @@
+package weather
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"weather-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenMeteoService_GetWeather(t *testing.T) {
+	var geocoding, forecast map[string]string
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch r.URL.Path {
+		case "/geocoding/search":
+			geocoding = map[string]string{"name": query.Get("name"), "count": query.Get("count")}
+			_, _ = w.Write([]byte(`{"results":[{"name":"New York","latitude":40.71427,"longitude":-74.00597}]}`))
+		case "/forecast/forecast":
+			forecast = map[string]string{"latitude": query.Get("latitude"), "longitude": query.Get("longitude"), "current": query.Get("current")}
+			_, _ = w.Write([]byte(`{"current":{"time":1748779200,"temperature_2m":20.5,"relative_humidity_2m":60,"weather_code":61}}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer provider.Close()
+
+	weather, err := NewOpenMeteoService(provider.URL+"/geocoding/", provider.URL+"/forecast", provider.Client()).GetWeather(context.Background(), "New York")
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Rain", ObservedAt: time.Unix(1748779200, 0)}, weather)
+	assert.Equal(t, map[string]string{"name": "New York", "count": "1"}, geocoding)
+	assert.Equal(t, map[string]string{"latitude": "40.71427", "longitude": "-74.00597", "current": "temperature_2m,relative_humidity_2m,weather_code"}, forecast)
+}
+
+func TestOpenMeteoService_GetWeatherErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		geocoding   http.HandlerFunc
+		forecast    http.HandlerFunc
+		expectedErr error
+	}{
+		{
+			name: "unknown city",
+			geocoding: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"generationtime_ms":0.5}`))
+			},
+			expectedErr: domain.ErrCityNotFound,
+		},
+		{
+			name: "geocoding unavailable",
+			geocoding: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			},
+			expectedErr: domain.ErrProviderUnavailable,
+		},
+		{
+			name: "forecast rate limited",
+			geocoding: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"results":[{"latitude":50.45,"longitude":30.52}]}`))
+			},
+			forecast: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusTooManyRequests)
+			},
+			expectedErr: domain.ErrProviderUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.Handle("/search", tt.geocoding)
+			if tt.forecast != nil {
+				mux.Handle("/forecast", tt.forecast)
+			}
+			provider := httptest.NewServer(mux)
+			defer provider.Close()
+
+			_, err := NewOpenMeteoService(provider.URL, provider.URL, provider.Client()).GetWeather(context.Background(), "Kyiv")
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestOpenMeteoService_RejectsClientErrors(t *testing.T) {
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer provider.Close()
+
+	_, err := NewOpenMeteoService(provider.URL, provider.URL, provider.Client()).GetWeather(context.Background(), "Kyiv")
+
+	assert.EqualError(t, err, "API error: provider responded with 400")
+	assert.NotErrorIs(t, err, domain.ErrProviderUnavailable)
+}
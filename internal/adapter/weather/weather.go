@@ -1,65 +1,186 @@
 package weather
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 	"weather-api/internal/core/domain"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
+// maxResponseSize bounds how much of a provider response is read.
+const maxResponseSize = 1 << 20
+
 type WeatherService struct {
-	apiKey string
+	baseURL string
+	client  *http.Client
 }
 
-func NewWeatherService(apiKey string) *WeatherService {
-	return &WeatherService{apiKey: apiKey}
+// NewWeatherService creates the weatherapi.com adapter on top of client, which is copied and
+// wrapped with tracing and API key transports.
+func NewWeatherService(baseURL, apiKey string, client *http.Client) *WeatherService {
+	next := client.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	traced := *client
+	traced.Transport = otelhttp.NewTransport(apiKeyTransport{key: apiKey, next: next})
+	return &WeatherService{baseURL: strings.TrimSuffix(baseURL, "/"), client: &traced}
 }
 
-func (w *WeatherService) GetWeather(city string) (domain.Weather, error) {
-	url := "http://api.weatherapi.com/v1/current.json?key=" + w.apiKey + "&q=" + city
-	resp, err := http.Get(url)
-	if err != nil {
+func (w *WeatherService) GetWeather(ctx context.Context, city string) (domain.Weather, error) {
+	query := url.Values{}
+	query.Set("q", city)
+	var data struct {
+		Current struct {
+			LastUpdatedEpoch int64   `json:"last_updated_epoch"`
+			TempC            float64 `json:"temp_c"`
+			Humidity         int     `json:"humidity"`
+			Condition        struct {
+				Text string `json:"text"`
+			} `json:"condition"`
+		} `json:"current"`
+	}
+	if err := w.get(ctx, "/current.json", query, &data); err != nil {
 		return domain.Weather{}, err
 	}
+
+	if data.Current.TempC == 0 && data.Current.Humidity == 0 && data.Current.Condition.Text == "" {
+		return domain.Weather{}, domain.ErrCityNotFound
+	}
+
+	weather := domain.Weather{
+		Temperature: data.Current.TempC,
+		Humidity:    data.Current.Humidity,
+		Description: data.Current.Condition.Text,
+	}
+	if data.Current.LastUpdatedEpoch > 0 {
+		weather.ObservedAt = time.Unix(data.Current.LastUpdatedEpoch, 0)
+	}
+	return weather, nil
+}
+
+func (w *WeatherService) GetForecast(ctx context.Context, city string, days int) ([]domain.DailyForecast, error) {
+	query := url.Values{}
+	query.Set("q", city)
+	query.Set("days", strconv.Itoa(days))
+	var data struct {
+		Forecast struct {
+			ForecastDay []struct {
+				Date string `json:"date"`
+				Day  struct {
+					MaxTempC    float64 `json:"maxtemp_c"`
+					MinTempC    float64 `json:"mintemp_c"`
+					AvgHumidity float64 `json:"avghumidity"`
+					Condition   struct {
+						Text string `json:"text"`
+					} `json:"condition"`
+				} `json:"day"`
+			} `json:"forecastday"`
+		} `json:"forecast"`
+	}
+	if err := w.get(ctx, "/forecast.json", query, &data); err != nil {
+		return nil, err
+	}
+
+	forecasts := make([]domain.DailyForecast, 0, len(data.Forecast.ForecastDay))
+	for _, day := range data.Forecast.ForecastDay {
+		date, err := time.Parse(time.DateOnly, day.Date)
+		if err != nil {
+			return nil, fmt.Errorf("API error: invalid forecast date %q", day.Date)
+		}
+		forecasts = append(forecasts, domain.DailyForecast{
+			Date:           date,
+			MinTemperature: day.Day.MinTempC,
+			MaxTemperature: day.Day.MaxTempC,
+			Humidity:       int(math.Round(day.Day.AvgHumidity)),
+			Description:    day.Day.Condition.Text,
+		})
+	}
+	if len(forecasts) == 0 {
+		return nil, domain.ErrCityNotFound
+	}
+	return forecasts, nil
+}
+
+// get calls endpoint and decodes the response into out. weatherapi.com reports unknown cities
+// and other request errors in an error object in the body.
+func (w *WeatherService) get(ctx context.Context, endpoint string, query url.Values, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, w.baseURL+endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return &domain.ProviderUnavailableError{Err: err}
+	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+		return &domain.ProviderUnavailableError{
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+			Err:        fmt.Errorf("provider responded with %d", resp.StatusCode),
+		}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
 	if err != nil {
-		return domain.Weather{}, err
+		return &domain.ProviderUnavailableError{Err: err}
 	}
 
-	var data struct {
-		Current struct {
-			TempC     float64 `json:"temp_c"`
-			Humidity  int     `json:"humidity"`
-			Condition struct {
-				Text string `json:"text"`
-			} `json:"condition"`
-		} `json:"current"`
+	var apiErr struct {
 		Error struct {
 			Code    int    `json:"code"`
 			Message string `json:"message"`
 		} `json:"error"`
 	}
-	if err := json.Unmarshal(body, &data); err != nil {
-		return domain.Weather{}, err
+	if err := json.Unmarshal(body, &apiErr); err != nil {
+		return err
 	}
-
-	if data.Error.Code != 0 {
-		if data.Error.Code == 1006 {
-			return domain.Weather{}, domain.ErrCityNotFound
+	if apiErr.Error.Code != 0 {
+		if apiErr.Error.Code == 1006 {
+			return domain.ErrCityNotFound
 		}
-		return domain.Weather{}, fmt.Errorf("API error: %s", data.Error.Message)
+		return fmt.Errorf("API error: %s", apiErr.Error.Message)
 	}
+	return json.Unmarshal(body, out)
+}
 
-	if data.Current.TempC == 0 && data.Current.Humidity == 0 && data.Current.Condition.Text == "" {
-		return domain.Weather{}, domain.ErrCityNotFound
-	}
+// apiKeyTransport adds the API key below the tracing transport, so the key never ends up in
+// span attributes or in the URL of returned errors.
+type apiKeyTransport struct {
+	key  string
+	next http.RoundTripper
+}
 
-	return domain.Weather{
-		Temperature: data.Current.TempC,
-		Humidity:    data.Current.Humidity,
-		Description: data.Current.Condition.Text,
-	}, nil
+func (t apiKeyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	query := req.URL.Query()
+	query.Set("key", t.key)
+	req.URL.RawQuery = query.Encode()
+	return t.next.RoundTrip(req)
+}
+
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0)
+	}
+	return 0
 }
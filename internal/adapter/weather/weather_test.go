@@ -0,0 +1,217 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"weather-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeatherService_GetWeather(t *testing.T) {
+	var query map[string]string
+	var userAgent string
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/current.json", r.URL.Path)
+		query = map[string]string{"q": r.URL.Query().Get("q"), "key": r.URL.Query().Get("key")}
+		userAgent = r.Header.Get("User-Agent")
+		_, _ = w.Write([]byte(`{"current":{"last_updated_epoch":1748779200,"temp_c":20.5,"humidity":60,"condition":{"text":"Sunny"}}}`))
+	}))
+	defer provider.Close()
+	client, err := NewHTTPClient(ClientConfig{Timeout: time.Second, UserAgent: "weather-api-test"})
+	require.NoError(t, err)
+
+	for _, city := range []string{"Kyiv", "New York", "Rock & Roll?q=London"} {
+		t.Run(city, func(t *testing.T) {
+			weather, err := NewWeatherService(provider.URL+"/v1/", "secret-key", client).GetWeather(context.Background(), city)
+
+			require.NoError(t, err)
+			assert.Equal(t, domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny", ObservedAt: time.Unix(1748779200, 0)}, weather)
+			assert.Equal(t, map[string]string{"q": city, "key": "secret-key"}, query)
+			assert.Equal(t, "weather-api-test", userAgent)
+		})
+	}
+}
+
+func TestWeatherService_GetWeatherErrors(t *testing.T) {
+	tests := []struct {
+		name               string
+		handler            http.HandlerFunc
+		expectedErr        error
+		expectedRetryAfter time.Duration
+	}{
+		{
+			name: "unknown city",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":{"code":1006,"message":"No matching location found."}}`))
+			},
+			expectedErr: domain.ErrCityNotFound,
+		},
+		{
+			name: "empty current weather",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"current":{}}`))
+			},
+			expectedErr: domain.ErrCityNotFound,
+		},
+		{
+			name: "server error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadGateway)
+			},
+			expectedErr: domain.ErrProviderUnavailable,
+		},
+		{
+			name: "rate limited",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Retry-After", "120")
+				w.WriteHeader(http.StatusTooManyRequests)
+			},
+			expectedErr:        domain.ErrProviderUnavailable,
+			expectedRetryAfter: 2 * time.Minute,
+		},
+		{
+			name: "timeout",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-r.Context().Done():
+				case <-time.After(time.Second):
+				}
+			},
+			expectedErr: domain.ErrProviderUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := httptest.NewServer(tt.handler)
+			defer provider.Close()
+			client, err := NewHTTPClient(ClientConfig{Timeout: 50 * time.Millisecond})
+			require.NoError(t, err)
+
+			_, err = NewWeatherService(provider.URL, "secret-key", client).GetWeather(context.Background(), "Kyiv")
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.Equal(t, tt.expectedRetryAfter, domain.RetryAfter(err))
+			assert.NotContains(t, err.Error(), "secret-key")
+		})
+	}
+}
+
+func TestWeatherService_GetForecast(t *testing.T) {
+	var query map[string]string
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/forecast.json", r.URL.Path)
+		query = map[string]string{"q": r.URL.Query().Get("q"), "days": r.URL.Query().Get("days"), "key": r.URL.Query().Get("key")}
+		_, _ = w.Write([]byte(`{"forecast":{"forecastday":[
+			{"date":"2025-06-01","day":{"maxtemp_c":24.1,"mintemp_c":13.2,"avghumidity":61.5,"condition":{"text":"Sunny"}}},
+			{"date":"2025-06-02","day":{"maxtemp_c":19,"mintemp_c":11.4,"avghumidity":80.2,"condition":{"text":"Patchy rain nearby"}}}]}}`))
+	}))
+	defer provider.Close()
+
+	forecast, err := NewWeatherService(provider.URL+"/v1/", "secret-key", provider.Client()).GetForecast(context.Background(), "New York", 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"q": "New York", "days": "2", "key": "secret-key"}, query)
+	assert.Equal(t, []domain.DailyForecast{
+		{Date: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), MinTemperature: 13.2, MaxTemperature: 24.1, Humidity: 62, Description: "Sunny"},
+		{Date: time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), MinTemperature: 11.4, MaxTemperature: 19, Humidity: 80, Description: "Patchy rain nearby"},
+	}, forecast)
+}
+
+func TestWeatherService_GetForecastErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		handler       http.HandlerFunc
+		expectedErr   error
+		expectedError string
+	}{
+		{
+			name: "unknown city",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":{"code":1006,"message":"No matching location found."}}`))
+			},
+			expectedErr: domain.ErrCityNotFound,
+		},
+		{
+			name: "no forecast days",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"forecast":{"forecastday":[]}}`))
+			},
+			expectedErr: domain.ErrCityNotFound,
+		},
+		{
+			name: "invalid date",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"forecast":{"forecastday":[{"date":"01.06.2025","day":{}}]}}`))
+			},
+			expectedError: `API error: invalid forecast date "01.06.2025"`,
+		},
+		{
+			name: "server error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			},
+			expectedErr: domain.ErrProviderUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := httptest.NewServer(tt.handler)
+			defer provider.Close()
+
+			_, err := NewWeatherService(provider.URL, "secret-key", provider.Client()).GetForecast(context.Background(), "Kyiv", 3)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.EqualError(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestWeatherService_GetWeatherCancelled(t *testing.T) {
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer provider.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := NewWeatherService(provider.URL, "secret-key", provider.Client()).GetWeather(ctx, "Kyiv")
+
+	// A caller that gave up is not a provider failure, so it mustn't count against the breaker.
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.False(t, errors.Is(err, domain.ErrProviderUnavailable))
+}
+
+func TestNewHTTPClient_UsesProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		_, _ = w.Write([]byte(`{"current":{"temp_c":20.5,"humidity":60,"condition":{"text":"Sunny"}}}`))
+	}))
+	defer proxy.Close()
+	client, err := NewHTTPClient(ClientConfig{Timeout: time.Second, ProxyURL: proxy.URL})
+	require.NoError(t, err)
+
+	_, err = NewWeatherService("http://api.weather.invalid/v1", "secret-key", client).GetWeather(context.Background(), "Kyiv")
+
+	require.NoError(t, err)
+	assert.Equal(t, "http://api.weather.invalid/v1/current.json?key=secret-key&q=Kyiv", proxied)
+}
+
+func TestNewHTTPClient_RejectsInvalidProxy(t *testing.T) {
+	_, err := NewHTTPClient(ClientConfig{ProxyURL: "http://[::1"})
+
+	assert.ErrorContains(t, err, "invalid proxy URL")
+}
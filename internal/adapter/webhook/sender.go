@@ -0,0 +1,74 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/port"
+	"weather-api/internal/util"
+)
+
+const (
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+	SignatureHeader = "X-Webhook-Signature"
+)
+
+// Sender POSTs deliveries with a signature header of the form "t=<unix time>,v1=<hex HMAC>",
+// see Sign. Any 2xx response counts as delivered; redirects are not followed.
+type Sender struct {
+	client    *http.Client
+	userAgent string
+	now       func() time.Time
+}
+
+func NewSender(client *http.Client, userAgent string) port.WebhookSender {
+	traced := *client
+	traced.Transport = util.NewHostOnlyTracingTransport(client.Transport)
+	traced.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return &Sender{client: &traced, userAgent: userAgent, now: time.Now}
+}
+
+func (s *Sender) Send(ctx context.Context, hook domain.Webhook, delivery domain.WebhookDelivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return &domain.WebhookError{Err: err}
+	}
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", s.userAgent)
+	req.Header.Set(EventHeader, string(delivery.Event))
+	req.Header.Set(DeliveryHeader, delivery.ID)
+	req.Header.Set(SignatureHeader, "t="+timestamp+",v1="+Sign(hook.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return &domain.WebhookError{Err: err}
+	}
+	defer resp.Body.Close()
+	// Reading a bit of the body lets the connection be reused.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &domain.WebhookError{StatusCode: resp.StatusCode}
+	}
+	return nil
+}
+
+// Sign returns the hex HMAC-SHA256 of timestamp, a dot and body, keyed with secret. Receivers
+// recompute it to check that a delivery is ours, and compare the timestamp with their clock to
+// reject replays.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
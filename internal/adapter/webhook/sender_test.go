@@ -0,0 +1,118 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"weather-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSender_SendsSignedDelivery(t *testing.T) {
+	var received *http.Request
+	var body []byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	sender := NewSender(receiver.Client(), "weather-api-test").(*Sender)
+	sender.now = func() time.Time { return time.Unix(1748764800, 0) }
+	hook := domain.Webhook{SubscriptionID: 7, URL: receiver.URL + "/hooks", Secret: "whsec_test"}
+	delivery := domain.WebhookDelivery{ID: "delivery1", Event: domain.NotificationWeatherUpdate, Payload: []byte(`{"city":"Kyiv"}`)}
+
+	err := sender.Send(context.Background(), hook, delivery)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.MethodPost, received.Method)
+	assert.Equal(t, "/hooks", received.URL.Path)
+	assert.Equal(t, "application/json", received.Header.Get("Content-Type"))
+	assert.Equal(t, "weather-api-test", received.Header.Get("User-Agent"))
+	assert.Equal(t, "weather.update", received.Header.Get(EventHeader))
+	assert.Equal(t, "delivery1", received.Header.Get(DeliveryHeader))
+	assert.Equal(t, `{"city":"Kyiv"}`, string(body))
+
+	// Verify the signature the way a receiver would.
+	timestamp, signature, ok := strings.Cut(received.Header.Get(SignatureHeader), ",")
+	require.True(t, ok)
+	assert.Equal(t, "t=1748764800", timestamp)
+	assert.True(t, hmac.Equal([]byte("v1="+Sign("whsec_test", "1748764800", body)), []byte(signature)))
+	assert.NotEqual(t, Sign("other", "1748764800", body), strings.TrimPrefix(signature, "v1="))
+}
+
+func TestSender_Errors(t *testing.T) {
+	tests := []struct {
+		name              string
+		handler           http.HandlerFunc
+		expectedStatus    int
+		expectedPermanent bool
+	}{
+		{
+			name:              "client error is permanent",
+			handler:           func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusGone) },
+			expectedStatus:    http.StatusGone,
+			expectedPermanent: true,
+		},
+		{
+			name:           "rate limit can be retried",
+			handler:        func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusTooManyRequests) },
+			expectedStatus: http.StatusTooManyRequests,
+		},
+		{
+			name:           "server error can be retried",
+			handler:        func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusBadGateway) },
+			expectedStatus: http.StatusBadGateway,
+		},
+		{
+			name: "redirect is not followed",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/moved" {
+					t.Error("redirect was followed")
+				}
+				http.Redirect(w, r, "/moved", http.StatusTemporaryRedirect)
+			},
+			expectedStatus: http.StatusTemporaryRedirect,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiver := httptest.NewServer(tt.handler)
+			defer receiver.Close()
+
+			err := NewSender(receiver.Client(), "weather-api-test").Send(context.Background(),
+				domain.Webhook{URL: receiver.URL, Secret: "whsec_test"},
+				domain.WebhookDelivery{ID: "delivery1", Event: domain.NotificationPing, Payload: []byte(`{}`)})
+
+			var webhookErr *domain.WebhookError
+			require.True(t, errors.As(err, &webhookErr), err)
+			assert.Equal(t, tt.expectedStatus, webhookErr.StatusCode)
+			assert.Equal(t, tt.expectedPermanent, webhookErr.Permanent())
+		})
+	}
+}
+
+func TestSender_UnreachableEndpoint(t *testing.T) {
+	receiver := httptest.NewServer(http.NotFoundHandler())
+	url := receiver.URL
+	receiver.Close()
+
+	err := NewSender(&http.Client{Timeout: time.Second}, "weather-api-test").Send(context.Background(),
+		domain.Webhook{URL: url, Secret: "whsec_test"},
+		domain.WebhookDelivery{ID: "delivery1", Event: domain.NotificationPing, Payload: []byte(`{}`)})
+
+	var webhookErr *domain.WebhookError
+	require.True(t, errors.As(err, &webhookErr), err)
+	assert.Zero(t, webhookErr.StatusCode)
+	assert.False(t, webhookErr.Permanent())
+}
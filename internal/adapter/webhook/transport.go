@@ -0,0 +1,40 @@
+package webhook
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/util"
+)
+
+// NewPublicTransport returns a transport that only connects to public addresses, so that a
+// subscriber can't make the server POST to itself or to its private network. The check runs on
+// the resolved address of every connection, which a hostname that resolves to a public address
+// when the webhook is created and to a private one later doesn't get around. Proxies from the
+// environment are not used, since only the address of the proxy would be checked.
+func NewPublicTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   checkPublicAddress,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return transport
+}
+
+func checkPublicAddress(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", domain.ErrWebhookAddressForbidden, address)
+	}
+	if !util.PublicAddr(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", domain.ErrWebhookAddressForbidden, addrPort.Addr())
+	}
+	return nil
+}
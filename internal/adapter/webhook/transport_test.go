@@ -0,0 +1,70 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"weather-api/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublicTransport_RefusesLoopbackEndpoint(t *testing.T) {
+	called := false
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer receiver.Close()
+
+	sender := NewSender(&http.Client{Transport: NewPublicTransport()}, "weather-api-test")
+	hook := domain.Webhook{SubscriptionID: 7, URL: receiver.URL, Secret: "whsec_test"}
+
+	err := sender.Send(context.Background(), hook, domain.WebhookDelivery{ID: "delivery1", Payload: []byte(`{}`)})
+
+	assert.True(t, errors.Is(err, domain.ErrWebhookAddressForbidden), err)
+	var webhookErr *domain.WebhookError
+	require.True(t, errors.As(err, &webhookErr))
+	assert.True(t, webhookErr.Permanent())
+	assert.Equal(t, "webhook address is not allowed", webhookErr.Reason())
+	assert.False(t, called)
+}
+
+func TestCheckPublicAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		allowed bool
+	}{
+		{address: "93.184.216.34:443", allowed: true},
+		{address: "[2606:4700::1111]:443", allowed: true},
+		{address: "127.0.0.1:8080"},
+		{address: "10.0.0.5:80"},
+		{address: "172.16.3.4:80"},
+		{address: "192.168.1.1:80"},
+		{address: "169.254.169.254:80"},
+		{address: "100.64.0.1:80"},
+		{address: "0.0.0.0:80"},
+		{address: "255.255.255.255:80"},
+		{address: "[::1]:443"},
+		{address: "[::]:443"},
+		{address: "[fd00:ec2::254]:80"},
+		{address: "[fe80::1]:80"},
+		{address: "[::ffff:127.0.0.1]:80"},
+		{address: "[64:ff9b::a00:1]:80"},
+		{address: "localhost:80"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			err := checkPublicAddress("tcp", tt.address, nil)
+
+			if tt.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.Is(err, domain.ErrWebhookAddressForbidden), err)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrUnauthorized = errors.New("Unauthorized")
+	ErrForbidden    = errors.New("Forbidden")
+)
+
+type Scope string
+
+const (
+	ScopeRead    Scope = "read"
+	ScopeWrite   Scope = "write"
+	ScopeTrigger Scope = "trigger"
+)
+
+type APIKey struct {
+	Name   string
+	Hash   [32]byte
+	Scopes []Scope
+}
+
+func (k APIKey) HasScope(scope Scope) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type SubscriptionFilter struct {
+	Query     string
+	City      string
+	Frequency Frequency
+	Confirmed *bool
+	Limit     int
+	Offset    int
+	// AfterID restricts cursor-paginated listings to subscriptions with a greater ID.
+	AfterID int
+}
+
+// SubscriptionCursorPage is a page of subscriptions ordered by ID. NextCursor is empty on the
+// last page.
+type SubscriptionCursorPage struct {
+	Items      []Subscription
+	NextCursor string
+}
+
+type SubscriptionPage struct {
+	Items  []Subscription
+	Total  int
+	Limit  int
+	Offset int
+}
+
+type SubscriptionCount struct {
+	Frequency   Frequency `json:"frequency"`
+	IsConfirmed bool      `json:"is_confirmed"`
+	Count       int       `json:"count"`
+}
+
+type DeliveryKind string
+
+const (
+	DeliveryKindWeatherUpdate DeliveryKind = "weather_update"
+)
+
+type DeliveryStatus string
+
+const (
+	DeliveryStatusSent   DeliveryStatus = "sent"
+	DeliveryStatusFailed DeliveryStatus = "failed"
+)
+
+type Delivery struct {
+	RunID          int
+	SubscriptionID int
+	Kind           DeliveryKind
+	Status         DeliveryStatus
+	Error          string
+}
+
+type DeliveryStats struct {
+	Kind   DeliveryKind   `json:"kind"`
+	Status DeliveryStatus `json:"status"`
+	Count  int            `json:"count"`
+	LastAt time.Time      `json:"last_at"`
+}
+
+type AdminStats struct {
+	Subscriptions []SubscriptionCount `json:"subscriptions"`
+	Deliveries    []DeliveryStats     `json:"deliveries"`
+	Since         time.Time           `json:"since"`
+}
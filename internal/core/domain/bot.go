@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrBotDetected = errors.New("Verification failed")
+
+// BotCheck carries what a subscribe request brought to prove it was sent by a person.
+type BotCheck struct {
+	// Honeypot is a form field hidden from people; bots filling in every field set it.
+	Honeypot string
+	// Challenge and Nonce are a proof-of-work challenge issued by the server and its solution.
+	Challenge string
+	Nonce     string
+	// CaptchaToken is the response token of a CAPTCHA widget.
+	CaptchaToken string
+	ClientIP     string
+}
+
+// Challenge asks the client to find a nonce so that SHA-256(Challenge + ":" + nonce) starts
+// with Difficulty zero bits.
+type Challenge struct {
+	Challenge  string    `json:"challenge"`
+	Difficulty int       `json:"difficulty"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrFeedNotFound is returned for feeds in a format that isn't served.
+var ErrFeedNotFound = errors.New("Feed not found")
+
+// Observation is the weather of a city at one point in time, kept as history for the feeds.
+type Observation struct {
+	City    string
+	Weather Weather
+	// ObservedAt is when the provider measured the weather, or when it was looked up if the
+	// provider didn't say.
+	ObservedAt time.Time
+}
+
+// ObservationHistory is the recent weather of a city, newest first.
+type ObservationHistory struct {
+	City         string
+	Observations []Observation
+	// ExpiresAt is when the current weather of the city will next be looked up; zero if unknown.
+	ExpiresAt time.Time
+}
+
+// DailyForecast is the forecast for one day in the city's time zone. Date is midnight UTC of
+// that day.
+type DailyForecast struct {
+	Date           time.Time
+	MinTemperature float64
+	MaxTemperature float64
+	Humidity       int
+	Description    string
+}
@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+type HealthStatus string
+
+const (
+	HealthStatusUp   HealthStatus = "up"
+	HealthStatusDown HealthStatus = "down"
+)
+
+type ComponentHealth struct {
+	Name      string         `json:"name"`
+	Status    HealthStatus   `json:"status"`
+	Error     string         `json:"error,omitempty"`
+	LatencyMs int64          `json:"latency_ms"`
+	CheckedAt time.Time      `json:"checked_at"`
+	Details   map[string]any `json:"details,omitempty"`
+}
+
+type HealthReport struct {
+	Status     HealthStatus      `json:"status"`
+	Components []ComponentHealth `json:"components"`
+}
+
+type JobStatus struct {
+	Name    string    `json:"name"`
+	Running bool      `json:"running"`
+	NextRun time.Time `json:"next_run"`
+	LastRun *JobRun   `json:"last_run,omitempty"`
+}
+
+type SchedulerStatus struct {
+	Running bool        `json:"running"`
+	Jobs    []JobStatus `json:"jobs"`
+}
+
+type SystemStatus struct {
+	Health    HealthReport    `json:"health"`
+	Scheduler SchedulerStatus `json:"scheduler"`
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrJobRunNotFound = errors.New("Job run not found")
+	// ErrJobBusy is returned when a run can't start because another run of the job holds its lock.
+	ErrJobBusy = errors.New("Job is already running")
+	// ErrShuttingDown is returned for runs requested once the scheduler is stopping.
+	ErrShuttingDown = errors.New("Server is shutting down")
+)
+
+type JobRunStatus string
+
+const (
+	JobRunRunning     JobRunStatus = "running"
+	JobRunCompleted   JobRunStatus = "completed"
+	JobRunFailed      JobRunStatus = "failed"
+	JobRunInterrupted JobRunStatus = "interrupted"
+)
+
+type JobRun struct {
+	ID            int          `json:"id"`
+	Job           string       `json:"job"`
+	ScheduledTime time.Time    `json:"scheduled_time"`
+	Status        JobRunStatus `json:"status"`
+	Owner         string       `json:"owner"`
+	Error         string       `json:"error,omitempty"`
+	StartedAt     time.Time    `json:"started_at"`
+	FinishedAt    *time.Time   `json:"finished_at,omitempty"`
+	// Report is the outcome of an update run started through the admin API.
+	Report *RunReport `json:"report,omitempty"`
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 var (
 	ErrCityNotFound           = errors.New("City not found")
@@ -8,6 +11,12 @@ var (
 	ErrEmailAlreadySubscribed = errors.New("Email already subscribed")
 	ErrInvalidToken           = errors.New("Invalid token")
 	ErrTokenNotFound          = errors.New("Token not found")
+	ErrTokenExpired           = errors.New("Token expired")
+	ErrSubscriptionNotFound   = errors.New("Subscription not found")
+	ErrAlreadyConfirmed       = errors.New("Subscription already confirmed")
+	ErrPreconditionFailed     = errors.New("Subscription has been modified")
+	ErrPreconditionRequired   = errors.New("If-Match header is required")
+	ErrTooManyStreams         = errors.New("Too many live weather streams")
 )
 
 type Frequency string
@@ -17,10 +26,35 @@ const (
 	FrequencyHourly Frequency = "hourly"
 )
 
+type TokenPurpose string
+
+const (
+	TokenPurposeConfirm     TokenPurpose = "confirm"
+	TokenPurposeUnsubscribe TokenPurpose = "unsubscribe"
+)
+
+type TokenClaims struct {
+	SubscriptionID int
+	Purpose        TokenPurpose
+	ExpiresAt      time.Time
+}
+
 type Weather struct {
 	Temperature float64 `json:"temperature"`
 	Humidity    int     `json:"humidity"`
 	Description string  `json:"description"`
+	// ObservedAt is when the provider measured the conditions, if it says so.
+	ObservedAt time.Time `json:"-"`
+	// ExpiresAt is when a cached lookup will be refreshed; zero if it wasn't cached.
+	ExpiresAt time.Time `json:"-"`
+}
+
+// CityWeather is the outcome of the lookup for one city of a batch, or an update of a live
+// weather stream.
+type CityWeather struct {
+	City    string
+	Weather Weather
+	Err     error
 }
 
 type Subscription struct {
@@ -30,4 +64,18 @@ type Subscription struct {
 	Frequency   Frequency `json:"frequency"`
 	Token       string    `json:"token"`
 	IsConfirmed bool      `json:"is_confirmed"`
+	// Version is incremented by every update and backs the ETag of the v2 resource.
+	Version int     `json:"version"`
+	Channel Channel `json:"channel"`
+	// Address is where chat channels post updates: the Slack incoming webhook URL or the
+	// Telegram chat ID.
+	Address string `json:"-"`
+	// Webhook is only set on a new subscription on the webhook channel, to be stored with it.
+	Webhook *Webhook `json:"-"`
+}
+
+// SubscriptionPatch holds the fields of a partial update; nil fields are left unchanged.
+type SubscriptionPatch struct {
+	City      *string
+	Frequency *Frequency
 }
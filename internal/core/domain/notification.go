@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrWebhookNotFound = errors.New("Webhook not found")
+	ErrWebhookDisabled = errors.New("Webhook is disabled after repeated failures")
+	// ErrWebhookAddressForbidden is returned for deliveries to loopback, private and other
+	// addresses that aren't reachable from the internet.
+	ErrWebhookAddressForbidden = errors.New("Webhook address is not allowed")
+)
+
+// Channel is how a subscription receives its updates. The zero value means email.
+type Channel string
+
+const (
+	ChannelEmail    Channel = "email"
+	ChannelWebhook  Channel = "webhook"
+	ChannelSlack    Channel = "slack"
+	ChannelTelegram Channel = "telegram"
+)
+
+type NotificationEvent string
+
+const (
+	NotificationWeatherUpdate NotificationEvent = "weather.update"
+	NotificationPing          NotificationEvent = "ping"
+)
+
+// Notification is a message to a subscriber, with what every channel needs to render it.
+type Notification struct {
+	Event   NotificationEvent
+	City    string
+	Weather Weather
+	// Subject and Body are the rendered email.
+	Subject string
+	Body    string
+	// UnsubscribeToken ends the subscription when passed to /api/unsubscribe.
+	UnsubscribeToken string
+}
+
+// Webhook is the endpoint of a subscription on the webhook channel. Deliveries are signed with
+// Secret, and the webhook is disabled once too many of them failed in a row.
+type Webhook struct {
+	SubscriptionID int
+	URL            string
+	Secret         string
+	Failures       int
+	LastError      string
+	// DisabledAt is zero while the webhook is enabled.
+	DisabledAt time.Time
+}
+
+func (w Webhook) Disabled() bool {
+	return !w.DisabledAt.IsZero()
+}
+
+// WebhookDelivery is one JSON payload to POST to a webhook. Retries of a delivery keep its ID,
+// so that receivers can drop duplicates.
+type WebhookDelivery struct {
+	ID      string
+	Event   NotificationEvent
+	Payload []byte
+}
+
+// WebhookError is a failed webhook delivery. StatusCode is 0 when no response was received.
+type WebhookError struct {
+	StatusCode int
+	Err        error
+}
+
+func (e *WebhookError) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("webhook responded with %d", e.StatusCode)
+	}
+	return fmt.Sprintf("webhook request failed: %v", e.Err)
+}
+
+func (e *WebhookError) Unwrap() error {
+	return e.Err
+}
+
+// Permanent reports whether retrying can't help, because the address isn't allowed or the
+// endpoint rejected the delivery with a client error other than 408 Request Timeout or 429 Too
+// Many Requests.
+func (e *WebhookError) Permanent() bool {
+	if errors.Is(e.Err, ErrWebhookAddressForbidden) {
+		return true
+	}
+	return e.StatusCode >= 400 && e.StatusCode < 500 && e.StatusCode != 408 && e.StatusCode != 429
+}
+
+// Reason is the failure as shown to the owner of the webhook. Unlike Error, it leaves out the
+// underlying network error, which would tell what answers on the far side of a connection.
+func (e *WebhookError) Reason() string {
+	var timeout interface{ Timeout() bool }
+	switch {
+	case e.StatusCode != 0:
+		return e.Error()
+	case errors.Is(e.Err, ErrWebhookAddressForbidden):
+		return "webhook address is not allowed"
+	case errors.As(e.Err, &timeout) && timeout.Timeout():
+		return "webhook request timed out"
+	default:
+		return "webhook request failed"
+	}
+}
+
+// WebhookPing is the outcome of a test delivery.
+type WebhookPing struct {
+	Delivered  bool
+	StatusCode int
+	Error      string
+	Duration   time.Duration
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrProviderUnavailable = errors.New("Weather provider unavailable")
+
+// ProviderUnavailableError is an ErrProviderUnavailable that knows when the provider is worth
+// trying again, e.g. from a 429 response or an open circuit breaker.
+type ProviderUnavailableError struct {
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *ProviderUnavailableError) Error() string {
+	if e.Err != nil {
+		return ErrProviderUnavailable.Error() + ": " + e.Err.Error()
+	}
+	return ErrProviderUnavailable.Error()
+}
+
+func (e *ProviderUnavailableError) Is(target error) bool {
+	return target == ErrProviderUnavailable
+}
+
+func (e *ProviderUnavailableError) Unwrap() error {
+	return e.Err
+}
+
+// RetryAfter returns how long the caller should wait before retrying after err, or 0 if unknown.
+func RetryAfter(err error) time.Duration {
+	var unavailable *ProviderUnavailableError
+	if errors.As(err, &unavailable) {
+		return unavailable.RetryAfter
+	}
+	var limited *RateLimitError
+	if errors.As(err, &limited) {
+		return limited.RetryAfter
+	}
+	return 0
+}
+
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"
+	CircuitOpen     CircuitState = "open"
+	CircuitHalfOpen CircuitState = "half_open"
+)
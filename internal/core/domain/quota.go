@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrQuotaExhausted = errors.New("Weather provider quota exhausted")
+
+type QuotaPeriod string
+
+const (
+	QuotaPeriodDaily   QuotaPeriod = "daily"
+	QuotaPeriodMonthly QuotaPeriod = "monthly"
+)
+
+// QuotaMode tells how weather requests are served with respect to the provider's budget.
+type QuotaMode string
+
+const (
+	QuotaModeNormal    QuotaMode = "normal"
+	QuotaModeFallback  QuotaMode = "fallback"
+	QuotaModeCacheOnly QuotaMode = "cache_only"
+)
+
+type QuotaUsage struct {
+	Provider    string    `json:"provider"`
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	Calls       int       `json:"calls"`
+	Limit       int       `json:"limit"`
+	Mode        QuotaMode `json:"mode"`
+}
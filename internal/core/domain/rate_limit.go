@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+var ErrRateLimited = errors.New("Too many requests")
+
+// RateLimitError is an ErrRateLimited that tells when the next request will be allowed.
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return ErrRateLimited.Error()
+}
+
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// RateLimit allows bursts of up to Limit requests, refilled evenly over Period.
+type RateLimit struct {
+	Limit  int
+	Period time.Duration
+}
+
+// TokenBucket is the stored state of one rate limited key.
+type TokenBucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is how long until the next request is allowed, 0 when Allowed.
+	RetryAfter time.Duration
+}
+
+// NewTokenBucket returns a full bucket for l.
+func (l RateLimit) NewTokenBucket(now time.Time) TokenBucket {
+	return TokenBucket{Tokens: float64(l.Limit), UpdatedAt: now}
+}
+
+// Take refills bucket for the time passed since it was updated and takes one token from it
+// if there is one.
+func (l RateLimit) Take(bucket TokenBucket, now time.Time) (TokenBucket, RateLimitResult) {
+	capacity := float64(l.Limit)
+	perSecond := capacity / l.Period.Seconds()
+
+	elapsed := max(now.Sub(bucket.UpdatedAt).Seconds(), 0)
+	tokens := math.Min(capacity, bucket.Tokens+elapsed*perSecond)
+
+	result := RateLimitResult{Limit: l.Limit}
+	if tokens >= 1 {
+		tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - tokens) / perSecond)
+	}
+	result.Remaining = int(math.Floor(tokens))
+	result.Reset = seconds((capacity - tokens) / perSecond)
+	return TokenBucket{Tokens: tokens, UpdatedAt: now}, result
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Round(s * float64(time.Second)))
+}
@@ -0,0 +1,58 @@
+package domain
+
+import "time"
+
+type RunRequest struct {
+	RunID          int
+	Frequency      Frequency
+	City           string
+	SubscriptionID int
+	DryRun         bool
+}
+
+type RunItemStatus string
+
+const (
+	RunItemSent      RunItemStatus = "sent"
+	RunItemFailed    RunItemStatus = "failed"
+	RunItemSkipped   RunItemStatus = "skipped"
+	RunItemWouldSend RunItemStatus = "would_send"
+)
+
+type RunItem struct {
+	SubscriptionID int           `json:"subscription_id"`
+	Email          string        `json:"email"`
+	City           string        `json:"city"`
+	Status         RunItemStatus `json:"status"`
+	Subject        string        `json:"subject,omitempty"`
+	Body           string        `json:"body,omitempty"`
+	Error          string        `json:"error,omitempty"`
+}
+
+type RunReport struct {
+	RunID          int       `json:"run_id,omitempty"`
+	Frequency      Frequency `json:"frequency,omitempty"`
+	City           string    `json:"city,omitempty"`
+	SubscriptionID int       `json:"subscription_id,omitempty"`
+	DryRun         bool      `json:"dry_run"`
+	StartedAt      time.Time `json:"started_at"`
+	FinishedAt     time.Time `json:"finished_at"`
+	Total          int       `json:"total"`
+	Sent           int       `json:"sent"`
+	Failed         int       `json:"failed"`
+	Skipped        int       `json:"skipped"`
+	Error          string    `json:"error,omitempty"`
+	Items          []RunItem `json:"items"`
+}
+
+func (r *RunReport) Add(item RunItem) {
+	r.Items = append(r.Items, item)
+	switch item.Status {
+	case RunItemSent, RunItemWouldSend:
+		r.Sent++
+	case RunItemFailed:
+		r.Failed++
+	case RunItemSkipped:
+		r.Skipped++
+	}
+}
@@ -0,0 +1,14 @@
+package domain
+
+// ValidationError is an ErrInvalidInput that tells which fields were rejected and why.
+type ValidationError struct {
+	Fields map[string]string
+}
+
+func (e *ValidationError) Error() string {
+	return ErrInvalidInput.Error()
+}
+
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrInvalidInput
+}
@@ -0,0 +1,11 @@
+package port
+
+import (
+	"context"
+	"weather-api/internal/core/domain"
+)
+
+type BotVerifier interface {
+	// Verify returns domain.ErrBotDetected if the request looks automated.
+	Verify(ctx context.Context, check domain.BotCheck) error
+}
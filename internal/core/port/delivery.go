@@ -0,0 +1,13 @@
+package port
+
+import (
+	"context"
+	"time"
+	"weather-api/internal/core/domain"
+)
+
+type DeliveryRepository interface {
+	RecordDelivery(ctx context.Context, delivery domain.Delivery) error
+	GetDeliveryStats(ctx context.Context, since time.Time) ([]domain.DeliveryStats, error)
+	GetDeliveredSubscriptionIDs(ctx context.Context, runID int) (map[int]bool, error)
+}
@@ -0,0 +1,10 @@
+package port
+
+import "context"
+
+// MXResolver checks whether a domain can receive email.
+type MXResolver interface {
+	// AcceptsMail reports false only when the domain definitely has no mail server; lookup
+	// failures are returned as errors.
+	AcceptsMail(ctx context.Context, domain string) (bool, error)
+}
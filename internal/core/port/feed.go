@@ -0,0 +1,20 @@
+package port
+
+import (
+	"context"
+	"time"
+	"weather-api/internal/core/domain"
+)
+
+type ObservationRepository interface {
+	// RecordObservation stores an observation unless one of the city at the same time exists.
+	RecordObservation(ctx context.Context, observation domain.Observation) error
+	// GetObservations returns the latest observations of a city, newest first.
+	GetObservations(ctx context.Context, city string, limit int) ([]domain.Observation, error)
+	PruneObservations(ctx context.Context, before time.Time) error
+}
+
+type ForecastService interface {
+	// GetForecast returns the forecast for today and the following days, days in total.
+	GetForecast(ctx context.Context, city string, days int) ([]domain.DailyForecast, error)
+}
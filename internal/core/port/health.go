@@ -0,0 +1,9 @@
+package port
+
+import "context"
+
+// HealthChecker checks a dependency. Details are optional and shown to admins only.
+type HealthChecker interface {
+	Name() string
+	Check(ctx context.Context) (details map[string]any, err error)
+}
@@ -0,0 +1,21 @@
+package port
+
+import (
+	"context"
+	"time"
+	"weather-api/internal/core/domain"
+)
+
+type JobRunRepository interface {
+	// AcquireLock blocks until the job's lock is held or ctx is done. The lock is released when
+	// release is called or when the holding process dies.
+	AcquireLock(ctx context.Context, job string) (release func(), acquired bool, err error)
+	// ClaimRun records the run as started by owner, taking over runs left running or interrupted.
+	// It returns false when the run already completed or failed.
+	ClaimRun(ctx context.Context, job string, scheduledTime time.Time, owner string) (domain.JobRun, bool, error)
+	FinishRun(ctx context.Context, id int, status domain.JobRunStatus, errMsg string) error
+	GetLatestRuns(ctx context.Context) ([]domain.JobRun, error)
+	// GetRun returns the run with its report, or ErrJobRunNotFound.
+	GetRun(ctx context.Context, id int) (domain.JobRun, error)
+	SaveReport(ctx context.Context, id int, report domain.RunReport) error
+}
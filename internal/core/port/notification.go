@@ -0,0 +1,39 @@
+package port
+
+import (
+	"context"
+	"weather-api/internal/core/domain"
+)
+
+// NotificationChannel delivers notifications to subscribers over one kind of channel.
+type NotificationChannel interface {
+	Notify(ctx context.Context, sub domain.Subscription, notification domain.Notification) error
+}
+
+// WebhookSender POSTs a delivery to a webhook, signed with its secret. Failures are returned as
+// *domain.WebhookError.
+type WebhookSender interface {
+	Send(ctx context.Context, hook domain.Webhook, delivery domain.WebhookDelivery) error
+}
+
+// TelegramSender sends text messages with Telegram's HTML markup through the Bot API.
+type TelegramSender interface {
+	SendMessage(ctx context.Context, chatID, text string) error
+}
+
+// SlackSender posts a JSON message, e.g. with Block Kit blocks, to a Slack incoming webhook.
+// AcceptsWebhookURL reports whether webhookURL is an incoming webhook it posts to, so that
+// subscriptions with other URLs can be rejected when they are created.
+type SlackSender interface {
+	PostMessage(ctx context.Context, webhookURL string, message []byte) error
+	AcceptsWebhookURL(webhookURL string) bool
+}
+
+type WebhookRepository interface {
+	GetWebhook(ctx context.Context, subscriptionID int) (domain.Webhook, error)
+	// RecordWebhookSuccess resets the failures of the webhook and enables it again.
+	RecordWebhookSuccess(ctx context.Context, subscriptionID int) error
+	// RecordWebhookFailure counts a failed delivery and disables the webhook once disableAfter
+	// deliveries failed in a row; 0 never disables it. It returns the updated webhook.
+	RecordWebhookFailure(ctx context.Context, subscriptionID int, deliveryErr string, disableAfter int) (domain.Webhook, error)
+}
@@ -0,0 +1,15 @@
+package port
+
+import (
+	"context"
+	"time"
+)
+
+type QuotaRepository interface {
+	// IncrementUsage adds one call to the provider's usage in the period and returns the new total.
+	IncrementUsage(ctx context.Context, provider string, periodStart time.Time) (int, error)
+	GetUsage(ctx context.Context, provider string, periodStart time.Time) (int, error)
+	// MarkAlerted records that usage reached percent of the limit. It reports false if this or a
+	// higher threshold was already recorded, so that each alert is sent once across replicas.
+	MarkAlerted(ctx context.Context, provider string, periodStart time.Time, percent int) (bool, error)
+}
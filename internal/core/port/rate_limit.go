@@ -0,0 +1,15 @@
+package port
+
+import (
+	"context"
+	"time"
+	"weather-api/internal/core/domain"
+)
+
+type RateLimitStore interface {
+	// Take atomically takes a token from the bucket stored under key, creating a full one if
+	// there is none.
+	Take(ctx context.Context, key string, limit domain.RateLimit) (domain.RateLimitResult, error)
+	// Prune removes buckets not used since before.
+	Prune(ctx context.Context, before time.Time) error
+}
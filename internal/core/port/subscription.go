@@ -6,11 +6,23 @@ import (
 )
 
 type SubscriptionRepository interface {
-	CreateSubscription(ctx context.Context, sub domain.Subscription) error
+	CreateSubscription(ctx context.Context, sub domain.Subscription) (int, error)
 	GetSubscriptionByToken(ctx context.Context, token string) (domain.Subscription, error)
+	GetSubscriptionByID(ctx context.Context, id int) (domain.Subscription, error)
+	// GetSubscriptionByAddress returns the subscription of a chat, e.g. a Telegram chat ID.
+	GetSubscriptionByAddress(ctx context.Context, channel domain.Channel, address string) (domain.Subscription, error)
 	UpdateSubscription(ctx context.Context, sub domain.Subscription) error
 	DeleteSubscription(ctx context.Context, token string) error
 	GetSubscriptionsByFrequency(ctx context.Context, frequency string) ([]domain.Subscription, error)
 	IsEmailSubscribed(ctx context.Context, email string) (bool, error)
 	IsTokenExists(ctx context.Context, token string) (bool, error)
+	ListSubscriptions(ctx context.Context, filter domain.SubscriptionFilter) (domain.SubscriptionPage, error)
+	GetSubscriptionCounts(ctx context.Context) ([]domain.SubscriptionCount, error)
+	// UpdateSubscriptionByID stores the city, frequency and confirmation of sub if its Version is
+	// still current, and returns the updated subscription with the incremented version.
+	UpdateSubscriptionByID(ctx context.Context, sub domain.Subscription) (domain.Subscription, error)
+	// DeleteSubscriptionByID deletes the subscription if it is at version, or regardless of its
+	// version when version is 0.
+	DeleteSubscriptionByID(ctx context.Context, id, version int) error
+	ListSubscriptionsAfter(ctx context.Context, filter domain.SubscriptionFilter) ([]domain.Subscription, error)
 }
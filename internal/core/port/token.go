@@ -1,5 +1,14 @@
 package port
 
+import "weather-api/internal/core/domain"
+
 type TokenService interface {
 	GenerateToken() (string, error)
 }
+
+// SignedTokenService issues self-contained tokens that can be verified without a database lookup.
+type SignedTokenService interface {
+	IssueToken(subscriptionID int, purpose domain.TokenPurpose) (string, error)
+	ParseToken(token string, purpose domain.TokenPurpose) (domain.TokenClaims, error)
+	IsSignedToken(token string) bool
+}
@@ -1,7 +1,10 @@
 package port
 
-import "weather-api/internal/core/domain"
+import (
+	"context"
+	"weather-api/internal/core/domain"
+)
 
 type WeatherService interface {
-	GetWeather(city string) (domain.Weather, error)
+	GetWeather(ctx context.Context, city string) (domain.Weather, error)
 }
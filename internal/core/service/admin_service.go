@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"log/slog"
+	"strconv"
+	"time"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/port"
+	"weather-api/internal/util"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+type AdminService struct {
+	repo       port.SubscriptionRepository
+	deliveries port.DeliveryRepository
+	logger     *slog.Logger
+}
+
+func NewAdminService(repo port.SubscriptionRepository, deliveries port.DeliveryRepository, logger *slog.Logger) *AdminService {
+	return &AdminService{repo: repo, deliveries: deliveries, logger: logger}
+}
+
+func (s *AdminService) ListSubscriptions(ctx context.Context, filter domain.SubscriptionFilter) (domain.SubscriptionPage, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultPageSize
+	}
+	if filter.Limit > maxPageSize {
+		filter.Limit = maxPageSize
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	page, err := s.repo.ListSubscriptions(ctx, filter)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list subscriptions", "error", err)
+		return domain.SubscriptionPage{}, err
+	}
+	page.Limit, page.Offset = filter.Limit, filter.Offset
+	return page, nil
+}
+
+// ListSubscriptionsByCursor returns the page of subscriptions following cursor, which is empty
+// for the first page or the NextCursor of the previous one. Unlike offsets, cursors don't skip
+// or repeat subscriptions when others are created or deleted in between.
+func (s *AdminService) ListSubscriptionsByCursor(ctx context.Context, filter domain.SubscriptionFilter, cursor string) (domain.SubscriptionCursorPage, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	if cursor != "" {
+		afterID, err := decodeCursor(cursor)
+		if err != nil {
+			return domain.SubscriptionCursorPage{}, &domain.ValidationError{Fields: map[string]string{"cursor": "is invalid"}}
+		}
+		filter.AfterID = afterID
+	}
+	// One extra row tells whether there is a next page.
+	filter.Limit, filter.Offset = limit+1, 0
+
+	subs, err := s.repo.ListSubscriptionsAfter(ctx, filter)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to list subscriptions", "error", err)
+		return domain.SubscriptionCursorPage{}, err
+	}
+	page := domain.SubscriptionCursorPage{Items: subs}
+	if len(subs) > limit {
+		page.Items = subs[:limit]
+		page.NextCursor = encodeCursor(subs[limit-1].ID)
+	}
+	return page, nil
+}
+
+func (s *AdminService) GetSubscription(ctx context.Context, id int) (domain.Subscription, error) {
+	return s.repo.GetSubscriptionByID(ctx, id)
+}
+
+func (s *AdminService) ConfirmSubscription(ctx context.Context, id int) (domain.Subscription, error) {
+	ctx = util.WithSubscriptionID(ctx, id)
+	sub, err := s.repo.GetSubscriptionByID(ctx, id)
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+	if sub.IsConfirmed {
+		return sub, nil
+	}
+
+	sub.IsConfirmed = true
+	if err := s.repo.UpdateSubscription(ctx, sub); err != nil {
+		s.logger.ErrorContext(ctx, "Failed to force-confirm subscription", "error", err)
+		return domain.Subscription{}, err
+	}
+	s.logger.InfoContext(ctx, "Subscription force-confirmed by admin")
+	return sub, nil
+}
+
+func (s *AdminService) DeleteSubscription(ctx context.Context, id int) error {
+	ctx = util.WithSubscriptionID(ctx, id)
+	sub, err := s.repo.GetSubscriptionByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteSubscription(ctx, sub.Token); err != nil {
+		s.logger.ErrorContext(ctx, "Failed to delete subscription", "error", err)
+		return err
+	}
+	s.logger.InfoContext(ctx, "Subscription deleted by admin")
+	return nil
+}
+
+func (s *AdminService) GetStats(ctx context.Context, since time.Time) (domain.AdminStats, error) {
+	counts, err := s.repo.GetSubscriptionCounts(ctx)
+	if err != nil {
+		return domain.AdminStats{}, err
+	}
+	deliveries, err := s.deliveries.GetDeliveryStats(ctx, since)
+	if err != nil {
+		return domain.AdminStats{}, err
+	}
+	return domain.AdminStats{Subscriptions: counts, Deliveries: deliveries, Since: since}, nil
+}
+
+func encodeCursor(afterID int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(afterID)))
+}
+
+func decodeCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	afterID, err := strconv.Atoi(string(raw))
+	if err != nil || afterID <= 0 {
+		return 0, domain.ErrInvalidInput
+	}
+	return afterID, nil
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"weather-api/internal/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"weather-api/internal/core/domain"
+)
+
+func TestAdminService_ListSubscriptions(t *testing.T) {
+	ctx := context.Background()
+	page := domain.SubscriptionPage{
+		Items: []domain.Subscription{{ID: 1, Email: "user1@example.com", City: "Kyiv", Frequency: domain.FrequencyDaily}},
+		Total: 1,
+	}
+
+	tests := []struct {
+		name           string
+		filter         domain.SubscriptionFilter
+		expectedFilter domain.SubscriptionFilter
+	}{
+		{
+			name:           "default page size",
+			filter:         domain.SubscriptionFilter{Query: "kyiv"},
+			expectedFilter: domain.SubscriptionFilter{Query: "kyiv", Limit: defaultPageSize},
+		},
+		{
+			name:           "page size is capped",
+			filter:         domain.SubscriptionFilter{Limit: 1000, Offset: 40},
+			expectedFilter: domain.SubscriptionFilter{Limit: maxPageSize, Offset: 40},
+		},
+		{
+			name:           "negative offset",
+			filter:         domain.SubscriptionFilter{Limit: 10, Offset: -5},
+			expectedFilter: domain.SubscriptionFilter{Limit: 10},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.MockSubscriptionRepository{}
+			service := NewAdminService(repo, &mocks.MockDeliveryRepository{}, testLogger)
+			repo.On("ListSubscriptions", ctx, tt.expectedFilter).Return(page, nil)
+
+			result, err := service.ListSubscriptions(ctx, tt.filter)
+
+			assert.NoError(t, err)
+			assert.Equal(t, page.Items, result.Items)
+			assert.Equal(t, tt.expectedFilter.Limit, result.Limit)
+			assert.Equal(t, tt.expectedFilter.Offset, result.Offset)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAdminService_ListSubscriptionsByCursor(t *testing.T) {
+	ctx := context.Background()
+	subs := []domain.Subscription{{ID: 3}, {ID: 5}, {ID: 8}}
+
+	tests := []struct {
+		name               string
+		limit              int
+		cursor             string
+		expectedFilter     domain.SubscriptionFilter
+		result             []domain.Subscription
+		expectedItems      []domain.Subscription
+		expectedNextCursor string
+		expectedError      error
+	}{
+		{
+			name:               "first page",
+			limit:              2,
+			expectedFilter:     domain.SubscriptionFilter{Limit: 3},
+			result:             subs,
+			expectedItems:      subs[:2],
+			expectedNextCursor: encodeCursor(5),
+		},
+		{
+			name:           "last page",
+			limit:          2,
+			cursor:         encodeCursor(5),
+			expectedFilter: domain.SubscriptionFilter{Limit: 3, AfterID: 5},
+			result:         subs[2:],
+			expectedItems:  subs[2:],
+		},
+		{
+			name:           "default page size",
+			expectedFilter: domain.SubscriptionFilter{Limit: defaultPageSize + 1},
+			result:         subs,
+			expectedItems:  subs,
+		},
+		{
+			name:          "invalid cursor",
+			cursor:        "not a cursor",
+			expectedError: domain.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.MockSubscriptionRepository{}
+			service := NewAdminService(repo, &mocks.MockDeliveryRepository{}, testLogger)
+			if tt.expectedError == nil {
+				repo.On("ListSubscriptionsAfter", ctx, tt.expectedFilter).Return(tt.result, nil)
+			}
+
+			page, err := service.ListSubscriptionsByCursor(ctx, domain.SubscriptionFilter{Limit: tt.limit}, tt.cursor)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedItems, page.Items)
+				assert.Equal(t, tt.expectedNextCursor, page.NextCursor)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAdminService_ConfirmSubscription(t *testing.T) {
+	ctx := context.Background()
+	sub := domain.Subscription{ID: 1, Email: "user1@example.com", City: "Kyiv", Frequency: domain.FrequencyDaily, Token: "token123"}
+
+	tests := []struct {
+		name          string
+		setupMocks    func(repo *mocks.MockSubscriptionRepository)
+		verifyMocks   func(t *testing.T, repo *mocks.MockSubscriptionRepository)
+		expectedError error
+	}{
+		{
+			name: "success",
+			setupMocks: func(repo *mocks.MockSubscriptionRepository) {
+				repo.On("GetSubscriptionByID", mock.Anything, 1).Return(sub, nil)
+				confirmed := sub
+				confirmed.IsConfirmed = true
+				repo.On("UpdateSubscription", mock.Anything, confirmed).Return(nil)
+			},
+			verifyMocks: func(t *testing.T, repo *mocks.MockSubscriptionRepository) {
+				repo.AssertExpectations(t)
+			},
+		},
+		{
+			name: "already confirmed",
+			setupMocks: func(repo *mocks.MockSubscriptionRepository) {
+				confirmed := sub
+				confirmed.IsConfirmed = true
+				repo.On("GetSubscriptionByID", mock.Anything, 1).Return(confirmed, nil)
+			},
+			verifyMocks: func(t *testing.T, repo *mocks.MockSubscriptionRepository) {
+				repo.AssertNotCalled(t, "UpdateSubscription", mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name: "not found",
+			setupMocks: func(repo *mocks.MockSubscriptionRepository) {
+				repo.On("GetSubscriptionByID", mock.Anything, 1).Return(domain.Subscription{}, domain.ErrSubscriptionNotFound)
+			},
+			verifyMocks: func(t *testing.T, repo *mocks.MockSubscriptionRepository) {
+				repo.AssertNotCalled(t, "UpdateSubscription", mock.Anything, mock.Anything)
+			},
+			expectedError: domain.ErrSubscriptionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.MockSubscriptionRepository{}
+			service := NewAdminService(repo, &mocks.MockDeliveryRepository{}, testLogger)
+
+			tt.setupMocks(repo)
+
+			_, err := service.ConfirmSubscription(ctx, 1)
+
+			assert.Equal(t, tt.expectedError, err)
+			tt.verifyMocks(t, repo)
+		})
+	}
+}
+
+func TestAdminService_GetStats(t *testing.T) {
+	ctx := context.Background()
+	since := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	counts := []domain.SubscriptionCount{{Frequency: domain.FrequencyDaily, IsConfirmed: true, Count: 3}}
+	stats := []domain.DeliveryStats{{Kind: domain.DeliveryKindWeatherUpdate, Status: domain.DeliveryStatusSent, Count: 3}}
+
+	tests := []struct {
+		name          string
+		setupMocks    func(repo *mocks.MockSubscriptionRepository, deliveries *mocks.MockDeliveryRepository)
+		expected      domain.AdminStats
+		expectedError error
+	}{
+		{
+			name: "success",
+			setupMocks: func(repo *mocks.MockSubscriptionRepository, deliveries *mocks.MockDeliveryRepository) {
+				repo.On("GetSubscriptionCounts", ctx).Return(counts, nil)
+				deliveries.On("GetDeliveryStats", ctx, since).Return(stats, nil)
+			},
+			expected: domain.AdminStats{Subscriptions: counts, Deliveries: stats, Since: since},
+		},
+		{
+			name: "delivery stats error",
+			setupMocks: func(repo *mocks.MockSubscriptionRepository, deliveries *mocks.MockDeliveryRepository) {
+				repo.On("GetSubscriptionCounts", ctx).Return(counts, nil)
+				deliveries.On("GetDeliveryStats", ctx, since).Return([]domain.DeliveryStats(nil), errors.New("db error"))
+			},
+			expectedError: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.MockSubscriptionRepository{}
+			deliveries := &mocks.MockDeliveryRepository{}
+			service := NewAdminService(repo, deliveries, testLogger)
+
+			tt.setupMocks(repo, deliveries)
+
+			result, err := service.GetStats(ctx, since)
+
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"weather-api/internal/core/domain"
+)
+
+// APIKeyService authenticates admin API keys. Only SHA-256 hashes of the keys are kept in memory.
+type APIKeyService struct {
+	keys []domain.APIKey
+}
+
+func NewAPIKeyService(keys []domain.APIKey) *APIKeyService {
+	return &APIKeyService{keys: keys}
+}
+
+// ParseAPIKeys parses a comma separated list of name:sha256hex:scope|scope entries,
+// e.g. "ops:9f86d08...:read|write,ci:60303ae...:trigger".
+func ParseAPIKeys(raw string) ([]domain.APIKey, error) {
+	var keys []domain.APIKey
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if len(parts) != 3 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid API key entry %q, expected name:sha256hex:scopes", parts[0])
+		}
+
+		hash, err := hex.DecodeString(parts[1])
+		if err != nil || len(hash) != sha256.Size {
+			return nil, fmt.Errorf("API key %q must have a hex encoded SHA-256 hash", parts[0])
+		}
+
+		key := domain.APIKey{Name: parts[0]}
+		copy(key.Hash[:], hash)
+		for _, scope := range strings.Split(parts[2], "|") {
+			switch s := domain.Scope(strings.TrimSpace(scope)); s {
+			case domain.ScopeRead, domain.ScopeWrite, domain.ScopeTrigger:
+				key.Scopes = append(key.Scopes, s)
+			default:
+				return nil, fmt.Errorf("API key %q has unknown scope %q", parts[0], scope)
+			}
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (s *APIKeyService) Authenticate(rawKey string) (domain.APIKey, error) {
+	if rawKey == "" {
+		return domain.APIKey{}, domain.ErrUnauthorized
+	}
+	hash := sha256.Sum256([]byte(rawKey))
+	for _, key := range s.keys {
+		if subtle.ConstantTimeCompare(hash[:], key.Hash[:]) == 1 {
+			return key, nil
+		}
+	}
+	return domain.APIKey{}, domain.ErrUnauthorized
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"weather-api/internal/core/domain"
+)
+
+func TestAPIKeyService_Authenticate(t *testing.T) {
+	opsHash := sha256.Sum256([]byte("ops-secret"))
+	ciHash := sha256.Sum256([]byte("ci-secret"))
+	raw := "ops:" + hex.EncodeToString(opsHash[:]) + ":read|write, ci:" + hex.EncodeToString(ciHash[:]) + ":trigger"
+
+	keys, err := ParseAPIKeys(raw)
+	assert.NoError(t, err)
+	svc := NewAPIKeyService(keys)
+
+	tests := []struct {
+		name          string
+		rawKey        string
+		expectedName  string
+		expectedScope map[domain.Scope]bool
+		expectedError error
+	}{
+		{
+			name:          "read and write key",
+			rawKey:        "ops-secret",
+			expectedName:  "ops",
+			expectedScope: map[domain.Scope]bool{domain.ScopeRead: true, domain.ScopeWrite: true, domain.ScopeTrigger: false},
+		},
+		{
+			name:          "trigger only key",
+			rawKey:        "ci-secret",
+			expectedName:  "ci",
+			expectedScope: map[domain.Scope]bool{domain.ScopeRead: false, domain.ScopeWrite: false, domain.ScopeTrigger: true},
+		},
+		{
+			name:          "unknown key",
+			rawKey:        "guess",
+			expectedError: domain.ErrUnauthorized,
+		},
+		{
+			name:          "empty key",
+			rawKey:        "",
+			expectedError: domain.ErrUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := svc.Authenticate(tt.rawKey)
+
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedName, key.Name)
+			for scope, expected := range tt.expectedScope {
+				assert.Equal(t, expected, key.HasScope(scope), "scope %s", scope)
+			}
+		})
+	}
+}
+
+func TestParseAPIKeys_Invalid(t *testing.T) {
+	hash := sha256.Sum256([]byte("secret"))
+
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{name: "missing scopes", raw: "ops:" + hex.EncodeToString(hash[:])},
+		{name: "plain text key instead of hash", raw: "ops:secret:read"},
+		{name: "unknown scope", raw: "ops:" + hex.EncodeToString(hash[:]) + ":admin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseAPIKeys(tt.raw)
+			assert.Error(t, err)
+		})
+	}
+}
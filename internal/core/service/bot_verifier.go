@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/port"
+)
+
+// BotVerifiers passes a request only if every verifier does.
+type BotVerifiers []port.BotVerifier
+
+func (v BotVerifiers) Verify(ctx context.Context, check domain.BotCheck) error {
+	for _, verifier := range v {
+		if err := verifier.Verify(ctx, check); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HoneypotVerifier rejects requests that filled in the hidden honeypot field.
+type HoneypotVerifier struct{}
+
+func (HoneypotVerifier) Verify(_ context.Context, check domain.BotCheck) error {
+	if check.Honeypot != "" {
+		return domain.ErrBotDetected
+	}
+	return nil
+}
+
+// ProofOfWorkVerifier issues stateless, signed challenges and checks their solutions. A solved
+// challenge is accepted once per instance.
+type ProofOfWorkVerifier struct {
+	key        []byte
+	difficulty int
+	ttl        time.Duration
+	now        func() time.Time
+
+	mu   sync.Mutex
+	used map[string]time.Time
+}
+
+func NewProofOfWorkVerifier(key []byte, difficulty int, ttl time.Duration) *ProofOfWorkVerifier {
+	return &ProofOfWorkVerifier{key: key, difficulty: difficulty, ttl: ttl, now: time.Now, used: map[string]time.Time{}}
+}
+
+// IssueChallenge returns a challenge of the form "<expiry>.<random>.<signature>".
+func (v *ProofOfWorkVerifier) IssueChallenge() (domain.Challenge, error) {
+	seed := make([]byte, 16)
+	if _, err := rand.Read(seed); err != nil {
+		return domain.Challenge{}, err
+	}
+	expiresAt := v.now().Add(v.ttl).Truncate(time.Second)
+	unsigned := strconv.FormatInt(expiresAt.Unix(), 10) + "." + base64.RawURLEncoding.EncodeToString(seed)
+	return domain.Challenge{
+		Challenge:  unsigned + "." + sign(v.key, unsigned),
+		Difficulty: v.difficulty,
+		ExpiresAt:  expiresAt,
+	}, nil
+}
+
+func (v *ProofOfWorkVerifier) Verify(_ context.Context, check domain.BotCheck) error {
+	expiresAt, err := v.parse(check.Challenge)
+	if err != nil || check.Nonce == "" || len(check.Nonce) > 64 {
+		return domain.ErrBotDetected
+	}
+	sum := sha256.Sum256([]byte(check.Challenge + ":" + check.Nonce))
+	if leadingZeroBits(sum[:]) < v.difficulty {
+		return domain.ErrBotDetected
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	now := v.now()
+	for challenge, expiry := range v.used {
+		if now.After(expiry) {
+			delete(v.used, challenge)
+		}
+	}
+	if _, ok := v.used[check.Challenge]; ok {
+		return domain.ErrBotDetected
+	}
+	v.used[check.Challenge] = expiresAt
+	return nil
+}
+
+func (v *ProofOfWorkVerifier) parse(challenge string) (time.Time, error) {
+	i := strings.LastIndexByte(challenge, '.')
+	if i < 0 {
+		return time.Time{}, fmt.Errorf("malformed challenge")
+	}
+	unsigned, signature := challenge[:i], challenge[i+1:]
+	if !hmac.Equal([]byte(signature), []byte(sign(v.key, unsigned))) {
+		return time.Time{}, fmt.Errorf("invalid challenge signature")
+	}
+	rawExpiry, _, _ := strings.Cut(unsigned, ".")
+	expiry, err := strconv.ParseInt(rawExpiry, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	expiresAt := time.Unix(expiry, 0)
+	if v.now().After(expiresAt) {
+		return time.Time{}, fmt.Errorf("challenge expired")
+	}
+	return expiresAt, nil
+}
+
+func leadingZeroBits(sum []byte) int {
+	n := 0
+	for i := 0; i+8 <= len(sum); i += 8 {
+		word := binary.BigEndian.Uint64(sum[i:])
+		n += bits.LeadingZeros64(word)
+		if word != 0 {
+			break
+		}
+	}
+	return n
+}
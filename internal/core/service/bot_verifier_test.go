@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"weather-api/internal/core/domain"
+)
+
+func solveChallenge(challenge string, difficulty int) string {
+	for i := 0; ; i++ {
+		nonce := strconv.Itoa(i)
+		sum := sha256.Sum256([]byte(challenge + ":" + nonce))
+		if leadingZeroBits(sum[:]) >= difficulty {
+			return nonce
+		}
+	}
+}
+
+func TestProofOfWorkVerifier_Verify(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	verifier := NewProofOfWorkVerifier([]byte("0123456789abcdef0123456789abcdef"), 8, 5*time.Minute)
+	verifier.now = func() time.Time { return now }
+
+	issued, err := verifier.IssueChallenge()
+	require.NoError(t, err)
+	assert.Equal(t, 8, issued.Difficulty)
+	assert.Equal(t, now.Add(5*time.Minute), issued.ExpiresAt)
+	nonce := solveChallenge(issued.Challenge, issued.Difficulty)
+
+	tests := []struct {
+		name          string
+		check         domain.BotCheck
+		after         time.Duration
+		expectedError error
+	}{
+		{
+			name:          "missing solution",
+			check:         domain.BotCheck{Challenge: issued.Challenge},
+			expectedError: domain.ErrBotDetected,
+		},
+		{
+			name:          "tampered challenge",
+			check:         domain.BotCheck{Challenge: "9" + issued.Challenge, Nonce: nonce},
+			expectedError: domain.ErrBotDetected,
+		},
+		{
+			name:          "expired challenge",
+			check:         domain.BotCheck{Challenge: issued.Challenge, Nonce: nonce},
+			after:         6 * time.Minute,
+			expectedError: domain.ErrBotDetected,
+		},
+		{
+			name:  "solved challenge",
+			check: domain.BotCheck{Challenge: issued.Challenge, Nonce: nonce},
+		},
+		{
+			name:          "reused challenge",
+			check:         domain.BotCheck{Challenge: issued.Challenge, Nonce: nonce},
+			expectedError: domain.ErrBotDetected,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier.now = func() time.Time { return now.Add(tt.after) }
+			err := verifier.Verify(ctx, tt.check)
+			assert.Equal(t, tt.expectedError, err)
+		})
+	}
+}
+
+func TestBotVerifiers_Verify(t *testing.T) {
+	verifiers := BotVerifiers{HoneypotVerifier{}}
+
+	assert.NoError(t, verifiers.Verify(context.Background(), domain.BotCheck{}))
+	assert.Equal(t, domain.ErrBotDetected, verifiers.Verify(context.Background(), domain.BotCheck{Honeypot: "http://spam.example"}))
+}
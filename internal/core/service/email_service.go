@@ -2,49 +2,245 @@ package service
 
 import (
 	"context"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
 	"weather-api/internal/core/domain"
 	"weather-api/internal/core/port"
 	"weather-api/internal/util"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type EmailService struct {
 	repo       port.SubscriptionRepository
+	deliveries port.DeliveryRepository
 	weatherSvc port.WeatherService
-	emailSvc   port.EmailService
+	channels   port.NotificationChannel
+	signer     port.SignedTokenService
+	logger     *slog.Logger
 }
 
-func NewEmailService(repo port.SubscriptionRepository, weatherSvc port.WeatherService, emailSvc port.EmailService) *EmailService {
+// NewEmailService creates the service. Updates go out over channels, which picks the channel of
+// each subscription; despite the name of the service it need not be email.
+func NewEmailService(repo port.SubscriptionRepository, deliveries port.DeliveryRepository, weatherSvc port.WeatherService, channels port.NotificationChannel, signer port.SignedTokenService, logger *slog.Logger) *EmailService {
 	return &EmailService{
 		repo:       repo,
+		deliveries: deliveries,
 		weatherSvc: weatherSvc,
-		emailSvc:   emailSvc,
+		channels:   channels,
+		signer:     signer,
+		logger:     logger,
+	}
+}
+
+func (s *EmailService) SendUpdates(ctx context.Context, frequency domain.Frequency) error {
+	return s.SendScheduledUpdates(ctx, frequency, 0)
+}
+
+// SendScheduledUpdates sends the updates of a scheduled job run. Subscriptions that were already
+// sent to by an earlier attempt of the same run are skipped, so an interrupted run can be resumed.
+func (s *EmailService) SendScheduledUpdates(ctx context.Context, frequency domain.Frequency, runID int) error {
+	report, err := s.RunUpdates(ctx, domain.RunRequest{RunID: runID, Frequency: frequency})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to run updates", "frequency", frequency, "error", err)
+		return err
 	}
+	s.logger.InfoContext(ctx, "Finished updates", "frequency", frequency, "run_id", runID, "sent", report.Sent, "failed", report.Failed, "skipped", report.Skipped)
+	if report.Error != "" {
+		return errors.New(report.Error)
+	}
+	return nil
 }
 
-func (s *EmailService) SendUpdates(ctx context.Context, frequency domain.Frequency) {
-	subs, err := s.repo.GetSubscriptionsByFrequency(ctx, string(frequency))
+// RunUpdates sends weather updates to the subscriptions selected by req and reports the outcome
+// for each of them. In dry-run mode every email is rendered but nothing is sent or recorded.
+func (s *EmailService) RunUpdates(ctx context.Context, req domain.RunRequest) (_ domain.RunReport, err error) {
+	ctx, span := tracer.Start(ctx, "EmailService.RunUpdates")
+	span.SetAttributes(
+		attribute.Int("run.id", req.RunID),
+		attribute.String("frequency", string(req.Frequency)),
+		attribute.String("city", req.City),
+		attribute.Int("subscription.id", req.SubscriptionID),
+		attribute.Bool("dry_run", req.DryRun),
+	)
+	defer func() { endSpan(span, err) }()
+
+	if req.Frequency == "" && req.SubscriptionID == 0 {
+		return domain.RunReport{}, domain.ErrInvalidInput
+	}
+
+	report := domain.RunReport{
+		RunID:          req.RunID,
+		Frequency:      req.Frequency,
+		City:           req.City,
+		SubscriptionID: req.SubscriptionID,
+		DryRun:         req.DryRun,
+		StartedAt:      time.Now(),
+		Items:          []domain.RunItem{},
+	}
+
+	subs, err := s.selectSubscriptions(ctx, req)
 	if err != nil {
-		log.Printf("Failed to get %s subscriptions: %v", frequency, err)
-		return
+		return domain.RunReport{}, err
+	}
+	report.Total = len(subs)
+
+	delivered := map[int]bool{}
+	if req.RunID != 0 && !req.DryRun {
+		delivered, err = s.deliveries.GetDeliveredSubscriptionIDs(ctx, req.RunID)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to load run progress", "run_id", req.RunID, "error", err)
+			return domain.RunReport{}, err
+		}
+		if len(delivered) > 0 {
+			s.logger.InfoContext(ctx, "Resuming run", "run_id", req.RunID, "delivered", len(delivered))
+		}
 	}
-	s.sendUpdates(subs)
+
+	s.sendUpdates(ctx, subs, delivered, &report)
+	report.FinishedAt = time.Now()
+	span.SetAttributes(
+		attribute.Int("run.sent", report.Sent),
+		attribute.Int("run.failed", report.Failed),
+		attribute.Int("run.skipped", report.Skipped),
+	)
+	return report, nil
 }
 
-func (s *EmailService) sendUpdates(subs []domain.Subscription) {
+func (s *EmailService) selectSubscriptions(ctx context.Context, req domain.RunRequest) ([]domain.Subscription, error) {
+	var subs []domain.Subscription
+	if req.SubscriptionID != 0 {
+		sub, err := s.repo.GetSubscriptionByID(ctx, req.SubscriptionID)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to get subscription", "subscription_id", req.SubscriptionID, "error", err)
+			return nil, err
+		}
+		subs = []domain.Subscription{sub}
+	} else {
+		var err error
+		subs, err = s.repo.GetSubscriptionsByFrequency(ctx, string(req.Frequency))
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to get subscriptions", "frequency", req.Frequency, "error", err)
+			return nil, err
+		}
+	}
+
+	var selected []domain.Subscription
 	for _, sub := range subs {
+		if req.Frequency != "" && sub.Frequency != req.Frequency {
+			continue
+		}
+		if req.City != "" && !strings.EqualFold(sub.City, req.City) {
+			continue
+		}
+		selected = append(selected, sub)
+	}
+	return selected, nil
+}
+
+func (s *EmailService) sendUpdates(ctx context.Context, subs []domain.Subscription, delivered map[int]bool, report *domain.RunReport) {
+	for _, sub := range subs {
+		if err := ctx.Err(); err != nil {
+			s.logger.WarnContext(ctx, "Run interrupted", "processed", len(report.Items), "total", len(subs))
+			report.Error = fmt.Sprintf("run interrupted: %v", err)
+			return
+		}
+
+		subCtx := util.WithSubscriptionID(ctx, sub.ID)
+		item := domain.RunItem{SubscriptionID: sub.ID, Email: sub.Email, City: sub.City}
 		if !sub.IsConfirmed {
+			item.Status = domain.RunItemSkipped
+			item.Error = "subscription is not confirmed"
+			report.Add(item)
+			continue
+		}
+		if delivered[sub.ID] {
+			item.Status = domain.RunItemSkipped
+			item.Error = "already delivered in this run"
+			report.Add(item)
 			continue
 		}
-		weather, err := s.weatherSvc.GetWeather(sub.City)
+		subCtx, span := tracer.Start(subCtx, "EmailService.sendUpdate", trace.WithAttributes(attribute.Int("subscription.id", sub.ID), attribute.String("city", sub.City)))
+		weather, err := s.weatherSvc.GetWeather(subCtx, sub.City)
 		if err != nil {
-			log.Printf("Failed to get weather for %s: %v", sub.City, err)
+			endSpan(span, err)
+			s.logger.ErrorContext(subCtx, "Failed to get weather", "city", sub.City, "error", err)
+			item.Status = domain.RunItemFailed
+			item.Error = err.Error()
+			report.Add(item)
+			report.Error = fmt.Sprintf("weather lookup for %s failed, run aborted: %v", sub.City, err)
 			return
 		}
 
-		subject, htmlBody := util.BuildWeatherUpdateEmail(sub.City, weather.Temperature, weather.Humidity, weather.Description, sub.Token)
-		if err := s.emailSvc.SendEmail(sub.Email, subject, htmlBody); err != nil {
-			log.Printf("Failed to send email to %s: %v", sub.Email, err)
+		unsubscribeToken := s.unsubscribeToken(subCtx, sub)
+		item.Subject, item.Body = util.BuildWeatherUpdateEmail(sub.City, weather.Temperature, weather.Humidity, weather.Description, unsubscribeToken)
+		if report.DryRun {
+			span.End()
+			item.Status = domain.RunItemWouldSend
+			report.Add(item)
+			continue
+		}
+
+		err = s.channels.Notify(subCtx, sub, domain.Notification{
+			Event:            domain.NotificationWeatherUpdate,
+			City:             sub.City,
+			Weather:          weather,
+			Subject:          item.Subject,
+			Body:             item.Body,
+			UnsubscribeToken: unsubscribeToken,
+		})
+		if errors.Is(err, domain.ErrWebhookDisabled) {
+			span.End()
+			item.Status = domain.RunItemSkipped
+			item.Error = err.Error()
+			item.Body = ""
+			report.Add(item)
+			continue
 		}
+		if err != nil {
+			s.logger.ErrorContext(subCtx, "Failed to send weather update", "email", sub.Email, "channel", sub.Channel, "error", err)
+			item.Status = domain.RunItemFailed
+			item.Error = err.Error()
+		} else {
+			item.Status = domain.RunItemSent
+		}
+		s.recordDelivery(subCtx, report.RunID, sub, err)
+		endSpan(span, err)
+
+		item.Body = ""
+		report.Add(item)
+	}
+}
+
+func (s *EmailService) recordDelivery(ctx context.Context, runID int, sub domain.Subscription, sendErr error) {
+	delivery := domain.Delivery{
+		RunID:          runID,
+		SubscriptionID: sub.ID,
+		Kind:           domain.DeliveryKindWeatherUpdate,
+		Status:         domain.DeliveryStatusSent,
+	}
+	if sendErr != nil {
+		delivery.Status = domain.DeliveryStatusFailed
+		delivery.Error = sendErr.Error()
+	}
+	// Progress must be saved even when the run is being interrupted.
+	if err := s.deliveries.RecordDelivery(context.WithoutCancel(ctx), delivery); err != nil {
+		s.logger.ErrorContext(ctx, "Failed to record delivery", "error", err)
+	}
+}
+
+func (s *EmailService) unsubscribeToken(ctx context.Context, sub domain.Subscription) string {
+	if s.signer == nil {
+		return sub.Token
+	}
+	token, err := s.signer.IssueToken(sub.ID, domain.TokenPurposeUnsubscribe)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Failed to issue unsubscribe token, falling back to stored token", "error", err)
+		return sub.Token
 	}
+	return token
 }
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"weather-api/internal/mocks"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
 	"weather-api/internal/core/domain"
@@ -38,9 +39,9 @@ func TestEmailService_SendUpdates(t *testing.T) {
 					{Email: "user2@example.com", City: "Lviv", Frequency: frequency, Token: "token2", IsConfirmed: true},
 					{Email: "user3@example.com", City: "Odesa", Frequency: frequency, Token: "token3", IsConfirmed: false},
 				}
-				repo.On("GetSubscriptionsByFrequency", ctx, string(frequency)).Return(subs, nil)
-				weatherSvc.On("GetWeather", "Kyiv").Return(domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny"}, nil)
-				weatherSvc.On("GetWeather", "Lviv").Return(domain.Weather{Temperature: 18.0, Humidity: 65, Description: "Cloudy"}, nil)
+				repo.On("GetSubscriptionsByFrequency", mock.Anything, string(frequency)).Return(subs, nil)
+				weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny"}, nil)
+				weatherSvc.On("GetWeather", mock.Anything, "Lviv").Return(domain.Weather{Temperature: 18.0, Humidity: 65, Description: "Cloudy"}, nil)
 				subjectKyiv, bodyKyiv := util.BuildWeatherUpdateEmail("Kyiv", 20.5, 60, "Sunny", "token1")
 				subjectLviv, bodyLviv := util.BuildWeatherUpdateEmail("Lviv", 18.0, 65, "Cloudy", "token2")
 				emailSvc.On("SendEmail", "user1@example.com", subjectKyiv, bodyKyiv).Return(nil)
@@ -56,11 +57,11 @@ func TestEmailService_SendUpdates(t *testing.T) {
 		{
 			name: "repository error",
 			setupMocks: func(repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService, emailSvc *mocks.MockEmailService) {
-				repo.On("GetSubscriptionsByFrequency", ctx, string(frequency)).Return([]domain.Subscription(nil), errors.New("db error"))
+				repo.On("GetSubscriptionsByFrequency", mock.Anything, string(frequency)).Return([]domain.Subscription(nil), errors.New("db error"))
 			},
 			verifyMocks: func(t *testing.T, repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService, emailSvc *mocks.MockEmailService) {
 				repo.AssertExpectations(t)
-				weatherSvc.AssertNotCalled(t, "GetWeather", mock.Anything)
+				weatherSvc.AssertNotCalled(t, "GetWeather", mock.Anything, mock.Anything)
 				emailSvc.AssertNotCalled(t, "SendEmail", mock.Anything, mock.Anything, mock.Anything)
 			},
 		},
@@ -70,8 +71,8 @@ func TestEmailService_SendUpdates(t *testing.T) {
 				subs := []domain.Subscription{
 					{Email: "user1@example.com", City: "Kyiv", Frequency: frequency, Token: "token1", IsConfirmed: true},
 				}
-				repo.On("GetSubscriptionsByFrequency", ctx, string(frequency)).Return(subs, nil)
-				weatherSvc.On("GetWeather", "Kyiv").Return(domain.Weather{}, errors.New("API error"))
+				repo.On("GetSubscriptionsByFrequency", mock.Anything, string(frequency)).Return(subs, nil)
+				weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{}, errors.New("API error"))
 			},
 			verifyMocks: func(t *testing.T, repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService, emailSvc *mocks.MockEmailService) {
 				repo.AssertExpectations(t)
@@ -85,9 +86,9 @@ func TestEmailService_SendUpdates(t *testing.T) {
 				subs := []domain.Subscription{
 					{Email: "user1@example.com", City: "Kyiv", Frequency: frequency, Token: "token1", IsConfirmed: true},
 				}
-				repo.On("GetSubscriptionsByFrequency", ctx, string(frequency)).Return(subs, nil)
+				repo.On("GetSubscriptionsByFrequency", mock.Anything, string(frequency)).Return(subs, nil)
 				weather := domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny"}
-				weatherSvc.On("GetWeather", "Kyiv").Return(weather, nil)
+				weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(weather, nil)
 				subject, body := util.BuildWeatherUpdateEmail("Kyiv", 20.5, 60, "Sunny", "token1")
 				emailSvc.On("SendEmail", "user1@example.com", subject, body).Return(errors.New("SMTP error"))
 			},
@@ -100,11 +101,11 @@ func TestEmailService_SendUpdates(t *testing.T) {
 		{
 			name: "empty subscriptions",
 			setupMocks: func(repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService, emailSvc *mocks.MockEmailService) {
-				repo.On("GetSubscriptionsByFrequency", ctx, string(frequency)).Return([]domain.Subscription{}, nil)
+				repo.On("GetSubscriptionsByFrequency", mock.Anything, string(frequency)).Return([]domain.Subscription{}, nil)
 			},
 			verifyMocks: func(t *testing.T, repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService, emailSvc *mocks.MockEmailService) {
 				repo.AssertExpectations(t)
-				weatherSvc.AssertNotCalled(t, "GetWeather", mock.Anything)
+				weatherSvc.AssertNotCalled(t, "GetWeather", mock.Anything, mock.Anything)
 				emailSvc.AssertNotCalled(t, "SendEmail", mock.Anything, mock.Anything, mock.Anything)
 			},
 		},
@@ -115,11 +116,11 @@ func TestEmailService_SendUpdates(t *testing.T) {
 					{Email: "user1@example.com", City: "Kyiv", Frequency: frequency, Token: "token1", IsConfirmed: false},
 					{Email: "user2@example.com", City: "Lviv", Frequency: frequency, Token: "token2", IsConfirmed: false},
 				}
-				repo.On("GetSubscriptionsByFrequency", ctx, string(frequency)).Return(subs, nil)
+				repo.On("GetSubscriptionsByFrequency", mock.Anything, string(frequency)).Return(subs, nil)
 			},
 			verifyMocks: func(t *testing.T, repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService, emailSvc *mocks.MockEmailService) {
 				repo.AssertExpectations(t)
-				weatherSvc.AssertNotCalled(t, "GetWeather", mock.Anything)
+				weatherSvc.AssertNotCalled(t, "GetWeather", mock.Anything, mock.Anything)
 				emailSvc.AssertNotCalled(t, "SendEmail", mock.Anything, mock.Anything, mock.Anything)
 			},
 		},
@@ -130,7 +131,9 @@ func TestEmailService_SendUpdates(t *testing.T) {
 			repo := &mocks.MockSubscriptionRepository{}
 			weatherSvc := &mocks.MockWeatherService{}
 			emailSvc := &mocks.MockEmailService{}
-			service := NewEmailService(repo, weatherSvc, emailSvc)
+			deliveries := &mocks.MockDeliveryRepository{}
+			deliveries.On("RecordDelivery", mock.Anything, mock.Anything).Return(nil)
+			service := NewEmailService(repo, deliveries, weatherSvc, NotificationChannels{domain.ChannelEmail: NewEmailChannel(emailSvc)}, nil, testLogger)
 
 			tt.setupMocks(repo, weatherSvc, emailSvc)
 
@@ -157,7 +160,7 @@ func TestEmailService_sendUpdates(t *testing.T) {
 				{Email: "user2@example.com", City: "Lviv", Frequency: frequency, Token: "token2", IsConfirmed: false},
 			},
 			setupMocks: func(weatherSvc *mocks.MockWeatherService, emailSvc *mocks.MockEmailService) {
-				weatherSvc.On("GetWeather", "Kyiv").Return(domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny"}, nil)
+				weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny"}, nil)
 				subject, body := util.BuildWeatherUpdateEmail("Kyiv", 20.5, 60, "Sunny", "token1")
 				emailSvc.On("SendEmail", "user1@example.com", subject, body).Return(nil)
 			},
@@ -172,7 +175,7 @@ func TestEmailService_sendUpdates(t *testing.T) {
 			subscriptions: []domain.Subscription{},
 			setupMocks:    func(weatherSvc *mocks.MockWeatherService, emailSvc *mocks.MockEmailService) {},
 			verifyMocks: func(t *testing.T, weatherSvc *mocks.MockWeatherService, emailSvc *mocks.MockEmailService) {
-				weatherSvc.AssertNotCalled(t, "GetWeather", mock.Anything)
+				weatherSvc.AssertNotCalled(t, "GetWeather", mock.Anything, mock.Anything)
 				emailSvc.AssertNotCalled(t, "SendEmail", mock.Anything, mock.Anything, mock.Anything)
 			},
 		},
@@ -183,11 +186,11 @@ func TestEmailService_sendUpdates(t *testing.T) {
 				{Email: "user2@example.com", City: "Lviv", Frequency: frequency, Token: "token2", IsConfirmed: true},
 			},
 			setupMocks: func(weatherSvc *mocks.MockWeatherService, emailSvc *mocks.MockEmailService) {
-				weatherSvc.On("GetWeather", "Kyiv").Return(domain.Weather{}, errors.New("API error"))
+				weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{}, errors.New("API error"))
 			},
 			verifyMocks: func(t *testing.T, weatherSvc *mocks.MockWeatherService, emailSvc *mocks.MockEmailService) {
 				weatherSvc.AssertExpectations(t)
-				weatherSvc.AssertNotCalled(t, "GetWeather", "Lviv")
+				weatherSvc.AssertNotCalled(t, "GetWeather", mock.Anything, "Lviv")
 				emailSvc.AssertNotCalled(t, "SendEmail", mock.Anything, mock.Anything, mock.Anything)
 			},
 		},
@@ -198,13 +201,220 @@ func TestEmailService_sendUpdates(t *testing.T) {
 			repo := &mocks.MockSubscriptionRepository{}
 			weatherSvc := &mocks.MockWeatherService{}
 			emailSvc := &mocks.MockEmailService{}
-			service := NewEmailService(repo, weatherSvc, emailSvc)
+			deliveries := &mocks.MockDeliveryRepository{}
+			deliveries.On("RecordDelivery", mock.Anything, mock.Anything).Return(nil)
+			service := NewEmailService(repo, deliveries, weatherSvc, NotificationChannels{domain.ChannelEmail: NewEmailChannel(emailSvc)}, nil, testLogger)
 
 			tt.setupMocks(weatherSvc, emailSvc)
 
-			service.sendUpdates(tt.subscriptions)
+			service.sendUpdates(context.Background(), tt.subscriptions, map[int]bool{}, &domain.RunReport{})
 
 			tt.verifyMocks(t, weatherSvc, emailSvc)
 		})
 	}
 }
+
+func TestEmailService_sendUpdatesRecordsDeliveries(t *testing.T) {
+	ctx := context.Background()
+	subs := []domain.Subscription{
+		{ID: 1, Email: "user1@example.com", City: "Kyiv", Frequency: domain.FrequencyDaily, Token: "token1", IsConfirmed: true},
+		{ID: 2, Email: "user2@example.com", City: "Kyiv", Frequency: domain.FrequencyDaily, Token: "token2", IsConfirmed: true},
+	}
+
+	repo := &mocks.MockSubscriptionRepository{}
+	weatherSvc := &mocks.MockWeatherService{}
+	emailSvc := &mocks.MockEmailService{}
+	deliveries := &mocks.MockDeliveryRepository{}
+	service := NewEmailService(repo, deliveries, weatherSvc, NotificationChannels{domain.ChannelEmail: NewEmailChannel(emailSvc)}, nil, testLogger)
+
+	weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny"}, nil)
+	emailSvc.On("SendEmail", "user1@example.com", mock.Anything, mock.Anything).Return(nil)
+	emailSvc.On("SendEmail", "user2@example.com", mock.Anything, mock.Anything).Return(errors.New("SMTP error"))
+	deliveries.On("RecordDelivery", mock.Anything, domain.Delivery{SubscriptionID: 1, Kind: domain.DeliveryKindWeatherUpdate, Status: domain.DeliveryStatusSent}).Return(nil)
+	deliveries.On("RecordDelivery", mock.Anything, domain.Delivery{SubscriptionID: 2, Kind: domain.DeliveryKindWeatherUpdate, Status: domain.DeliveryStatusFailed, Error: "SMTP error"}).Return(nil)
+
+	service.sendUpdates(ctx, subs, map[int]bool{}, &domain.RunReport{})
+
+	deliveries.AssertExpectations(t)
+}
+
+func TestEmailService_sendUpdatesUsesChannelOfSubscription(t *testing.T) {
+	ctx := context.Background()
+	subs := []domain.Subscription{
+		{ID: 1, Email: "user1@example.com", City: "Kyiv", Frequency: domain.FrequencyDaily, Token: "token1", IsConfirmed: true, Channel: domain.ChannelEmail},
+		{ID: 2, Email: "ops@example.com", City: "Kyiv", Frequency: domain.FrequencyDaily, Token: "token2", IsConfirmed: true, Channel: domain.ChannelWebhook},
+		{ID: 3, Email: "dev@example.com", City: "Kyiv", Frequency: domain.FrequencyDaily, Token: "token3", IsConfirmed: true, Channel: domain.ChannelWebhook},
+	}
+	weather := domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny"}
+
+	weatherSvc := &mocks.MockWeatherService{}
+	emailSvc := &mocks.MockEmailService{}
+	webhooks := &mocks.MockNotificationChannel{}
+	deliveries := &mocks.MockDeliveryRepository{}
+	service := NewEmailService(&mocks.MockSubscriptionRepository{}, deliveries, weatherSvc, NotificationChannels{
+		domain.ChannelEmail:   NewEmailChannel(emailSvc),
+		domain.ChannelWebhook: webhooks,
+	}, nil, testLogger)
+
+	weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(weather, nil)
+	emailSvc.On("SendEmail", "user1@example.com", mock.Anything, mock.Anything).Return(nil)
+	webhooks.On("Notify", mock.Anything, subs[1], mock.MatchedBy(func(n domain.Notification) bool {
+		return n.Event == domain.NotificationWeatherUpdate && n.Weather == weather && n.UnsubscribeToken == "token2"
+	})).Return(nil)
+	webhooks.On("Notify", mock.Anything, subs[2], mock.Anything).Return(domain.ErrWebhookDisabled)
+	deliveries.On("RecordDelivery", mock.Anything, domain.Delivery{SubscriptionID: 1, Kind: domain.DeliveryKindWeatherUpdate, Status: domain.DeliveryStatusSent}).Return(nil)
+	deliveries.On("RecordDelivery", mock.Anything, domain.Delivery{SubscriptionID: 2, Kind: domain.DeliveryKindWeatherUpdate, Status: domain.DeliveryStatusSent}).Return(nil)
+
+	report := domain.RunReport{}
+	service.sendUpdates(ctx, subs, map[int]bool{}, &report)
+
+	assert.Equal(t, 2, report.Sent)
+	assert.Equal(t, 1, report.Skipped)
+	assert.Equal(t, domain.ErrWebhookDisabled.Error(), report.Items[2].Error)
+	emailSvc.AssertExpectations(t)
+	webhooks.AssertExpectations(t)
+	deliveries.AssertExpectations(t)
+}
+
+func TestEmailService_RunUpdates(t *testing.T) {
+	ctx := context.Background()
+	subs := []domain.Subscription{
+		{ID: 1, Email: "user1@example.com", City: "Kyiv", Frequency: domain.FrequencyDaily, Token: "token1", IsConfirmed: true},
+		{ID: 2, Email: "user2@example.com", City: "Lviv", Frequency: domain.FrequencyDaily, Token: "token2", IsConfirmed: true},
+	}
+	kyivWeather := domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny"}
+
+	tests := []struct {
+		name          string
+		request       domain.RunRequest
+		setupMocks    func(repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService, emailSvc *mocks.MockEmailService)
+		verify        func(t *testing.T, report domain.RunReport, emailSvc *mocks.MockEmailService, deliveries *mocks.MockDeliveryRepository)
+		expectedError error
+	}{
+		{
+			name:    "dry run renders emails without sending",
+			request: domain.RunRequest{Frequency: domain.FrequencyDaily, City: "kyiv", DryRun: true},
+			setupMocks: func(repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService, emailSvc *mocks.MockEmailService) {
+				repo.On("GetSubscriptionsByFrequency", mock.Anything, string(domain.FrequencyDaily)).Return(subs, nil)
+				weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(kyivWeather, nil)
+			},
+			verify: func(t *testing.T, report domain.RunReport, emailSvc *mocks.MockEmailService, deliveries *mocks.MockDeliveryRepository) {
+				subject, body := util.BuildWeatherUpdateEmail("Kyiv", 20.5, 60, "Sunny", "token1")
+				assert.Equal(t, 1, report.Total)
+				assert.Equal(t, 1, report.Sent)
+				assert.Equal(t, []domain.RunItem{{
+					SubscriptionID: 1, Email: "user1@example.com", City: "Kyiv",
+					Status: domain.RunItemWouldSend, Subject: subject, Body: body,
+				}}, report.Items)
+				emailSvc.AssertNotCalled(t, "SendEmail", mock.Anything, mock.Anything, mock.Anything)
+				deliveries.AssertNotCalled(t, "RecordDelivery", mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name:    "single subscription",
+			request: domain.RunRequest{SubscriptionID: 1},
+			setupMocks: func(repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService, emailSvc *mocks.MockEmailService) {
+				repo.On("GetSubscriptionByID", mock.Anything, 1).Return(subs[0], nil)
+				weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(kyivWeather, nil)
+				emailSvc.On("SendEmail", "user1@example.com", mock.Anything, mock.Anything).Return(nil)
+			},
+			verify: func(t *testing.T, report domain.RunReport, emailSvc *mocks.MockEmailService, deliveries *mocks.MockDeliveryRepository) {
+				assert.Equal(t, 1, report.Sent)
+				assert.Equal(t, domain.RunItemSent, report.Items[0].Status)
+				assert.Empty(t, report.Items[0].Body)
+				emailSvc.AssertExpectations(t)
+				deliveries.AssertNumberOfCalls(t, "RecordDelivery", 1)
+			},
+		},
+		{
+			name:    "weather error aborts the run",
+			request: domain.RunRequest{Frequency: domain.FrequencyDaily},
+			setupMocks: func(repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService, emailSvc *mocks.MockEmailService) {
+				repo.On("GetSubscriptionsByFrequency", mock.Anything, string(domain.FrequencyDaily)).Return(subs, nil)
+				weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{}, errors.New("API error"))
+			},
+			verify: func(t *testing.T, report domain.RunReport, emailSvc *mocks.MockEmailService, deliveries *mocks.MockDeliveryRepository) {
+				assert.Equal(t, 2, report.Total)
+				assert.Equal(t, 1, report.Failed)
+				assert.NotEmpty(t, report.Error)
+				emailSvc.AssertNotCalled(t, "SendEmail", mock.Anything, mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name:    "missing frequency and subscription",
+			request: domain.RunRequest{City: "Kyiv"},
+			setupMocks: func(repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService, emailSvc *mocks.MockEmailService) {
+			},
+			verify: func(t *testing.T, report domain.RunReport, emailSvc *mocks.MockEmailService, deliveries *mocks.MockDeliveryRepository) {
+			},
+			expectedError: domain.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.MockSubscriptionRepository{}
+			weatherSvc := &mocks.MockWeatherService{}
+			emailSvc := &mocks.MockEmailService{}
+			deliveries := &mocks.MockDeliveryRepository{}
+			deliveries.On("RecordDelivery", mock.Anything, mock.Anything).Return(nil)
+			service := NewEmailService(repo, deliveries, weatherSvc, NotificationChannels{domain.ChannelEmail: NewEmailChannel(emailSvc)}, nil, testLogger)
+
+			tt.setupMocks(repo, weatherSvc, emailSvc)
+
+			report, err := service.RunUpdates(ctx, tt.request)
+
+			assert.Equal(t, tt.expectedError, err)
+			tt.verify(t, report, emailSvc, deliveries)
+		})
+	}
+}
+
+func TestEmailService_SendScheduledUpdatesResumesRun(t *testing.T) {
+	ctx := context.Background()
+	runID := 7
+	subs := []domain.Subscription{
+		{ID: 1, Email: "user1@example.com", City: "Kyiv", Frequency: domain.FrequencyDaily, Token: "token1", IsConfirmed: true},
+		{ID: 2, Email: "user2@example.com", City: "Kyiv", Frequency: domain.FrequencyDaily, Token: "token2", IsConfirmed: true},
+	}
+
+	repo := &mocks.MockSubscriptionRepository{}
+	weatherSvc := &mocks.MockWeatherService{}
+	emailSvc := &mocks.MockEmailService{}
+	deliveries := &mocks.MockDeliveryRepository{}
+	service := NewEmailService(repo, deliveries, weatherSvc, NotificationChannels{domain.ChannelEmail: NewEmailChannel(emailSvc)}, nil, testLogger)
+
+	repo.On("GetSubscriptionsByFrequency", mock.Anything, string(domain.FrequencyDaily)).Return(subs, nil)
+	deliveries.On("GetDeliveredSubscriptionIDs", mock.Anything, runID).Return(map[int]bool{1: true}, nil)
+	weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny"}, nil)
+	emailSvc.On("SendEmail", "user2@example.com", mock.Anything, mock.Anything).Return(nil)
+	deliveries.On("RecordDelivery", mock.Anything, domain.Delivery{RunID: runID, SubscriptionID: 2, Kind: domain.DeliveryKindWeatherUpdate, Status: domain.DeliveryStatusSent}).Return(nil)
+
+	err := service.SendScheduledUpdates(ctx, domain.FrequencyDaily, runID)
+
+	assert.NoError(t, err)
+	emailSvc.AssertExpectations(t)
+	emailSvc.AssertNotCalled(t, "SendEmail", "user1@example.com", mock.Anything, mock.Anything)
+	deliveries.AssertExpectations(t)
+}
+
+func TestEmailService_RunUpdatesStopsWhenInterrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	subs := []domain.Subscription{
+		{ID: 1, Email: "user1@example.com", City: "Kyiv", Frequency: domain.FrequencyDaily, Token: "token1", IsConfirmed: true},
+	}
+
+	repo := &mocks.MockSubscriptionRepository{}
+	weatherSvc := &mocks.MockWeatherService{}
+	emailSvc := &mocks.MockEmailService{}
+	deliveries := &mocks.MockDeliveryRepository{}
+	service := NewEmailService(repo, deliveries, weatherSvc, NotificationChannels{domain.ChannelEmail: NewEmailChannel(emailSvc)}, nil, testLogger)
+	repo.On("GetSubscriptionsByFrequency", mock.Anything, string(domain.FrequencyDaily)).Return(subs, nil)
+
+	err := service.SendUpdates(ctx, domain.FrequencyDaily)
+
+	assert.Error(t, err)
+	weatherSvc.AssertNotCalled(t, "GetWeather", mock.Anything, mock.Anything)
+	emailSvc.AssertNotCalled(t, "SendEmail", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/port"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ObservationRecorder stores the weather it looks up as observations, which make up the history
+// of the feeds. It belongs below the cache, so that each observation is recorded about once.
+type ObservationRecorder struct {
+	next         port.WeatherService
+	observations port.ObservationRepository
+	logger       *slog.Logger
+	now          func() time.Time
+}
+
+func NewObservationRecorder(next port.WeatherService, observations port.ObservationRepository, logger *slog.Logger) *ObservationRecorder {
+	return &ObservationRecorder{next: next, observations: observations, logger: logger, now: time.Now}
+}
+
+func (r *ObservationRecorder) GetWeather(ctx context.Context, city string) (domain.Weather, error) {
+	weather, err := r.next.GetWeather(ctx, city)
+	if err != nil {
+		return domain.Weather{}, err
+	}
+	observation := domain.Observation{City: strings.TrimSpace(city), Weather: weather, ObservedAt: weather.ObservedAt}
+	if observation.ObservedAt.IsZero() {
+		observation.ObservedAt = r.now()
+	}
+	// The lookup succeeded, so a caller that gives up now still shouldn't lose the observation.
+	if err := r.observations.RecordObservation(context.WithoutCancel(ctx), observation); err != nil {
+		r.logger.WarnContext(ctx, "Failed to record observation", "city", city, "error", err)
+	}
+	return weather, nil
+}
+
+// FeedService backs the per-city feeds: the recent weather from the stored observations and a
+// daily forecast.
+type FeedService struct {
+	weatherSvc   port.WeatherService
+	observations port.ObservationRepository
+	forecasts    port.ForecastService
+	historySize  int
+	forecastDays int
+	logger       *slog.Logger
+}
+
+// NewFeedService creates the service. Histories hold the latest historySize observations and
+// forecasts cover forecastDays days, today included.
+func NewFeedService(weatherSvc port.WeatherService, observations port.ObservationRepository, forecasts port.ForecastService, historySize, forecastDays int, logger *slog.Logger) *FeedService {
+	return &FeedService{
+		weatherSvc:   weatherSvc,
+		observations: observations,
+		forecasts:    forecasts,
+		historySize:  historySize,
+		forecastDays: forecastDays,
+		logger:       logger,
+	}
+}
+
+// History returns the recent weather of city. The current weather is looked up first, which
+// checks that the city exists and records a new observation once the cached one has expired.
+// While the provider is unavailable the stored history is served on its own.
+func (s *FeedService) History(ctx context.Context, city string) (_ domain.ObservationHistory, err error) {
+	ctx, span := tracer.Start(ctx, "FeedService.History")
+	span.SetAttributes(attribute.String("city", city))
+	defer func() { endSpan(span, err) }()
+
+	city, problem := normalizeCity(city)
+	if problem != "" {
+		return domain.ObservationHistory{}, &domain.ValidationError{Fields: map[string]string{"city": problem}}
+	}
+	current, lookupErr := s.weatherSvc.GetWeather(ctx, city)
+	if lookupErr != nil && !errors.Is(lookupErr, domain.ErrProviderUnavailable) {
+		return domain.ObservationHistory{}, lookupErr
+	}
+
+	observations, err := s.observations.GetObservations(ctx, city, s.historySize)
+	if err != nil {
+		return domain.ObservationHistory{}, err
+	}
+	if len(observations) == 0 && lookupErr != nil {
+		return domain.ObservationHistory{}, lookupErr
+	}
+	if lookupErr != nil {
+		s.logger.WarnContext(ctx, "Serving stored observations while the provider is unavailable", "city", city, "error", lookupErr)
+	}
+	return domain.ObservationHistory{City: city, Observations: observations, ExpiresAt: current.ExpiresAt}, nil
+}
+
+// Forecast returns the daily forecast of city.
+func (s *FeedService) Forecast(ctx context.Context, city string) (_ []domain.DailyForecast, err error) {
+	ctx, span := tracer.Start(ctx, "FeedService.Forecast")
+	span.SetAttributes(attribute.String("city", city))
+	defer func() { endSpan(span, err) }()
+
+	city, problem := normalizeCity(city)
+	if problem != "" {
+		return nil, &domain.ValidationError{Fields: map[string]string{"city": problem}}
+	}
+	forecast, err := s.forecasts.GetForecast(ctx, city, s.forecastDays)
+	if err != nil {
+		if !errors.Is(err, domain.ErrCityNotFound) {
+			s.logger.ErrorContext(ctx, "Failed to get forecast", "city", city, "error", err)
+		}
+		return nil, err
+	}
+	return forecast, nil
+}
+
+// PruneObservations deletes observations older than retention.
+func (s *FeedService) PruneObservations(ctx context.Context, retention time.Duration) error {
+	return s.observations.PruneObservations(ctx, time.Now().Add(-retention))
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestFeedService_History(t *testing.T) {
+	ctx := context.Background()
+	expiresAt := time.Date(2025, 6, 1, 12, 10, 0, 0, time.UTC)
+	observations := []domain.Observation{
+		{City: "Kyiv", Weather: domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny"}, ObservedAt: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)},
+	}
+	unavailable := &domain.ProviderUnavailableError{}
+	dbErr := errors.New("connection refused")
+
+	tests := []struct {
+		name            string
+		city            string
+		setupMocks      func(weatherSvc *mocks.MockWeatherService, history *mocks.MockObservationRepository)
+		expectedHistory domain.ObservationHistory
+		expectedError   error
+	}{
+		{
+			name: "returns stored observations",
+			city: " Kyiv ",
+			setupMocks: func(weatherSvc *mocks.MockWeatherService, history *mocks.MockObservationRepository) {
+				weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{ExpiresAt: expiresAt}, nil)
+				history.On("GetObservations", mock.Anything, "Kyiv", 48).Return(observations, nil)
+			},
+			expectedHistory: domain.ObservationHistory{City: "Kyiv", Observations: observations, ExpiresAt: expiresAt},
+		},
+		{
+			name: "serves history while the provider is unavailable",
+			city: "Kyiv",
+			setupMocks: func(weatherSvc *mocks.MockWeatherService, history *mocks.MockObservationRepository) {
+				weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{}, unavailable)
+				history.On("GetObservations", mock.Anything, "Kyiv", 48).Return(observations, nil)
+			},
+			expectedHistory: domain.ObservationHistory{City: "Kyiv", Observations: observations},
+		},
+		{
+			name: "fails without history while the provider is unavailable",
+			city: "Kyiv",
+			setupMocks: func(weatherSvc *mocks.MockWeatherService, history *mocks.MockObservationRepository) {
+				weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{}, unavailable)
+				history.On("GetObservations", mock.Anything, "Kyiv", 48).Return([]domain.Observation{}, nil)
+			},
+			expectedError: unavailable,
+		},
+		{
+			name: "rejects unknown city",
+			city: "Atlantis",
+			setupMocks: func(weatherSvc *mocks.MockWeatherService, history *mocks.MockObservationRepository) {
+				weatherSvc.On("GetWeather", mock.Anything, "Atlantis").Return(domain.Weather{}, domain.ErrCityNotFound)
+			},
+			expectedError: domain.ErrCityNotFound,
+		},
+		{
+			name:          "rejects invalid city",
+			city:          "   ",
+			setupMocks:    func(weatherSvc *mocks.MockWeatherService, history *mocks.MockObservationRepository) {},
+			expectedError: &domain.ValidationError{Fields: map[string]string{"city": "is required"}},
+		},
+		{
+			name: "returns repository error",
+			city: "Kyiv",
+			setupMocks: func(weatherSvc *mocks.MockWeatherService, history *mocks.MockObservationRepository) {
+				weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{}, nil)
+				history.On("GetObservations", mock.Anything, "Kyiv", 48).Return([]domain.Observation(nil), dbErr)
+			},
+			expectedError: dbErr,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			weatherSvc := &mocks.MockWeatherService{}
+			history := &mocks.MockObservationRepository{}
+			tt.setupMocks(weatherSvc, history)
+
+			result, err := NewFeedService(weatherSvc, history, &mocks.MockForecastService{}, 48, 3, testLogger).History(ctx, tt.city)
+
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expectedHistory, result)
+			weatherSvc.AssertExpectations(t)
+			history.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFeedService_Forecast(t *testing.T) {
+	forecast := []domain.DailyForecast{{Date: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), MinTemperature: 14, MaxTemperature: 22, Humidity: 60, Description: "Sunny"}}
+	forecasts := &mocks.MockForecastService{}
+	forecasts.On("GetForecast", mock.Anything, "Kyiv", 3).Return(forecast, nil)
+	feeds := NewFeedService(&mocks.MockWeatherService{}, &mocks.MockObservationRepository{}, forecasts, 48, 3, testLogger)
+
+	result, err := feeds.Forecast(context.Background(), "Kyiv ")
+
+	assert.NoError(t, err)
+	assert.Equal(t, forecast, result)
+
+	_, err = feeds.Forecast(context.Background(), "")
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+}
+
+func TestObservationRecorder_GetWeather(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 1, 12, 5, 0, 0, time.UTC)
+	observedAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		weather       domain.Weather
+		lookupErr     error
+		recordErr     error
+		expectedObs   *domain.Observation
+		expectedError error
+	}{
+		{
+			name:        "records observation",
+			weather:     domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny", ObservedAt: observedAt},
+			expectedObs: &domain.Observation{City: "Kyiv", Weather: domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny", ObservedAt: observedAt}, ObservedAt: observedAt},
+		},
+		{
+			name:        "dates observation without observation time",
+			weather:     domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny"},
+			expectedObs: &domain.Observation{City: "Kyiv", Weather: domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny"}, ObservedAt: now},
+		},
+		{
+			name:        "ignores recording error",
+			weather:     domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny", ObservedAt: observedAt},
+			recordErr:   errors.New("connection refused"),
+			expectedObs: &domain.Observation{City: "Kyiv", Weather: domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny", ObservedAt: observedAt}, ObservedAt: observedAt},
+		},
+		{
+			name:          "records nothing when the lookup fails",
+			lookupErr:     domain.ErrCityNotFound,
+			expectedError: domain.ErrCityNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &mocks.MockWeatherService{}
+			next.On("GetWeather", mock.Anything, " Kyiv").Return(tt.weather, tt.lookupErr)
+			history := &mocks.MockObservationRepository{}
+			if tt.expectedObs != nil {
+				history.On("RecordObservation", mock.Anything, *tt.expectedObs).Return(tt.recordErr)
+			}
+			recorder := NewObservationRecorder(next, history, testLogger)
+			recorder.now = func() time.Time { return now }
+
+			weather, err := recorder.GetWeather(ctx, " Kyiv")
+
+			assert.Equal(t, tt.expectedError, err)
+			if tt.expectedError == nil {
+				assert.Equal(t, tt.weather, weather)
+			}
+			history.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/port"
+)
+
+// HealthService runs dependency checks concurrently with a per-check timeout and caches the
+// result, so frequent probes don't hammer the database, the SMTP server or the weather provider.
+type HealthService struct {
+	checkers  []port.HealthChecker
+	timeout   time.Duration
+	cacheTTL  time.Duration
+	scheduler *Scheduler
+	runs      port.JobRunRepository
+	logger    *slog.Logger
+	now       func() time.Time
+
+	mu        sync.Mutex
+	cached    domain.HealthReport
+	checkedAt time.Time
+}
+
+func NewHealthService(checkers []port.HealthChecker, timeout, cacheTTL time.Duration, scheduler *Scheduler, runs port.JobRunRepository, logger *slog.Logger) *HealthService {
+	return &HealthService{
+		checkers:  checkers,
+		timeout:   timeout,
+		cacheTTL:  cacheTTL,
+		scheduler: scheduler,
+		runs:      runs,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+func (s *HealthService) Readiness(ctx context.Context) domain.HealthReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.checkedAt.IsZero() && s.now().Sub(s.checkedAt) < s.cacheTTL {
+		return s.cached
+	}
+
+	report := domain.HealthReport{Status: domain.HealthStatusUp, Components: make([]domain.ComponentHealth, len(s.checkers))}
+	var wg sync.WaitGroup
+	for i, checker := range s.checkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Components[i] = s.check(ctx, checker)
+		}()
+	}
+	wg.Wait()
+
+	for _, component := range report.Components {
+		if component.Status != domain.HealthStatusUp {
+			report.Status = domain.HealthStatusDown
+			s.logger.WarnContext(ctx, "Health check failed", "component", component.Name, "error", component.Error)
+		}
+	}
+
+	s.cached, s.checkedAt = report, s.now()
+	return report
+}
+
+// Status extends the readiness report with the scheduler state and the outcome of the last run of each job.
+func (s *HealthService) Status(ctx context.Context) domain.SystemStatus {
+	status := domain.SystemStatus{Health: s.Readiness(ctx), Scheduler: s.scheduler.Status()}
+
+	runs, err := s.runs.GetLatestRuns(ctx)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to load latest job runs", "error", err)
+		return status
+	}
+	for i, job := range status.Scheduler.Jobs {
+		for _, run := range runs {
+			if run.Job == job.Name {
+				status.Scheduler.Jobs[i].LastRun = &run
+			}
+		}
+	}
+	return status
+}
+
+func (s *HealthService) check(ctx context.Context, checker port.HealthChecker) domain.ComponentHealth {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	start := s.now()
+	details, err := checker.Check(ctx)
+	component := domain.ComponentHealth{
+		Name:      checker.Name(),
+		Status:    domain.HealthStatusUp,
+		LatencyMs: time.Since(start).Milliseconds(),
+		CheckedAt: start,
+		Details:   details,
+	}
+	if err != nil {
+		component.Status = domain.HealthStatusDown
+		component.Error = err.Error()
+	}
+	return component
+}
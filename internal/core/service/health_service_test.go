@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"weather-api/internal/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/port"
+)
+
+func newMockChecker(name string, details map[string]any, err error) *mocks.MockHealthChecker {
+	checker := &mocks.MockHealthChecker{}
+	checker.On("Name").Return(name)
+	checker.On("Check", mock.Anything).Return(details, err)
+	return checker
+}
+
+func TestHealthService_Readiness(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name           string
+		dbErr          error
+		smtpErr        error
+		expectedStatus domain.HealthStatus
+		expectedDown   []string
+	}{
+		{
+			name:           "all dependencies up",
+			expectedStatus: domain.HealthStatusUp,
+		},
+		{
+			name:           "smtp down",
+			smtpErr:        errors.New("connection refused"),
+			expectedStatus: domain.HealthStatusDown,
+			expectedDown:   []string{"smtp"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newMockChecker("postgres", map[string]any{"migration_version": 3}, tt.dbErr)
+			smtp := newMockChecker("smtp", nil, tt.smtpErr)
+			service := NewHealthService([]port.HealthChecker{db, smtp}, time.Second, time.Minute, nil, nil, testLogger)
+
+			report := service.Readiness(ctx)
+
+			assert.Equal(t, tt.expectedStatus, report.Status)
+			assert.Len(t, report.Components, 2)
+			var down []string
+			for _, c := range report.Components {
+				if c.Status == domain.HealthStatusDown {
+					down = append(down, c.Name)
+				}
+			}
+			assert.Equal(t, tt.expectedDown, down)
+		})
+	}
+}
+
+func TestHealthService_ReadinessIsCached(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	db := newMockChecker("postgres", nil, nil)
+	service := NewHealthService([]port.HealthChecker{db}, time.Second, 10*time.Second, nil, nil, testLogger)
+	service.now = func() time.Time { return now }
+
+	service.Readiness(ctx)
+	now = now.Add(5 * time.Second)
+	service.Readiness(ctx)
+	db.AssertNumberOfCalls(t, "Check", 1)
+
+	now = now.Add(10 * time.Second)
+	service.Readiness(ctx)
+	db.AssertNumberOfCalls(t, "Check", 2)
+}
+
+func TestHealthService_Status(t *testing.T) {
+	ctx := context.Background()
+	runs := &mocks.MockJobRunRepository{}
+	scheduler := NewScheduler(runs, "replica-1", time.Second, testLogger)
+	assert.NoError(t, scheduler.AddJob("daily_updates", "0 0 * * *", func(ctx context.Context, run domain.JobRun) error { return nil }))
+	lastRun := domain.JobRun{ID: 3, Job: "daily_updates", Status: domain.JobRunCompleted}
+	runs.On("GetLatestRuns", ctx).Return([]domain.JobRun{lastRun}, nil)
+	service := NewHealthService([]port.HealthChecker{newMockChecker("postgres", nil, nil)}, time.Second, time.Minute, scheduler, runs, testLogger)
+
+	status := service.Status(ctx)
+
+	assert.Equal(t, domain.HealthStatusUp, status.Health.Status)
+	assert.False(t, status.Scheduler.Running)
+	assert.Len(t, status.Scheduler.Jobs, 1)
+	assert.Equal(t, &lastRun, status.Scheduler.Jobs[0].LastRun)
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/port"
+)
+
+const signedTokenVersion = "v1"
+
+type SigningKey struct {
+	ID     string
+	Secret []byte
+}
+
+type tokenPayload struct {
+	SubscriptionID int                 `json:"sid"`
+	Purpose        domain.TokenPurpose `json:"pur"`
+	ExpiresAt      int64               `json:"exp,omitempty"`
+}
+
+// HMACTokenService signs tokens with the first key and verifies them with any of the configured keys,
+// so a new key can be introduced before the old one is retired.
+type HMACTokenService struct {
+	keys []SigningKey
+	ttls map[domain.TokenPurpose]time.Duration
+	now  func() time.Time
+}
+
+func NewHMACTokenService(keys []SigningKey, confirmTTL, unsubscribeTTL time.Duration) (port.SignedTokenService, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one signing key is required")
+	}
+	return &HMACTokenService{
+		keys: keys,
+		ttls: map[domain.TokenPurpose]time.Duration{
+			domain.TokenPurposeConfirm:     confirmTTL,
+			domain.TokenPurposeUnsubscribe: unsubscribeTTL,
+		},
+		now: time.Now,
+	}, nil
+}
+
+// ParseSigningKeys parses a comma separated list of id:secret pairs, e.g. "2025b:secret,2025a:oldsecret".
+func ParseSigningKeys(raw string) ([]SigningKey, error) {
+	var keys []SigningKey
+	for i, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		// Errors give the position of the entry, never its contents, which may be the secret.
+		id, secret, ok := strings.Cut(entry, ":")
+		switch {
+		case !ok || id == "":
+			return nil, fmt.Errorf("signing key entry %d: missing key id", i+1)
+		case strings.Contains(id, "."):
+			return nil, fmt.Errorf("signing key entry %d: key id must not contain a dot", i+1)
+		}
+		if len(secret) < 32 {
+			return nil, fmt.Errorf("signing key %q must be at least 32 bytes long", id)
+		}
+		keys = append(keys, SigningKey{ID: id, Secret: []byte(secret)})
+	}
+	return keys, nil
+}
+
+func (s *HMACTokenService) IssueToken(subscriptionID int, purpose domain.TokenPurpose) (string, error) {
+	payload := tokenPayload{SubscriptionID: subscriptionID, Purpose: purpose}
+	if ttl := s.ttls[purpose]; ttl > 0 {
+		payload.ExpiresAt = s.now().Add(ttl).Unix()
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	key := s.keys[0]
+	unsigned := signedTokenVersion + "." + key.ID + "." + base64.RawURLEncoding.EncodeToString(raw)
+	return unsigned + "." + sign(key.Secret, unsigned), nil
+}
+
+func (s *HMACTokenService) ParseToken(token string, purpose domain.TokenPurpose) (domain.TokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 4 || parts[0] != signedTokenVersion {
+		return domain.TokenClaims{}, domain.ErrInvalidToken
+	}
+
+	key, ok := s.key(parts[1])
+	if !ok {
+		return domain.TokenClaims{}, domain.ErrInvalidToken
+	}
+	unsigned := strings.Join(parts[:3], ".")
+	if !hmac.Equal([]byte(sign(key.Secret, unsigned)), []byte(parts[3])) {
+		return domain.TokenClaims{}, domain.ErrInvalidToken
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return domain.TokenClaims{}, domain.ErrInvalidToken
+	}
+	var payload tokenPayload
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return domain.TokenClaims{}, domain.ErrInvalidToken
+	}
+	if payload.Purpose != purpose || payload.SubscriptionID <= 0 {
+		return domain.TokenClaims{}, domain.ErrInvalidToken
+	}
+
+	claims := domain.TokenClaims{SubscriptionID: payload.SubscriptionID, Purpose: payload.Purpose}
+	if payload.ExpiresAt != 0 {
+		claims.ExpiresAt = time.Unix(payload.ExpiresAt, 0)
+		if !s.now().Before(claims.ExpiresAt) {
+			return domain.TokenClaims{}, domain.ErrTokenExpired
+		}
+	}
+	return claims, nil
+}
+
+// IsSignedToken reports whether the token has the signed format. Legacy tokens are
+// base64 encoded random bytes and never contain dots.
+func (s *HMACTokenService) IsSignedToken(token string) bool {
+	return strings.HasPrefix(token, signedTokenVersion+".") && strings.Count(token, ".") == 3
+}
+
+func (s *HMACTokenService) key(id string) (SigningKey, bool) {
+	for _, k := range s.keys {
+		if k.ID == id {
+			return k, true
+		}
+	}
+	return SigningKey{}, false
+}
+
+func sign(secret []byte, data string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(data))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
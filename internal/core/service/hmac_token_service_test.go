@@ -0,0 +1,187 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"weather-api/internal/core/domain"
+)
+
+var (
+	testKeyCurrent = SigningKey{ID: "k2", Secret: []byte("0123456789abcdef0123456789abcdef")}
+	testKeyOld     = SigningKey{ID: "k1", Secret: []byte("fedcba9876543210fedcba9876543210")}
+)
+
+func newTestHMACTokenService(t *testing.T, keys ...SigningKey) *HMACTokenService {
+	svc, err := NewHMACTokenService(keys, time.Hour, 0)
+	assert.NoError(t, err)
+	return svc.(*HMACTokenService)
+}
+
+func TestHMACTokenService_ParseToken(t *testing.T) {
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		issue         func(t *testing.T) string
+		purpose       domain.TokenPurpose
+		parseAt       time.Time
+		expected      domain.TokenClaims
+		expectedError error
+	}{
+		{
+			name: "valid unsubscribe token without expiry",
+			issue: func(t *testing.T) string {
+				token, err := newTestHMACTokenService(t, testKeyCurrent).IssueToken(42, domain.TokenPurposeUnsubscribe)
+				assert.NoError(t, err)
+				return token
+			},
+			purpose:  domain.TokenPurposeUnsubscribe,
+			parseAt:  now.Add(24 * 365 * time.Hour),
+			expected: domain.TokenClaims{SubscriptionID: 42, Purpose: domain.TokenPurposeUnsubscribe},
+		},
+		{
+			name: "token signed with rotated out key is still accepted",
+			issue: func(t *testing.T) string {
+				token, err := newTestHMACTokenService(t, testKeyOld).IssueToken(7, domain.TokenPurposeUnsubscribe)
+				assert.NoError(t, err)
+				return token
+			},
+			purpose:  domain.TokenPurposeUnsubscribe,
+			parseAt:  now,
+			expected: domain.TokenClaims{SubscriptionID: 7, Purpose: domain.TokenPurposeUnsubscribe},
+		},
+		{
+			name: "expired confirmation token",
+			issue: func(t *testing.T) string {
+				svc := newTestHMACTokenService(t, testKeyCurrent)
+				svc.now = func() time.Time { return now }
+				token, err := svc.IssueToken(42, domain.TokenPurposeConfirm)
+				assert.NoError(t, err)
+				return token
+			},
+			purpose:       domain.TokenPurposeConfirm,
+			parseAt:       now.Add(2 * time.Hour),
+			expectedError: domain.ErrTokenExpired,
+		},
+		{
+			name: "wrong purpose",
+			issue: func(t *testing.T) string {
+				token, err := newTestHMACTokenService(t, testKeyCurrent).IssueToken(42, domain.TokenPurposeConfirm)
+				assert.NoError(t, err)
+				return token
+			},
+			purpose:       domain.TokenPurposeUnsubscribe,
+			parseAt:       now,
+			expectedError: domain.ErrInvalidToken,
+		},
+		{
+			name: "unknown key",
+			issue: func(t *testing.T) string {
+				other := SigningKey{ID: "k3", Secret: []byte("ffffffffffffffffffffffffffffffff")}
+				token, err := newTestHMACTokenService(t, other).IssueToken(42, domain.TokenPurposeUnsubscribe)
+				assert.NoError(t, err)
+				return token
+			},
+			purpose:       domain.TokenPurposeUnsubscribe,
+			parseAt:       now,
+			expectedError: domain.ErrInvalidToken,
+		},
+		{
+			name: "tampered payload",
+			issue: func(t *testing.T) string {
+				token, err := newTestHMACTokenService(t, testKeyCurrent).IssueToken(42, domain.TokenPurposeUnsubscribe)
+				assert.NoError(t, err)
+				forged, err := newTestHMACTokenService(t, testKeyCurrent).IssueToken(43, domain.TokenPurposeUnsubscribe)
+				assert.NoError(t, err)
+				tokenParts, forgedParts := strings.Split(token, "."), strings.Split(forged, ".")
+				return tokenParts[0] + "." + tokenParts[1] + "." + forgedParts[2] + "." + tokenParts[3]
+			},
+			purpose:       domain.TokenPurposeUnsubscribe,
+			parseAt:       now,
+			expectedError: domain.ErrInvalidToken,
+		},
+		{
+			name: "legacy token",
+			issue: func(t *testing.T) string {
+				return "leLuPPmedUXI0bGYddfsOZEO_KaFthyJHWsb9lWfsdo="
+			},
+			purpose:       domain.TokenPurposeUnsubscribe,
+			parseAt:       now,
+			expectedError: domain.ErrInvalidToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTestHMACTokenService(t, testKeyCurrent, testKeyOld)
+			svc.now = func() time.Time { return tt.parseAt }
+
+			claims, err := svc.ParseToken(tt.issue(t), tt.purpose)
+
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expected.SubscriptionID, claims.SubscriptionID)
+			assert.Equal(t, tt.expected.Purpose, claims.Purpose)
+		})
+	}
+}
+
+func TestHMACTokenService_IsSignedToken(t *testing.T) {
+	svc := newTestHMACTokenService(t, testKeyCurrent)
+
+	token, err := svc.IssueToken(1, domain.TokenPurposeConfirm)
+	assert.NoError(t, err)
+
+	assert.True(t, svc.IsSignedToken(token))
+	assert.False(t, svc.IsSignedToken("leLuPPmedUXI0bGYddfsOZEO_KaFthyJHWsb9lWfsdo="))
+}
+
+func TestParseSigningKeys(t *testing.T) {
+	tests := []struct {
+		name          string
+		raw           string
+		expectedIDs   []string
+		expectedError string
+	}{
+		{
+			name:        "multiple keys",
+			raw:         "k2:0123456789abcdef0123456789abcdef, k1:fedcba9876543210fedcba9876543210",
+			expectedIDs: []string{"k2", "k1"},
+		},
+		{
+			name:          "short secret",
+			raw:           "k1:short",
+			expectedError: `signing key "k1" must be at least 32 bytes long`,
+		},
+		{
+			name:          "missing separator does not echo the secret",
+			raw:           "k1:fedcba9876543210fedcba9876543210,0123456789abcdef0123456789abcdef",
+			expectedError: "signing key entry 2: missing key id",
+		},
+		{
+			name:          "dot in key id",
+			raw:           "k.1:0123456789abcdef0123456789abcdef",
+			expectedError: "signing key entry 1: key id must not contain a dot",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := ParseSigningKeys(tt.raw)
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			var ids []string
+			for _, k := range keys {
+				ids = append(ids, k.ID)
+			}
+			assert.Equal(t, tt.expectedIDs, ids)
+		})
+	}
+}
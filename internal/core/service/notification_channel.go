@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/port"
+	"weather-api/internal/util"
+)
+
+// NotificationChannels delivers each notification over the channel of its subscription.
+type NotificationChannels map[domain.Channel]port.NotificationChannel
+
+func (c NotificationChannels) Notify(ctx context.Context, sub domain.Subscription, notification domain.Notification) error {
+	kind := sub.Channel
+	if kind == "" {
+		kind = domain.ChannelEmail
+	}
+	channel, ok := c[kind]
+	if !ok {
+		return fmt.Errorf("no %s notification channel is configured", kind)
+	}
+	return channel.Notify(ctx, sub, notification)
+}
+
+// EmailChannel sends the rendered email of a notification to the subscriber's address.
+type EmailChannel struct {
+	emailSvc port.EmailService
+}
+
+func NewEmailChannel(emailSvc port.EmailService) *EmailChannel {
+	return &EmailChannel{emailSvc: emailSvc}
+}
+
+func (c *EmailChannel) Notify(_ context.Context, sub domain.Subscription, notification domain.Notification) error {
+	return c.emailSvc.SendEmail(sub.Email, notification.Subject, notification.Body)
+}
+
+// SlackChannel posts updates to the Slack incoming webhook of the subscription.
+type SlackChannel struct {
+	sender port.SlackSender
+}
+
+func NewSlackChannel(sender port.SlackSender) *SlackChannel {
+	return &SlackChannel{sender: sender}
+}
+
+func (c *SlackChannel) Notify(ctx context.Context, sub domain.Subscription, notification domain.Notification) error {
+	w := notification.Weather
+	message := util.BuildSlackWeatherUpdate(notification.City, w.Temperature, w.Humidity, w.Description, notification.UnsubscribeToken)
+	return c.sender.PostMessage(ctx, sub.Address, message)
+}
+
+// TelegramChannel sends updates to the Telegram chat of the subscription.
+type TelegramChannel struct {
+	sender port.TelegramSender
+}
+
+func NewTelegramChannel(sender port.TelegramSender) *TelegramChannel {
+	return &TelegramChannel{sender: sender}
+}
+
+func (c *TelegramChannel) Notify(ctx context.Context, sub domain.Subscription, notification domain.Notification) error {
+	w := notification.Weather
+	return c.sender.SendMessage(ctx, sub.Address, util.BuildTelegramWeatherUpdate(notification.City, w.Temperature, w.Humidity, w.Description))
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testNotification = domain.Notification{
+	Event:            domain.NotificationWeatherUpdate,
+	City:             "Kyiv",
+	Weather:          domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny"},
+	UnsubscribeToken: "token123",
+}
+
+func TestNotificationChannels_Notify(t *testing.T) {
+	ctx := context.Background()
+	email := &mocks.MockNotificationChannel{}
+	slack := &mocks.MockNotificationChannel{}
+	channels := NotificationChannels{domain.ChannelEmail: email, domain.ChannelSlack: slack}
+	legacy := domain.Subscription{ID: 1}
+	slackSub := domain.Subscription{ID: 2, Channel: domain.ChannelSlack}
+	email.On("Notify", ctx, legacy, testNotification).Return(nil)
+	slack.On("Notify", ctx, slackSub, testNotification).Return(nil)
+
+	assert.NoError(t, channels.Notify(ctx, legacy, testNotification))
+	assert.NoError(t, channels.Notify(ctx, slackSub, testNotification))
+	assert.EqualError(t, channels.Notify(ctx, domain.Subscription{Channel: domain.ChannelTelegram}, testNotification), "no telegram notification channel is configured")
+	email.AssertExpectations(t)
+	slack.AssertExpectations(t)
+}
+
+func TestSlackChannel_Notify(t *testing.T) {
+	ctx := context.Background()
+	sender := &mocks.MockSlackSender{}
+	var message []byte
+	sender.On("PostMessage", ctx, "https://hooks.slack.com/services/T000/B000/XXX", mock.Anything).Run(func(args mock.Arguments) {
+		message = args.Get(2).([]byte)
+	}).Return(nil)
+
+	err := NewSlackChannel(sender).Notify(ctx, domain.Subscription{Channel: domain.ChannelSlack, Address: "https://hooks.slack.com/services/T000/B000/XXX"}, testNotification)
+
+	require.NoError(t, err)
+	var payload struct {
+		Text   string `json:"text"`
+		Blocks []struct {
+			Type string `json:"type"`
+		} `json:"blocks"`
+	}
+	require.NoError(t, json.Unmarshal(message, &payload))
+	assert.Equal(t, "Weather in Kyiv: Temp 20.50°C, Humidity 60%, Sunny", payload.Text)
+	require.Len(t, payload.Blocks, 3)
+	assert.Equal(t, "header", payload.Blocks[0].Type)
+	assert.Contains(t, string(message), "/api/unsubscribe/token123|Unsubscribe")
+}
+
+func TestTelegramChannel_Notify(t *testing.T) {
+	ctx := context.Background()
+	sender := &mocks.MockTelegramSender{}
+	sender.On("SendMessage", ctx, "42", "<b>Weather in Kyiv</b>\nTemp 20.50°C, Humidity 60%, Sunny\n\nSend /unsubscribe to stop these updates.").Return(nil)
+
+	err := NewTelegramChannel(sender).Notify(ctx, domain.Subscription{Channel: domain.ChannelTelegram, Address: "42"}, testNotification)
+
+	assert.NoError(t, err)
+	sender.AssertExpectations(t)
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/port"
+	"weather-api/internal/util"
+)
+
+type QuotaPolicy struct {
+	Provider string
+	// Limit is the number of calls allowed per period. 0 means the usage is only tracked.
+	Limit  int
+	Period domain.QuotaPeriod
+	// DegradeAt is the share of the limit after which the primary provider is no longer called.
+	DegradeAt float64
+	// AlertAt lists the shares of the limit at which OpsEmail is warned, e.g. 0.8 and 0.95.
+	AlertAt  []float64
+	OpsEmail string
+}
+
+// QuotaWeatherService counts calls to the primary provider against its budget. Once usage
+// reaches DegradeAt of the limit, requests go to the fallback provider, or fail with
+// ErrQuotaExhausted if there is none, so that only cached weather is served.
+type QuotaWeatherService struct {
+	primary  port.WeatherService
+	fallback port.WeatherService
+	repo     port.QuotaRepository
+	emailSvc port.EmailService
+	policy   QuotaPolicy
+	logger   *slog.Logger
+	now      func() time.Time
+
+	mu          sync.Mutex
+	periodStart time.Time
+	calls       int
+	alerted     int
+}
+
+// NewQuotaWeatherService creates the service. fallback may be nil.
+func NewQuotaWeatherService(primary, fallback port.WeatherService, repo port.QuotaRepository, emailSvc port.EmailService, policy QuotaPolicy, logger *slog.Logger) *QuotaWeatherService {
+	return &QuotaWeatherService{
+		primary:  primary,
+		fallback: fallback,
+		repo:     repo,
+		emailSvc: emailSvc,
+		policy:   policy,
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+func (s *QuotaWeatherService) GetWeather(ctx context.Context, city string) (domain.Weather, error) {
+	if err := s.charge(ctx); err != nil {
+		if s.fallback != nil {
+			return s.fallback.GetWeather(ctx, city)
+		}
+		return domain.Weather{}, err
+	}
+	return s.primary.GetWeather(ctx, city)
+}
+
+// Forecasts returns next counted against the same budget. Forecasts have no fallback, so once
+// the service is degraded they fail with ErrQuotaExhausted.
+func (s *QuotaWeatherService) Forecasts(next port.ForecastService) port.ForecastService {
+	return &quotaForecastService{quota: s, next: next}
+}
+
+// charge counts a call to the primary provider, or returns ErrQuotaExhausted without counting
+// it once usage has reached DegradeAt of the limit.
+func (s *QuotaWeatherService) charge(ctx context.Context) error {
+	now := s.now()
+	period := s.periodOf(now)
+	calls := s.currentCalls(ctx, period)
+
+	if s.degraded(calls) {
+		return &domain.ProviderUnavailableError{RetryAfter: s.periodEnd(period).Sub(now), Err: domain.ErrQuotaExhausted}
+	}
+
+	calls, err := s.repo.IncrementUsage(ctx, s.policy.Provider, period)
+	s.mu.Lock()
+	if err != nil {
+		// Keep counting locally so the budget is still enforced while the database is unavailable.
+		s.calls++
+	} else if s.periodStart.Equal(period) {
+		s.calls = max(s.calls, calls)
+	}
+	calls = s.calls
+	s.mu.Unlock()
+
+	s.alert(ctx, period, calls)
+	return nil
+}
+
+type quotaForecastService struct {
+	quota *QuotaWeatherService
+	next  port.ForecastService
+}
+
+func (s *quotaForecastService) GetForecast(ctx context.Context, city string, days int) ([]domain.DailyForecast, error) {
+	if err := s.quota.charge(ctx); err != nil {
+		return nil, err
+	}
+	return s.next.GetForecast(ctx, city, days)
+}
+
+// Usage reports the provider's usage in the current period as stored in the database.
+func (s *QuotaWeatherService) Usage(ctx context.Context) (domain.QuotaUsage, error) {
+	period := s.periodOf(s.now())
+	calls, err := s.repo.GetUsage(ctx, s.policy.Provider, period)
+	if err != nil {
+		return domain.QuotaUsage{}, err
+	}
+	return domain.QuotaUsage{
+		Provider:    s.policy.Provider,
+		PeriodStart: period,
+		PeriodEnd:   s.periodEnd(period),
+		Calls:       calls,
+		Limit:       s.policy.Limit,
+		Mode:        s.mode(calls),
+	}, nil
+}
+
+// CurrentUsage returns the last known number of calls in the current period and the limit,
+// without querying the database.
+func (s *QuotaWeatherService) CurrentUsage() (calls, limit int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.periodStart.Equal(s.periodOf(s.now())) {
+		return 0, s.policy.Limit
+	}
+	return s.calls, s.policy.Limit
+}
+
+func (s *QuotaWeatherService) currentCalls(ctx context.Context, period time.Time) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.periodStart.Equal(period) {
+		return s.calls
+	}
+
+	calls, err := s.repo.GetUsage(ctx, s.policy.Provider, period)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Failed to load provider usage, counting from zero", "provider", s.policy.Provider, "error", err)
+		calls = 0
+	}
+	s.periodStart, s.calls, s.alerted = period, calls, 0
+	return calls
+}
+
+func (s *QuotaWeatherService) degraded(calls int) bool {
+	return s.policy.Limit > 0 && calls >= s.threshold(s.policy.DegradeAt)
+}
+
+func (s *QuotaWeatherService) mode(calls int) domain.QuotaMode {
+	switch {
+	case !s.degraded(calls):
+		return domain.QuotaModeNormal
+	case s.fallback != nil:
+		return domain.QuotaModeFallback
+	default:
+		return domain.QuotaModeCacheOnly
+	}
+}
+
+func (s *QuotaWeatherService) threshold(share float64) int {
+	if share <= 0 || share > 1 {
+		share = 1
+	}
+	return int(math.Ceil(float64(s.policy.Limit) * share))
+}
+
+// alert warns the ops address about the highest threshold reached, once per threshold and period.
+func (s *QuotaWeatherService) alert(ctx context.Context, period time.Time, calls int) {
+	if s.policy.Limit <= 0 || s.policy.OpsEmail == "" {
+		return
+	}
+
+	percent := 0
+	for _, share := range s.policy.AlertAt {
+		if calls >= s.threshold(share) {
+			percent = max(percent, int(math.Round(share*100)))
+		}
+	}
+
+	s.mu.Lock()
+	if percent <= s.alerted || !s.periodStart.Equal(period) {
+		s.mu.Unlock()
+		return
+	}
+	s.alerted = percent
+	s.mu.Unlock()
+
+	go func() {
+		ctx := context.WithoutCancel(ctx)
+		first, err := s.repo.MarkAlerted(ctx, s.policy.Provider, period, percent)
+		if err != nil || !first {
+			return
+		}
+		s.logger.WarnContext(ctx, "Weather provider quota threshold reached", "provider", s.policy.Provider, "percent", percent, "calls", calls, "limit", s.policy.Limit)
+		subject, body := util.BuildQuotaAlertEmail(s.policy.Provider, percent, calls, s.policy.Limit, s.periodEnd(period))
+		if err := s.emailSvc.SendEmail(s.policy.OpsEmail, subject, body); err != nil {
+			s.logger.ErrorContext(ctx, "Failed to send quota alert", "error", err)
+		}
+	}()
+}
+
+func (s *QuotaWeatherService) periodOf(t time.Time) time.Time {
+	t = t.UTC()
+	if s.policy.Period == domain.QuotaPeriodDaily {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func (s *QuotaWeatherService) periodEnd(start time.Time) time.Time {
+	if s.policy.Period == domain.QuotaPeriodDaily {
+		return start.AddDate(0, 0, 1)
+	}
+	return start.AddDate(0, 1, 0)
+}
+
+// ParseQuotaAlerts parses a comma separated list of percentages of the limit, e.g. "80,95".
+func ParseQuotaAlerts(raw string) ([]float64, error) {
+	var shares []float64
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		percent, err := strconv.Atoi(entry)
+		if err != nil || percent <= 0 || percent > 100 {
+			return nil, fmt.Errorf("invalid quota alert percentage %q", entry)
+		}
+		shares = append(shares, float64(percent)/100)
+	}
+	return shares, nil
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"weather-api/internal/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/port"
+)
+
+func TestQuotaWeatherService_GetWeather(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 5, 20, 12, 0, 0, 0, time.UTC)
+	periodStart := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	kyiv := domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny"}
+	fallbackKyiv := domain.Weather{Temperature: 20, Humidity: 58, Description: "Clear sky"}
+
+	tests := []struct {
+		name            string
+		withFallback    bool
+		setupMocks      func(repo *mocks.MockQuotaRepository, primary, fallback *mocks.MockWeatherService)
+		expected        domain.Weather
+		expectedError   error
+		expectedRetryIn time.Duration
+	}{
+		{
+			name: "calls the primary provider under the limit",
+			setupMocks: func(repo *mocks.MockQuotaRepository, primary, fallback *mocks.MockWeatherService) {
+				repo.On("GetUsage", ctx, "weatherapi", periodStart).Return(10, nil)
+				repo.On("IncrementUsage", ctx, "weatherapi", periodStart).Return(11, nil)
+				primary.On("GetWeather", ctx, "Kyiv").Return(kyiv, nil)
+			},
+			expected: kyiv,
+		},
+		{
+			name: "keeps counting when the usage cannot be stored",
+			setupMocks: func(repo *mocks.MockQuotaRepository, primary, fallback *mocks.MockWeatherService) {
+				repo.On("GetUsage", ctx, "weatherapi", periodStart).Return(0, errors.New("db down"))
+				repo.On("IncrementUsage", ctx, "weatherapi", periodStart).Return(0, errors.New("db down"))
+				primary.On("GetWeather", ctx, "Kyiv").Return(kyiv, nil)
+			},
+			expected: kyiv,
+		},
+		{
+			name:         "switches to the fallback provider near the limit",
+			withFallback: true,
+			setupMocks: func(repo *mocks.MockQuotaRepository, primary, fallback *mocks.MockWeatherService) {
+				repo.On("GetUsage", ctx, "weatherapi", periodStart).Return(95, nil)
+				fallback.On("GetWeather", ctx, "Kyiv").Return(fallbackKyiv, nil)
+			},
+			expected: fallbackKyiv,
+		},
+		{
+			name: "reports the quota as exhausted without a fallback",
+			setupMocks: func(repo *mocks.MockQuotaRepository, primary, fallback *mocks.MockWeatherService) {
+				repo.On("GetUsage", ctx, "weatherapi", periodStart).Return(100, nil)
+			},
+			expectedError:   domain.ErrQuotaExhausted,
+			expectedRetryIn: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC).Sub(now),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.MockQuotaRepository{}
+			primary := &mocks.MockWeatherService{}
+			fallback := &mocks.MockWeatherService{}
+			tt.setupMocks(repo, primary, fallback)
+
+			var fallbackSvc port.WeatherService
+			if tt.withFallback {
+				fallbackSvc = fallback
+			}
+			svc := NewQuotaWeatherService(primary, fallbackSvc, repo, &mocks.MockEmailService{},
+				QuotaPolicy{Provider: "weatherapi", Limit: 100, Period: domain.QuotaPeriodMonthly, DegradeAt: 0.95}, testLogger)
+			svc.now = func() time.Time { return now }
+
+			weather, err := svc.GetWeather(ctx, "Kyiv")
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.ErrorIs(t, err, domain.ErrProviderUnavailable)
+				assert.Equal(t, tt.expectedRetryIn, domain.RetryAfter(err))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, weather)
+			}
+			repo.AssertExpectations(t)
+			primary.AssertExpectations(t)
+			fallback.AssertExpectations(t)
+		})
+	}
+}
+
+func TestQuotaWeatherService_Forecasts(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 5, 20, 12, 0, 0, 0, time.UTC)
+	periodStart := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	forecast := []domain.DailyForecast{{Date: time.Date(2025, 5, 20, 0, 0, 0, 0, time.UTC), Description: "Sunny"}}
+
+	repo := &mocks.MockQuotaRepository{}
+	fallback := &mocks.MockWeatherService{}
+	next := &mocks.MockForecastService{}
+	repo.On("GetUsage", ctx, "weatherapi", periodStart).Return(93, nil)
+	repo.On("IncrementUsage", ctx, "weatherapi", periodStart).Return(94, nil).Once()
+	repo.On("IncrementUsage", ctx, "weatherapi", periodStart).Return(95, nil).Once()
+	next.On("GetForecast", ctx, "Kyiv", 3).Return(forecast, nil).Twice()
+
+	svc := NewQuotaWeatherService(&mocks.MockWeatherService{}, fallback, repo, &mocks.MockEmailService{},
+		QuotaPolicy{Provider: "weatherapi", Limit: 100, Period: domain.QuotaPeriodMonthly, DegradeAt: 0.95}, testLogger)
+	svc.now = func() time.Time { return now }
+	forecasts := svc.Forecasts(next)
+
+	for range 2 {
+		got, err := forecasts.GetForecast(ctx, "Kyiv", 3)
+		assert.NoError(t, err)
+		assert.Equal(t, forecast, got)
+	}
+	// The budget is shared with current weather, and forecasts have no fallback.
+	_, err := forecasts.GetForecast(ctx, "Kyiv", 3)
+
+	assert.ErrorIs(t, err, domain.ErrQuotaExhausted)
+	assert.Equal(t, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC).Sub(now), domain.RetryAfter(err))
+	calls, _ := svc.CurrentUsage()
+	assert.Equal(t, 95, calls)
+	repo.AssertExpectations(t)
+	next.AssertExpectations(t)
+	fallback.AssertExpectations(t)
+}
+
+func TestQuotaWeatherService_AlertsOncePerThreshold(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 5, 20, 12, 0, 0, 0, time.UTC)
+	periodStart := time.Date(2025, 5, 20, 0, 0, 0, 0, time.UTC)
+
+	repo := &mocks.MockQuotaRepository{}
+	primary := &mocks.MockWeatherService{}
+	emailSvc := &mocks.MockEmailService{}
+	sent := make(chan string, 2)
+
+	repo.On("GetUsage", ctx, "weatherapi", periodStart).Return(78, nil)
+	repo.On("IncrementUsage", ctx, "weatherapi", periodStart).Return(79, nil).Once()
+	repo.On("IncrementUsage", ctx, "weatherapi", periodStart).Return(80, nil).Once()
+	repo.On("IncrementUsage", ctx, "weatherapi", periodStart).Return(81, nil).Once()
+	repo.On("MarkAlerted", mock.Anything, "weatherapi", periodStart, 80).Return(true, nil).Once()
+	primary.On("GetWeather", ctx, "Kyiv").Return(domain.Weather{}, nil)
+	emailSvc.On("SendEmail", "ops@example.com", "Weather provider quota at 80%", mock.Anything).
+		Run(func(args mock.Arguments) { sent <- args.String(1) }).Return(nil).Once()
+
+	svc := NewQuotaWeatherService(primary, nil, repo, emailSvc, QuotaPolicy{
+		Provider:  "weatherapi",
+		Limit:     100,
+		Period:    domain.QuotaPeriodDaily,
+		DegradeAt: 0.95,
+		AlertAt:   []float64{0.8, 0.95},
+		OpsEmail:  "ops@example.com",
+	}, testLogger)
+	svc.now = func() time.Time { return now }
+
+	for range 3 {
+		_, err := svc.GetWeather(ctx, "Kyiv")
+		assert.NoError(t, err)
+	}
+
+	select {
+	case <-sent:
+	case <-time.After(time.Second):
+		t.Fatal("quota alert was not sent")
+	}
+	calls, limit := svc.CurrentUsage()
+	assert.Equal(t, 81, calls)
+	assert.Equal(t, 100, limit)
+	repo.AssertExpectations(t)
+	emailSvc.AssertExpectations(t)
+}
+
+func TestParseQuotaAlerts(t *testing.T) {
+	shares, err := ParseQuotaAlerts("80, 95")
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{0.8, 0.95}, shares)
+
+	_, err = ParseQuotaAlerts("80,150")
+	assert.Error(t, err)
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/port"
+)
+
+// Rate limit rules checked by the services; the HTTP routes name their own per-IP rules.
+const RateLimitSubscribeEmail = "subscribe_email"
+
+// RateLimiter applies named rate limits, e.g. "subscribe_ip", to keys such as a client IP.
+// Rules that are not configured don't limit anything.
+type RateLimiter struct {
+	store  port.RateLimitStore
+	limits map[string]domain.RateLimit
+	logger *slog.Logger
+}
+
+func NewRateLimiter(store port.RateLimitStore, limits map[string]domain.RateLimit, logger *slog.Logger) *RateLimiter {
+	return &RateLimiter{store: store, limits: limits, logger: logger}
+}
+
+// ParseRateLimits parses a comma separated list of rule=limit/period entries,
+// e.g. "subscribe_ip=10/1h,subscribe_email=5/24h".
+func ParseRateLimits(raw string) (map[string]domain.RateLimit, error) {
+	limits := map[string]domain.RateLimit{}
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		rule, value, ok := strings.Cut(entry, "=")
+		count, period, ok2 := strings.Cut(value, "/")
+		if !ok || !ok2 || rule == "" {
+			return nil, fmt.Errorf("invalid rate limit entry %q, expected rule=limit/period", entry)
+		}
+		limit, err := strconv.Atoi(count)
+		if err != nil || limit <= 0 {
+			return nil, fmt.Errorf("rate limit %q must be a positive number of requests", rule)
+		}
+		d, err := time.ParseDuration(period)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("rate limit %q must have a positive period", rule)
+		}
+		limits[rule] = domain.RateLimit{Limit: limit, Period: d}
+	}
+	return limits, nil
+}
+
+// Allow takes a token for key under rule. It reports false if the rule is not configured.
+// Requests are let through when the store fails, so an outage of the store doesn't take the
+// API down with it.
+func (l *RateLimiter) Allow(ctx context.Context, rule, key string) (domain.RateLimitResult, bool) {
+	limit, ok := l.limits[rule]
+	if !ok {
+		return domain.RateLimitResult{}, false
+	}
+	result, err := l.store.Take(ctx, rule+":"+key, limit)
+	if err != nil {
+		l.logger.WarnContext(ctx, "Failed to check rate limit, allowing request", "rule", rule, "error", err)
+		return domain.RateLimitResult{Allowed: true, Limit: limit.Limit, Remaining: limit.Limit}, true
+	}
+	return result, true
+}
+
+// Check is Allow for callers that only need to know whether to go on; it returns a
+// *domain.RateLimitError when the limit is exceeded.
+func (l *RateLimiter) Check(ctx context.Context, rule, key string) error {
+	if result, ok := l.Allow(ctx, rule, key); ok && !result.Allowed {
+		l.logger.InfoContext(ctx, "Rate limit exceeded", "rule", rule)
+		return &domain.RateLimitError{RetryAfter: result.RetryAfter}
+	}
+	return nil
+}
+
+// Limit returns the configured limit of rule.
+func (l *RateLimiter) Limit(rule string) (domain.RateLimit, bool) {
+	limit, ok := l.limits[rule]
+	return limit, ok
+}
+
+// Prune drops buckets that have been full for a while.
+func (l *RateLimiter) Prune(ctx context.Context) error {
+	var longest time.Duration
+	for _, limit := range l.limits {
+		longest = max(longest, limit.Period)
+	}
+	return l.store.Prune(ctx, time.Now().Add(-longest))
+}
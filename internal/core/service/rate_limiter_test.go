@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"weather-api/internal/mocks"
+
+	"github.com/stretchr/testify/assert"
+
+	"weather-api/internal/core/domain"
+)
+
+func TestRateLimit_Take(t *testing.T) {
+	limit := domain.RateLimit{Limit: 2, Period: time.Minute}
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	bucket := limit.NewTokenBucket(now)
+
+	bucket, result := limit.Take(bucket, now)
+	assert.Equal(t, domain.RateLimitResult{Allowed: true, Limit: 2, Remaining: 1, Reset: 30 * time.Second}, result)
+
+	bucket, result = limit.Take(bucket, now)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	bucket, result = limit.Take(bucket, now.Add(10*time.Second))
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 20*time.Second, result.RetryAfter)
+
+	_, result = limit.Take(bucket, now.Add(30*time.Second))
+	assert.True(t, result.Allowed)
+}
+
+func TestRateLimiter_Check(t *testing.T) {
+	ctx := context.Background()
+	limit := domain.RateLimit{Limit: 5, Period: time.Hour}
+
+	tests := []struct {
+		name          string
+		rule          string
+		setupMocks    func(store *mocks.MockRateLimitStore)
+		expectedError error
+		retryAfter    time.Duration
+	}{
+		{
+			name: "allowed",
+			rule: RateLimitSubscribeEmail,
+			setupMocks: func(store *mocks.MockRateLimitStore) {
+				store.On("Take", ctx, "subscribe_email:user1@example.com", limit).Return(domain.RateLimitResult{Allowed: true, Limit: 5, Remaining: 4}, nil)
+			},
+		},
+		{
+			name: "limit exceeded",
+			rule: RateLimitSubscribeEmail,
+			setupMocks: func(store *mocks.MockRateLimitStore) {
+				store.On("Take", ctx, "subscribe_email:user1@example.com", limit).Return(domain.RateLimitResult{Limit: 5, RetryAfter: 12 * time.Minute}, nil)
+			},
+			expectedError: domain.ErrRateLimited,
+			retryAfter:    12 * time.Minute,
+		},
+		{
+			name: "store failure lets the request through",
+			rule: RateLimitSubscribeEmail,
+			setupMocks: func(store *mocks.MockRateLimitStore) {
+				store.On("Take", ctx, "subscribe_email:user1@example.com", limit).Return(domain.RateLimitResult{}, errors.New("db down"))
+			},
+		},
+		{
+			name:       "unconfigured rule",
+			rule:       "other",
+			setupMocks: func(store *mocks.MockRateLimitStore) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &mocks.MockRateLimitStore{}
+			tt.setupMocks(store)
+			limiter := NewRateLimiter(store, map[string]domain.RateLimit{RateLimitSubscribeEmail: limit}, testLogger)
+
+			err := limiter.Check(ctx, tt.rule, "user1@example.com")
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Equal(t, tt.retryAfter, domain.RetryAfter(err))
+			} else {
+				assert.NoError(t, err)
+			}
+			store.AssertExpectations(t)
+		})
+	}
+}
+
+func TestParseRateLimits(t *testing.T) {
+	limits, err := ParseRateLimits("subscribe_ip=10/1h, subscribe_email=5/24h")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]domain.RateLimit{
+		"subscribe_ip":    {Limit: 10, Period: time.Hour},
+		"subscribe_email": {Limit: 5, Period: 24 * time.Hour},
+	}, limits)
+
+	for _, raw := range []string{"subscribe_ip=10", "subscribe_ip=0/1h", "subscribe_ip=10/soon", "=10/1h"} {
+		_, err := ParseRateLimits(raw)
+		assert.Error(t, err, raw)
+	}
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/port"
+)
+
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+type BreakerPolicy struct {
+	// FailureThreshold is the number of consecutive failed calls that opens the circuit.
+	FailureThreshold int
+	// OpenTimeout is how long the circuit stays open before a single trial call is let through.
+	OpenTimeout time.Duration
+}
+
+// ResilientWeatherService retries calls that failed because the provider was unavailable, with
+// jittered exponential backoff, and stops calling it altogether while it keeps failing. Other
+// errors, like an unknown city, are returned as they are.
+type ResilientWeatherService struct {
+	next    port.WeatherService
+	retry   RetryPolicy
+	breaker BreakerPolicy
+	logger  *slog.Logger
+	now     func() time.Time
+	sleep   func(ctx context.Context, d time.Duration) error
+
+	mu       sync.Mutex
+	state    domain.CircuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func NewResilientWeatherService(next port.WeatherService, retry RetryPolicy, breaker BreakerPolicy, logger *slog.Logger) *ResilientWeatherService {
+	return &ResilientWeatherService{
+		next:    next,
+		retry:   retry,
+		breaker: breaker,
+		logger:  logger,
+		now:     time.Now,
+		sleep:   sleepContext,
+		state:   domain.CircuitClosed,
+	}
+}
+
+func (s *ResilientWeatherService) GetWeather(ctx context.Context, city string) (domain.Weather, error) {
+	return callResilient(ctx, s, city, func(ctx context.Context) (domain.Weather, error) {
+		return s.next.GetWeather(ctx, city)
+	})
+}
+
+// Forecasts returns next with the same retries, sharing the circuit breaker since both call
+// the same provider.
+func (s *ResilientWeatherService) Forecasts(next port.ForecastService) port.ForecastService {
+	return &resilientForecastService{resilient: s, next: next}
+}
+
+type resilientForecastService struct {
+	resilient *ResilientWeatherService
+	next      port.ForecastService
+}
+
+func (s *resilientForecastService) GetForecast(ctx context.Context, city string, days int) ([]domain.DailyForecast, error) {
+	return callResilient(ctx, s.resilient, city, func(ctx context.Context) ([]domain.DailyForecast, error) {
+		return s.next.GetForecast(ctx, city, days)
+	})
+}
+
+func callResilient[T any](ctx context.Context, s *ResilientWeatherService, city string, call func(context.Context) (T, error)) (T, error) {
+	var zero T
+	attempts := max(s.retry.MaxAttempts, 1)
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			delay := s.retry.backoff(attempt)
+			if retryAfter := domain.RetryAfter(err); retryAfter > delay {
+				if retryAfter > s.retry.MaxDelay {
+					return zero, err
+				}
+				delay = retryAfter
+			}
+			if sleepErr := s.sleep(ctx, delay); sleepErr != nil {
+				return zero, err
+			}
+		}
+
+		if wait, ok := s.allow(); !ok {
+			return zero, &domain.ProviderUnavailableError{RetryAfter: wait, Err: errors.New("circuit breaker is open")}
+		}
+
+		var result T
+		result, err = call(ctx)
+		if errors.Is(err, domain.ErrQuotaExhausted) {
+			// The provider is fine, we just stopped calling it; retrying won't help.
+			s.release()
+			return zero, err
+		}
+		s.record(ctx, err)
+		if !errors.Is(err, domain.ErrProviderUnavailable) {
+			return result, err
+		}
+		s.logger.WarnContext(ctx, "Weather provider unavailable", "city", city, "attempt", attempt+1, "error", err)
+	}
+	return zero, err
+}
+
+// State returns the current circuit state.
+func (s *ResilientWeatherService) State() domain.CircuitState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state
+}
+
+// allow reports whether a call may go through, and if not, how long the circuit stays open.
+func (s *ResilientWeatherService) allow() (time.Duration, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch s.state {
+	case domain.CircuitOpen:
+		remaining := s.breaker.OpenTimeout - s.now().Sub(s.openedAt)
+		if remaining > 0 {
+			return remaining, false
+		}
+		s.state, s.probing = domain.CircuitHalfOpen, true
+		return 0, true
+	case domain.CircuitHalfOpen:
+		if s.probing {
+			return s.breaker.OpenTimeout, false
+		}
+		s.probing = true
+	}
+	return 0, true
+}
+
+// release frees the half-open trial slot without counting the call either way.
+func (s *ResilientWeatherService) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.probing = false
+}
+
+func (s *ResilientWeatherService) record(ctx context.Context, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.probing = false
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return
+	}
+	if !errors.Is(err, domain.ErrProviderUnavailable) {
+		if s.state != domain.CircuitClosed {
+			s.logger.InfoContext(ctx, "Weather provider circuit closed")
+		}
+		s.state, s.failures = domain.CircuitClosed, 0
+		return
+	}
+
+	s.failures++
+	if s.state == domain.CircuitHalfOpen || (s.breaker.FailureThreshold > 0 && s.failures >= s.breaker.FailureThreshold) {
+		if s.state != domain.CircuitOpen {
+			s.logger.WarnContext(ctx, "Weather provider circuit opened", "failures", s.failures, "open_for", s.breaker.OpenTimeout)
+		}
+		s.state, s.openedAt = domain.CircuitOpen, s.now()
+	}
+}
+
+// backoff returns a random delay up to BaseDelay * 2^(attempt-1), capped at MaxDelay ("full jitter").
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	ceiling := p.BaseDelay << (attempt - 1)
+	if ceiling <= 0 || ceiling > p.MaxDelay {
+		ceiling = p.MaxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling) + 1
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
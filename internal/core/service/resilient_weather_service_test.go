@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"weather-api/internal/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"weather-api/internal/core/domain"
+)
+
+func newTestResilientWeatherService(next *mocks.MockWeatherService, now *time.Time) *ResilientWeatherService {
+	svc := NewResilientWeatherService(next,
+		RetryPolicy{MaxAttempts: 3, BaseDelay: 10 * time.Millisecond, MaxDelay: 100 * time.Millisecond},
+		BreakerPolicy{FailureThreshold: 3, OpenTimeout: 30 * time.Second},
+		testLogger)
+	svc.now = func() time.Time { return *now }
+	svc.sleep = func(ctx context.Context, d time.Duration) error { return nil }
+	return svc
+}
+
+func TestResilientWeatherService_GetWeather(t *testing.T) {
+	ctx := context.Background()
+	kyiv := domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny"}
+	unavailable := &domain.ProviderUnavailableError{Err: errors.New("provider responded with 502")}
+
+	tests := []struct {
+		name          string
+		setupMocks    func(next *mocks.MockWeatherService)
+		expected      domain.Weather
+		expectedError error
+		expectedCalls int
+	}{
+		{
+			name: "retries until the provider recovers",
+			setupMocks: func(next *mocks.MockWeatherService) {
+				next.On("GetWeather", ctx, "Kyiv").Return(domain.Weather{}, unavailable).Twice()
+				next.On("GetWeather", ctx, "Kyiv").Return(kyiv, nil).Once()
+			},
+			expected:      kyiv,
+			expectedCalls: 3,
+		},
+		{
+			name: "gives up after max attempts",
+			setupMocks: func(next *mocks.MockWeatherService) {
+				next.On("GetWeather", ctx, "Kyiv").Return(domain.Weather{}, unavailable)
+			},
+			expectedError: domain.ErrProviderUnavailable,
+			expectedCalls: 3,
+		},
+		{
+			name: "does not retry unknown city",
+			setupMocks: func(next *mocks.MockWeatherService) {
+				next.On("GetWeather", ctx, "Kyiv").Return(domain.Weather{}, domain.ErrCityNotFound)
+			},
+			expectedError: domain.ErrCityNotFound,
+			expectedCalls: 1,
+		},
+		{
+			name: "does not retry when the quota is exhausted",
+			setupMocks: func(next *mocks.MockWeatherService) {
+				next.On("GetWeather", ctx, "Kyiv").Return(domain.Weather{}, &domain.ProviderUnavailableError{Err: domain.ErrQuotaExhausted})
+			},
+			expectedError: domain.ErrQuotaExhausted,
+			expectedCalls: 1,
+		},
+		{
+			name: "does not wait for retry-after beyond max delay",
+			setupMocks: func(next *mocks.MockWeatherService) {
+				next.On("GetWeather", ctx, "Kyiv").Return(domain.Weather{}, &domain.ProviderUnavailableError{RetryAfter: time.Minute})
+			},
+			expectedError: domain.ErrProviderUnavailable,
+			expectedCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+			next := &mocks.MockWeatherService{}
+			tt.setupMocks(next)
+			svc := newTestResilientWeatherService(next, &now)
+
+			weather, err := svc.GetWeather(ctx, "Kyiv")
+
+			assert.Equal(t, tt.expected, weather)
+			assert.ErrorIs(t, err, tt.expectedError)
+			next.AssertNumberOfCalls(t, "GetWeather", tt.expectedCalls)
+		})
+	}
+}
+
+func TestResilientWeatherService_CircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	next := &mocks.MockWeatherService{}
+	svc := newTestResilientWeatherService(next, &now)
+	svc.retry.MaxAttempts = 1
+
+	next.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{}, &domain.ProviderUnavailableError{}).Times(3)
+	for range 3 {
+		_, err := svc.GetWeather(ctx, "Kyiv")
+		assert.ErrorIs(t, err, domain.ErrProviderUnavailable)
+	}
+	assert.Equal(t, domain.CircuitOpen, svc.State())
+
+	now = now.Add(10 * time.Second)
+	_, err := svc.GetWeather(ctx, "Kyiv")
+	assert.ErrorIs(t, err, domain.ErrProviderUnavailable)
+	assert.Equal(t, 20*time.Second, domain.RetryAfter(err))
+	next.AssertNumberOfCalls(t, "GetWeather", 3)
+
+	now = now.Add(20 * time.Second)
+	next.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{Description: "Sunny"}, nil).Once()
+	weather, err := svc.GetWeather(ctx, "Kyiv")
+	assert.NoError(t, err)
+	assert.Equal(t, "Sunny", weather.Description)
+	assert.Equal(t, domain.CircuitClosed, svc.State())
+}
+
+func TestResilientWeatherService_FailedProbeReopensCircuit(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	next := &mocks.MockWeatherService{}
+	svc := newTestResilientWeatherService(next, &now)
+	svc.retry.MaxAttempts = 1
+	svc.breaker.FailureThreshold = 1
+
+	next.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{}, &domain.ProviderUnavailableError{})
+	_, _ = svc.GetWeather(ctx, "Kyiv")
+	assert.Equal(t, domain.CircuitOpen, svc.State())
+
+	now = now.Add(31 * time.Second)
+	_, err := svc.GetWeather(ctx, "Kyiv")
+	assert.ErrorIs(t, err, domain.ErrProviderUnavailable)
+	assert.Equal(t, domain.CircuitOpen, svc.State())
+	next.AssertNumberOfCalls(t, "GetWeather", 2)
+}
+
+func TestResilientWeatherService_Forecasts(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	next := &mocks.MockWeatherService{}
+	forecastNext := &mocks.MockForecastService{}
+	svc := newTestResilientWeatherService(next, &now)
+	forecasts := svc.Forecasts(forecastNext)
+	forecast := []domain.DailyForecast{{Description: "Sunny"}}
+
+	forecastNext.On("GetForecast", mock.Anything, "Kyiv", 3).Return([]domain.DailyForecast(nil), &domain.ProviderUnavailableError{}).Once()
+	forecastNext.On("GetForecast", mock.Anything, "Kyiv", 3).Return(forecast, nil).Once()
+	got, err := forecasts.GetForecast(ctx, "Kyiv", 3)
+	assert.NoError(t, err)
+	assert.Equal(t, forecast, got)
+
+	// Forecasts and current weather share the breaker of the provider.
+	next.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{}, &domain.ProviderUnavailableError{}).Times(3)
+	_, err = svc.GetWeather(ctx, "Kyiv")
+	assert.ErrorIs(t, err, domain.ErrProviderUnavailable)
+	assert.Equal(t, domain.CircuitOpen, svc.State())
+
+	_, err = forecasts.GetForecast(ctx, "Kyiv", 3)
+	assert.ErrorIs(t, err, domain.ErrProviderUnavailable)
+	assert.Equal(t, 30*time.Second, domain.RetryAfter(err))
+	forecastNext.AssertNumberOfCalls(t, "GetForecast", 2)
+}
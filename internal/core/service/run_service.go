@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/port"
+)
+
+// runLockWait is how long a requested run waits for a scheduled run of its job to finish.
+const runLockWait = 2 * time.Second
+
+// dryRunJob records dry runs, which send nothing and so need not wait for scheduled runs.
+const dryRunJob = "dry_run_updates"
+
+// updateJobName is the scheduler job that sends the updates of frequency, e.g. hourly_updates.
+func updateJobName(frequency domain.Frequency) string {
+	return string(frequency) + "_updates"
+}
+
+// RunService starts update runs on request. A run is started through the scheduler under the
+// lock of the job of its frequency, so it never overlaps the scheduled run that would send to
+// the same subscribers, and is recorded in job_runs along with its report.
+type RunService struct {
+	scheduler *Scheduler
+	emailSvc  *EmailService
+	repo      port.SubscriptionRepository
+	runs      port.JobRunRepository
+	logger    *slog.Logger
+}
+
+func NewRunService(scheduler *Scheduler, emailSvc *EmailService, repo port.SubscriptionRepository, runs port.JobRunRepository, logger *slog.Logger) *RunService {
+	return &RunService{scheduler: scheduler, emailSvc: emailSvc, repo: repo, runs: runs, logger: logger}
+}
+
+// Start starts the run and returns its record right away; GetRun has the report once it is
+// done. It returns ErrJobBusy while another run of the same job holds the lock.
+func (s *RunService) Start(ctx context.Context, req domain.RunRequest) (domain.JobRun, error) {
+	if req.Frequency == "" && req.SubscriptionID == 0 {
+		return domain.JobRun{}, domain.ErrInvalidInput
+	}
+
+	job := dryRunJob
+	if !req.DryRun {
+		frequency := req.Frequency
+		if frequency == "" {
+			sub, err := s.repo.GetSubscriptionByID(ctx, req.SubscriptionID)
+			if err != nil {
+				return domain.JobRun{}, err
+			}
+			frequency = sub.Frequency
+		}
+		job = updateJobName(frequency)
+	}
+
+	return s.scheduler.Trigger(ctx, job, runLockWait, func(ctx context.Context, run domain.JobRun) error {
+		req.RunID = run.ID
+		report, err := s.emailSvc.RunUpdates(ctx, req)
+		if err != nil {
+			return err
+		}
+		if err := s.runs.SaveReport(context.WithoutCancel(ctx), run.ID, report); err != nil {
+			s.logger.ErrorContext(ctx, "Failed to save run report", "run_id", run.ID, "error", err)
+		}
+		s.logger.InfoContext(ctx, "Finished requested run", "run_id", run.ID, "sent", report.Sent, "failed", report.Failed, "skipped", report.Skipped)
+		if report.Error != "" {
+			return errors.New(report.Error)
+		}
+		return nil
+	})
+}
+
+// GetRun returns a run with its report, if it has finished.
+func (s *RunService) GetRun(ctx context.Context, id int) (domain.JobRun, error) {
+	return s.runs.GetRun(ctx, id)
+}
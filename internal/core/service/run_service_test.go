@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRunService_Start(t *testing.T) {
+	tests := []struct {
+		name          string
+		req           domain.RunRequest
+		setupMocks    func(repo *mocks.MockSubscriptionRepository, deliveries *mocks.MockDeliveryRepository)
+		expectedJob   string
+		expectedError error
+	}{
+		{
+			name: "frequency run shares the lock of the scheduled job",
+			req:  domain.RunRequest{Frequency: domain.FrequencyHourly, City: "Kyiv"},
+			setupMocks: func(repo *mocks.MockSubscriptionRepository, deliveries *mocks.MockDeliveryRepository) {
+				repo.On("GetSubscriptionsByFrequency", mock.Anything, "hourly").Return([]domain.Subscription{}, nil)
+				deliveries.On("GetDeliveredSubscriptionIDs", mock.Anything, 7).Return(map[int]bool{}, nil)
+			},
+			expectedJob: "hourly_updates",
+		},
+		{
+			name: "single subscription uses the job of its frequency",
+			req:  domain.RunRequest{SubscriptionID: 42},
+			setupMocks: func(repo *mocks.MockSubscriptionRepository, deliveries *mocks.MockDeliveryRepository) {
+				repo.On("GetSubscriptionByID", mock.Anything, 42).Return(domain.Subscription{ID: 42, Frequency: domain.FrequencyDaily}, nil)
+				deliveries.On("GetDeliveredSubscriptionIDs", mock.Anything, 7).Return(map[int]bool{}, nil)
+			},
+			expectedJob: "daily_updates",
+		},
+		{
+			name: "dry run has a job of its own",
+			req:  domain.RunRequest{Frequency: domain.FrequencyDaily, DryRun: true},
+			setupMocks: func(repo *mocks.MockSubscriptionRepository, deliveries *mocks.MockDeliveryRepository) {
+				repo.On("GetSubscriptionsByFrequency", mock.Anything, "daily").Return([]domain.Subscription{}, nil)
+			},
+			expectedJob: "dry_run_updates",
+		},
+		{
+			name:          "requires frequency or subscription",
+			req:           domain.RunRequest{City: "Kyiv"},
+			setupMocks:    func(repo *mocks.MockSubscriptionRepository, deliveries *mocks.MockDeliveryRepository) {},
+			expectedError: domain.ErrInvalidInput,
+		},
+		{
+			name: "unknown subscription",
+			req:  domain.RunRequest{SubscriptionID: 42},
+			setupMocks: func(repo *mocks.MockSubscriptionRepository, deliveries *mocks.MockDeliveryRepository) {
+				repo.On("GetSubscriptionByID", mock.Anything, 42).Return(domain.Subscription{}, domain.ErrSubscriptionNotFound)
+			},
+			expectedError: domain.ErrSubscriptionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.MockSubscriptionRepository{}
+			deliveries := &mocks.MockDeliveryRepository{}
+			runs := &mocks.MockJobRunRepository{}
+			tt.setupMocks(repo, deliveries)
+			run := domain.JobRun{ID: 7, Job: tt.expectedJob, Status: domain.JobRunRunning}
+			if tt.expectedError == nil {
+				runs.On("AcquireLock", mock.Anything, tt.expectedJob).Return(func() {}, true, nil)
+				runs.On("ClaimRun", mock.Anything, tt.expectedJob, mock.Anything, "replica-1").Return(run, true, nil)
+				runs.On("SaveReport", mock.Anything, 7, mock.MatchedBy(func(report domain.RunReport) bool {
+					return report.RunID == 7 && report.Frequency == tt.req.Frequency && report.DryRun == tt.req.DryRun
+				})).Return(nil)
+				runs.On("FinishRun", mock.Anything, 7, domain.JobRunCompleted, "").Return(nil)
+			}
+			scheduler := NewScheduler(runs, "replica-1", time.Second, testLogger)
+			emailSvc := NewEmailService(repo, deliveries, &mocks.MockWeatherService{}, NotificationChannels{}, nil, testLogger)
+
+			got, err := NewRunService(scheduler, emailSvc, repo, runs, testLogger).Start(context.Background(), tt.req)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, run, got)
+			}
+			// Shutdown waits for the run to finish.
+			assert.NoError(t, scheduler.Shutdown(context.Background(), time.Second))
+			runs.AssertExpectations(t)
+			repo.AssertExpectations(t)
+			deliveries.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/port"
+
+	"github.com/robfig/cron/v3"
+)
+
+type JobFunc func(ctx context.Context, run domain.JobRun) error
+
+type scheduledJob struct {
+	name     string
+	schedule cron.Schedule
+	fn       JobFunc
+}
+
+// Scheduler runs cron jobs so that each scheduled occurrence is executed by exactly one
+// instance. Instances that lose the race for the job's lock wait for it, and take the run
+// over if the holder dies before marking it completed.
+type Scheduler struct {
+	runs     port.JobRunRepository
+	owner    string
+	lockWait time.Duration
+	cron     *cron.Cron
+	jobs     []scheduledJob
+	logger   *slog.Logger
+	now      func() time.Time
+
+	ctx       context.Context
+	interrupt context.CancelFunc
+	running   sync.WaitGroup
+
+	mu       sync.Mutex
+	started  bool
+	stopping bool
+	active   map[string]int
+}
+
+func NewScheduler(runs port.JobRunRepository, owner string, lockWait time.Duration, logger *slog.Logger) *Scheduler {
+	ctx, interrupt := context.WithCancel(context.Background())
+	return &Scheduler{
+		runs:      runs,
+		owner:     owner,
+		lockWait:  lockWait,
+		cron:      cron.New(),
+		logger:    logger,
+		now:       time.Now,
+		ctx:       ctx,
+		interrupt: interrupt,
+		active:    map[string]int{},
+	}
+}
+
+func (s *Scheduler) AddJob(name, spec string, fn JobFunc) error {
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return err
+	}
+	s.jobs = append(s.jobs, scheduledJob{name: name, schedule: schedule, fn: fn})
+	s.cron.Schedule(schedule, cron.FuncJob(func() {
+		s.RunJob(s.ctx, name, s.now().Truncate(time.Minute), fn)
+	}))
+	return nil
+}
+
+// CatchUp runs the most recent occurrence of every job scheduled within the last window, unless it
+// has already completed. Interrupted runs are resumed. Older missed occurrences are not replayed,
+// since their weather would be out of date.
+func (s *Scheduler) CatchUp(ctx context.Context, window time.Duration) {
+	if window <= 0 {
+		return
+	}
+	now := s.now()
+	for _, job := range s.jobs {
+		scheduledTime, ok := lastOccurrence(job.schedule, now.Add(-window), now)
+		if !ok {
+			continue
+		}
+		s.logger.InfoContext(ctx, "Checking for missed run", "job", job.name, "scheduled_time", scheduledTime)
+		s.RunJob(ctx, job.name, scheduledTime, job.fn)
+	}
+}
+
+func lastOccurrence(schedule cron.Schedule, from, to time.Time) (time.Time, bool) {
+	var last time.Time
+	for t := schedule.Next(from.Add(-time.Second)); !t.After(to); t = schedule.Next(t) {
+		last = t
+	}
+	return last, !last.IsZero()
+}
+
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	s.started = true
+	s.mu.Unlock()
+	s.cron.Start()
+}
+
+// Status reports which jobs this instance is running and when they are scheduled next.
+func (s *Scheduler) Status() domain.SchedulerStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	status := domain.SchedulerStatus{Running: s.started, Jobs: []domain.JobStatus{}}
+	for _, job := range s.jobs {
+		status.Jobs = append(status.Jobs, domain.JobStatus{
+			Name:    job.name,
+			Running: s.active[job.name] > 0,
+			NextRun: job.schedule.Next(now),
+		})
+	}
+	return status
+}
+
+// Shutdown stops scheduling new runs and waits for running jobs until ctx is done. Jobs still
+// running then are interrupted and given interruptGrace to save their progress; their runs are
+// marked interrupted so that they can be resumed later.
+func (s *Scheduler) Shutdown(ctx context.Context, interruptGrace time.Duration) error {
+	s.mu.Lock()
+	s.started = false
+	s.stopping = true
+	s.mu.Unlock()
+	s.cron.Stop()
+
+	done := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.interrupt()
+		return nil
+	case <-ctx.Done():
+	}
+
+	s.logger.WarnContext(ctx, "Interrupting running jobs")
+	s.interrupt()
+	select {
+	case <-done:
+		return nil
+	case <-time.After(interruptGrace):
+		return errors.New("running jobs did not stop in time")
+	}
+}
+
+// RunJob executes fn for the given occurrence of the job unless another instance already did.
+// It reports whether fn was executed by this instance. Once Shutdown has begun no new runs start.
+func (s *Scheduler) RunJob(ctx context.Context, name string, scheduledTime time.Time, fn JobFunc) bool {
+	// Adding to running under the lock keeps it from racing with the Wait in Shutdown.
+	s.mu.Lock()
+	if s.stopping {
+		s.mu.Unlock()
+		s.logger.InfoContext(ctx, "Skipping job, scheduler is shutting down", "job", name, "scheduled_time", scheduledTime)
+		return false
+	}
+	s.running.Add(1)
+	s.mu.Unlock()
+	defer s.running.Done()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer context.AfterFunc(s.ctx, cancel)()
+	if ctx.Err() != nil {
+		return false
+	}
+
+	lockCtx, cancel := context.WithTimeout(ctx, s.lockWait)
+	release, acquired, err := s.runs.AcquireLock(lockCtx, name)
+	cancel()
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to lock job", "job", name, "error", err)
+		return false
+	}
+	if !acquired {
+		s.logger.InfoContext(ctx, "Skipping job, lock is held by another instance", "job", name, "scheduled_time", scheduledTime)
+		return false
+	}
+	defer release()
+
+	run, claimed, err := s.runs.ClaimRun(ctx, name, scheduledTime, s.owner)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to claim job", "job", name, "scheduled_time", scheduledTime, "error", err)
+		return false
+	}
+	if !claimed {
+		s.logger.InfoContext(ctx, "Skipping job, already handled by another instance", "job", name, "scheduled_time", scheduledTime)
+		return false
+	}
+
+	s.execute(ctx, name, run, fn)
+	return true
+}
+
+// Trigger starts a run of the job outside its schedule and returns the run without waiting for
+// fn, which keeps running after ctx is done. The run takes the lock of the job like scheduled
+// runs do, so the two never overlap; when the lock isn't free within wait, Trigger returns
+// ErrJobBusy.
+func (s *Scheduler) Trigger(ctx context.Context, name string, wait time.Duration, fn JobFunc) (domain.JobRun, error) {
+	s.mu.Lock()
+	if s.stopping {
+		s.mu.Unlock()
+		return domain.JobRun{}, domain.ErrShuttingDown
+	}
+	s.running.Add(1)
+	s.mu.Unlock()
+	started := false
+	defer func() {
+		if !started {
+			s.running.Done()
+		}
+	}()
+
+	lockCtx, cancel := context.WithTimeout(ctx, wait)
+	release, acquired, err := s.runs.AcquireLock(lockCtx, name)
+	cancel()
+	if err != nil {
+		return domain.JobRun{}, err
+	}
+	if !acquired {
+		return domain.JobRun{}, domain.ErrJobBusy
+	}
+	run, claimed, err := s.runs.ClaimRun(ctx, name, s.now(), s.owner)
+	if err != nil || !claimed {
+		release()
+		if err == nil {
+			err = domain.ErrJobBusy
+		}
+		return domain.JobRun{}, err
+	}
+
+	started = true
+	go func() {
+		defer s.running.Done()
+		defer release()
+		ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		defer cancel()
+		defer context.AfterFunc(s.ctx, cancel)()
+		s.execute(ctx, name, run, fn)
+	}()
+	return run, nil
+}
+
+// execute runs fn for a claimed run and records how it ended.
+func (s *Scheduler) execute(ctx context.Context, name string, run domain.JobRun, fn JobFunc) {
+	s.logger.InfoContext(ctx, "Running job", "job", name, "scheduled_time", run.ScheduledTime, "run_id", run.ID)
+	s.setActive(name, 1)
+	defer s.setActive(name, -1)
+
+	status, errMsg := domain.JobRunCompleted, ""
+	if err := fn(ctx, run); err != nil {
+		status, errMsg = domain.JobRunFailed, err.Error()
+		if ctx.Err() != nil {
+			status = domain.JobRunInterrupted
+		}
+		s.logger.ErrorContext(ctx, "Job run did not complete", "job", name, "run_id", run.ID, "status", status, "error", err)
+	}
+	if err := s.runs.FinishRun(context.Background(), run.ID, status, errMsg); err != nil {
+		s.logger.ErrorContext(ctx, "Failed to record job result", "job", name, "run_id", run.ID, "error", err)
+	}
+}
+
+func (s *Scheduler) setActive(name string, delta int) {
+	s.mu.Lock()
+	s.active[name] += delta
+	s.mu.Unlock()
+}
@@ -0,0 +1,337 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"weather-api/internal/mocks"
+
+	"github.com/robfig/cron/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"weather-api/internal/core/domain"
+)
+
+func TestScheduler_RunJob(t *testing.T) {
+	ctx := context.Background()
+	job := "daily_updates"
+	owner := "replica-1"
+	scheduledTime := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	run := domain.JobRun{ID: 7, Job: job, ScheduledTime: scheduledTime, Status: domain.JobRunRunning, Owner: owner}
+
+	tests := []struct {
+		name        string
+		jobErr      error
+		setupMocks  func(runs *mocks.MockJobRunRepository, released *bool)
+		verifyMocks func(t *testing.T, runs *mocks.MockJobRunRepository, released bool)
+		expectedRan bool
+	}{
+		{
+			name: "claims and completes the run",
+			setupMocks: func(runs *mocks.MockJobRunRepository, released *bool) {
+				runs.On("AcquireLock", mock.Anything, job).Return(func() { *released = true }, true, nil)
+				runs.On("ClaimRun", mock.Anything, job, scheduledTime, owner).Return(run, true, nil)
+				runs.On("FinishRun", mock.Anything, run.ID, domain.JobRunCompleted, "").Return(nil)
+			},
+			verifyMocks: func(t *testing.T, runs *mocks.MockJobRunRepository, released bool) {
+				runs.AssertExpectations(t)
+				assert.True(t, released, "lock should be released")
+			},
+			expectedRan: true,
+		},
+		{
+			name:   "records failed run",
+			jobErr: errors.New("weather lookup failed"),
+			setupMocks: func(runs *mocks.MockJobRunRepository, released *bool) {
+				runs.On("AcquireLock", mock.Anything, job).Return(func() { *released = true }, true, nil)
+				runs.On("ClaimRun", mock.Anything, job, scheduledTime, owner).Return(run, true, nil)
+				runs.On("FinishRun", mock.Anything, run.ID, domain.JobRunFailed, "weather lookup failed").Return(nil)
+			},
+			verifyMocks: func(t *testing.T, runs *mocks.MockJobRunRepository, released bool) {
+				runs.AssertExpectations(t)
+				assert.True(t, released, "lock should be released")
+			},
+			expectedRan: true,
+		},
+		{
+			name: "run already completed by another instance",
+			setupMocks: func(runs *mocks.MockJobRunRepository, released *bool) {
+				runs.On("AcquireLock", mock.Anything, job).Return(func() { *released = true }, true, nil)
+				runs.On("ClaimRun", mock.Anything, job, scheduledTime, owner).Return(domain.JobRun{}, false, nil)
+			},
+			verifyMocks: func(t *testing.T, runs *mocks.MockJobRunRepository, released bool) {
+				runs.AssertNotCalled(t, "FinishRun", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				assert.True(t, released, "lock should be released")
+			},
+		},
+		{
+			name: "lock not acquired in time",
+			setupMocks: func(runs *mocks.MockJobRunRepository, released *bool) {
+				runs.On("AcquireLock", mock.Anything, job).Return(nil, false, nil)
+			},
+			verifyMocks: func(t *testing.T, runs *mocks.MockJobRunRepository, released bool) {
+				runs.AssertNotCalled(t, "ClaimRun", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs := &mocks.MockJobRunRepository{}
+			scheduler := NewScheduler(runs, owner, time.Second, testLogger)
+			released := false
+			called := false
+
+			tt.setupMocks(runs, &released)
+
+			ran := scheduler.RunJob(ctx, job, scheduledTime, func(ctx context.Context, run domain.JobRun) error {
+				called = true
+				return tt.jobErr
+			})
+
+			assert.Equal(t, tt.expectedRan, ran)
+			assert.Equal(t, tt.expectedRan, called)
+			tt.verifyMocks(t, runs, released)
+		})
+	}
+}
+
+func TestScheduler_CatchUp(t *testing.T) {
+	ctx := context.Background()
+	owner := "replica-1"
+	now := time.Date(2025, 5, 1, 0, 0, 30, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		window      time.Duration
+		setupMocks  func(runs *mocks.MockJobRunRepository)
+		verifyMocks func(t *testing.T, runs *mocks.MockJobRunRepository)
+		expectedRun []string
+	}{
+		{
+			name:   "runs the latest missed occurrence of each job",
+			window: 2 * time.Hour,
+			setupMocks: func(runs *mocks.MockJobRunRepository) {
+				runs.On("AcquireLock", mock.Anything, mock.Anything).Return(func() {}, true, nil)
+				hourly := domain.JobRun{ID: 1, Job: "hourly_updates"}
+				daily := domain.JobRun{ID: 2, Job: "daily_updates"}
+				runs.On("ClaimRun", mock.Anything, "hourly_updates", time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC), owner).Return(hourly, true, nil)
+				runs.On("ClaimRun", mock.Anything, "daily_updates", time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC), owner).Return(daily, true, nil)
+				runs.On("FinishRun", mock.Anything, mock.Anything, domain.JobRunCompleted, "").Return(nil)
+			},
+			verifyMocks: func(t *testing.T, runs *mocks.MockJobRunRepository) {
+				runs.AssertExpectations(t)
+				runs.AssertNumberOfCalls(t, "ClaimRun", 2)
+			},
+			expectedRun: []string{"hourly_updates", "daily_updates"},
+		},
+		{
+			name:   "skips occurrences that already completed",
+			window: 2 * time.Hour,
+			setupMocks: func(runs *mocks.MockJobRunRepository) {
+				runs.On("AcquireLock", mock.Anything, mock.Anything).Return(func() {}, true, nil)
+				runs.On("ClaimRun", mock.Anything, mock.Anything, mock.Anything, owner).Return(domain.JobRun{}, false, nil)
+			},
+			verifyMocks: func(t *testing.T, runs *mocks.MockJobRunRepository) {
+				runs.AssertNotCalled(t, "FinishRun", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name:       "disabled",
+			window:     0,
+			setupMocks: func(runs *mocks.MockJobRunRepository) {},
+			verifyMocks: func(t *testing.T, runs *mocks.MockJobRunRepository) {
+				runs.AssertNotCalled(t, "AcquireLock", mock.Anything, mock.Anything)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs := &mocks.MockJobRunRepository{}
+			scheduler := NewScheduler(runs, owner, time.Second, testLogger)
+			scheduler.now = func() time.Time { return now }
+			var ran []string
+			record := func(ctx context.Context, run domain.JobRun) error {
+				ran = append(ran, run.Job)
+				return nil
+			}
+			assert.NoError(t, scheduler.AddJob("hourly_updates", "0 * * * *", record))
+			assert.NoError(t, scheduler.AddJob("daily_updates", "0 0 * * *", record))
+
+			tt.setupMocks(runs)
+
+			scheduler.CatchUp(ctx, tt.window)
+
+			assert.Equal(t, tt.expectedRun, ran)
+			tt.verifyMocks(t, runs)
+		})
+	}
+}
+
+func TestLastOccurrence(t *testing.T) {
+	schedule, err := cron.ParseStandard("0 * * * *")
+	assert.NoError(t, err)
+	now := time.Date(2025, 5, 1, 14, 20, 0, 0, time.UTC)
+
+	last, ok := lastOccurrence(schedule, now.Add(-3*time.Hour), now)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2025, 5, 1, 14, 0, 0, 0, time.UTC), last)
+
+	_, ok = lastOccurrence(schedule, now.Add(-10*time.Minute), now)
+	assert.False(t, ok)
+}
+
+func TestScheduler_Shutdown(t *testing.T) {
+	tests := []struct {
+		name           string
+		job            func(started chan<- struct{}) JobFunc
+		expectedStatus domain.JobRunStatus
+	}{
+		{
+			name: "waits for running job to finish",
+			job: func(started chan<- struct{}) JobFunc {
+				return func(ctx context.Context, run domain.JobRun) error {
+					close(started)
+					time.Sleep(20 * time.Millisecond)
+					return nil
+				}
+			},
+			expectedStatus: domain.JobRunCompleted,
+		},
+		{
+			name: "interrupts job that outlives the drain timeout",
+			job: func(started chan<- struct{}) JobFunc {
+				return func(ctx context.Context, run domain.JobRun) error {
+					close(started)
+					<-ctx.Done()
+					return ctx.Err()
+				}
+			},
+			expectedStatus: domain.JobRunInterrupted,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs := &mocks.MockJobRunRepository{}
+			scheduler := NewScheduler(runs, "replica-1", time.Second, testLogger)
+			runs.On("AcquireLock", mock.Anything, "daily_updates").Return(func() {}, true, nil)
+			runs.On("ClaimRun", mock.Anything, "daily_updates", mock.Anything, "replica-1").Return(domain.JobRun{ID: 1}, true, nil)
+			runs.On("FinishRun", mock.Anything, 1, tt.expectedStatus, mock.Anything).Return(nil)
+
+			started := make(chan struct{})
+			go scheduler.RunJob(context.Background(), "daily_updates", time.Now(), tt.job(started))
+			<-started
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			err := scheduler.Shutdown(ctx, time.Second)
+
+			assert.NoError(t, err)
+			runs.AssertExpectations(t)
+		})
+	}
+}
+
+func TestScheduler_RefusesRunsAfterShutdown(t *testing.T) {
+	runs := &mocks.MockJobRunRepository{}
+	scheduler := NewScheduler(runs, "replica-1", time.Second, testLogger)
+	assert.NoError(t, scheduler.Shutdown(context.Background(), time.Second))
+
+	ran := scheduler.RunJob(context.Background(), "daily_updates", time.Now(), func(ctx context.Context, run domain.JobRun) error {
+		t.Error("job ran after shutdown")
+		return nil
+	})
+
+	assert.False(t, ran)
+	runs.AssertNotCalled(t, "AcquireLock", mock.Anything, mock.Anything)
+}
+
+func TestScheduler_Trigger(t *testing.T) {
+	now := time.Date(2025, 5, 1, 12, 17, 3, 0, time.UTC)
+	run := domain.JobRun{ID: 9, Job: "daily_updates", ScheduledTime: now, Status: domain.JobRunRunning, Owner: "replica-1"}
+	runs := &mocks.MockJobRunRepository{}
+	scheduler := NewScheduler(runs, "replica-1", time.Second, testLogger)
+	scheduler.now = func() time.Time { return now }
+	released := false
+	runs.On("AcquireLock", mock.Anything, "daily_updates").Return(func() { released = true }, true, nil)
+	runs.On("ClaimRun", mock.Anything, "daily_updates", now, "replica-1").Return(run, true, nil)
+	runs.On("FinishRun", mock.Anything, 9, domain.JobRunCompleted, "").Return(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	proceed := make(chan struct{})
+	var ranWith domain.JobRun
+	got, err := scheduler.Trigger(ctx, "daily_updates", time.Second, func(ctx context.Context, run domain.JobRun) error {
+		<-proceed
+		ranWith = run
+		return ctx.Err()
+	})
+	// The run outlives the request that started it.
+	cancel()
+	close(proceed)
+
+	assert.NoError(t, err)
+	assert.Equal(t, run, got)
+	assert.NoError(t, scheduler.Shutdown(context.Background(), time.Second))
+	assert.Equal(t, run, ranWith)
+	assert.True(t, released, "lock should be released")
+	runs.AssertExpectations(t)
+}
+
+func TestScheduler_TriggerErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		shutdown      bool
+		setupMocks    func(runs *mocks.MockJobRunRepository, released *bool)
+		expectedError error
+		expectRelease bool
+	}{
+		{
+			name: "lock held by a scheduled run",
+			setupMocks: func(runs *mocks.MockJobRunRepository, released *bool) {
+				runs.On("AcquireLock", mock.Anything, "daily_updates").Return(nil, false, nil)
+			},
+			expectedError: domain.ErrJobBusy,
+		},
+		{
+			name: "run not claimed",
+			setupMocks: func(runs *mocks.MockJobRunRepository, released *bool) {
+				runs.On("AcquireLock", mock.Anything, "daily_updates").Return(func() { *released = true }, true, nil)
+				runs.On("ClaimRun", mock.Anything, "daily_updates", mock.Anything, "replica-1").Return(domain.JobRun{}, false, nil)
+			},
+			expectedError: domain.ErrJobBusy,
+			expectRelease: true,
+		},
+		{
+			name:          "scheduler shutting down",
+			shutdown:      true,
+			setupMocks:    func(runs *mocks.MockJobRunRepository, released *bool) {},
+			expectedError: domain.ErrShuttingDown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs := &mocks.MockJobRunRepository{}
+			scheduler := NewScheduler(runs, "replica-1", time.Second, testLogger)
+			released := false
+			tt.setupMocks(runs, &released)
+			if tt.shutdown {
+				assert.NoError(t, scheduler.Shutdown(context.Background(), time.Second))
+			}
+
+			_, err := scheduler.Trigger(context.Background(), "daily_updates", time.Second, func(ctx context.Context, run domain.JobRun) error {
+				t.Error("job ran")
+				return nil
+			})
+
+			assert.ErrorIs(t, err, tt.expectedError)
+			assert.Equal(t, tt.expectRelease, released)
+			assert.NoError(t, scheduler.Shutdown(context.Background(), time.Second), "nothing should be left running")
+			runs.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/util"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// The methods below back the Telegram bot, where a subscription is addressed by its chat. Only
+// members of a chat can send it commands, so its subscription needs no confirmation.
+
+// SubscribeChat subscribes a Telegram chat, or changes the city and frequency of the
+// subscription it already has.
+func (s *SubscriptionService) SubscribeChat(ctx context.Context, chatID, city string, frequency domain.Frequency) (_ domain.Subscription, err error) {
+	ctx, span := tracer.Start(ctx, "SubscriptionService.SubscribeChat")
+	span.SetAttributes(attribute.String("city", city), attribute.String("frequency", string(frequency)))
+	defer func() { endSpan(span, err) }()
+
+	city, err = validateChatSubscription(city, frequency)
+	if err != nil {
+		s.logger.InfoContext(ctx, "Rejected invalid chat subscription", "error", err)
+		return domain.Subscription{}, err
+	}
+	if err := s.checkCity(ctx, city); err != nil {
+		return domain.Subscription{}, err
+	}
+
+	existing, err := s.repo.GetSubscriptionByAddress(ctx, domain.ChannelTelegram, chatID)
+	switch {
+	case err == nil:
+		ctx = util.WithSubscriptionID(ctx, existing.ID)
+		existing.City, existing.Frequency = city, frequency
+		updated, err := s.repo.UpdateSubscriptionByID(ctx, existing)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to update chat subscription", "error", err)
+			return domain.Subscription{}, err
+		}
+		s.logger.InfoContext(ctx, "Successfully updated chat subscription")
+		return updated, nil
+	case !errors.Is(err, domain.ErrSubscriptionNotFound):
+		s.logger.ErrorContext(ctx, "Failed to get chat subscription", "error", err)
+		return domain.Subscription{}, err
+	}
+
+	token, err := s.tokenSvc.GenerateToken()
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to generate token", "error", err)
+		return domain.Subscription{}, err
+	}
+	sub := domain.Subscription{
+		City:        city,
+		Frequency:   frequency,
+		Token:       token,
+		IsConfirmed: true,
+		Channel:     domain.ChannelTelegram,
+		Address:     chatID,
+	}
+	sub.ID, err = s.repo.CreateSubscription(ctx, sub)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to create chat subscription", "error", err)
+		return domain.Subscription{}, err
+	}
+	sub.Version = 1
+	ctx = util.WithSubscriptionID(ctx, sub.ID)
+	span.SetAttributes(attribute.Int("subscription.id", sub.ID))
+
+	s.logger.InfoContext(ctx, "Successfully created chat subscription")
+	return sub, nil
+}
+
+// UnsubscribeChat deletes the subscription of a Telegram chat.
+func (s *SubscriptionService) UnsubscribeChat(ctx context.Context, chatID string) (err error) {
+	ctx, span := tracer.Start(ctx, "SubscriptionService.UnsubscribeChat")
+	defer func() { endSpan(span, err) }()
+
+	sub, err := s.repo.GetSubscriptionByAddress(ctx, domain.ChannelTelegram, chatID)
+	if err != nil {
+		return err
+	}
+	ctx = util.WithSubscriptionID(ctx, sub.ID)
+	if err := s.repo.DeleteSubscriptionByID(ctx, sub.ID, 0); err != nil {
+		s.logger.ErrorContext(ctx, "Failed to delete chat subscription", "error", err)
+		return err
+	}
+
+	s.logger.InfoContext(ctx, "Successfully unsubscribed chat")
+	return nil
+}
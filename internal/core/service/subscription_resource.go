@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/util"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// The methods below back the v2 API, where a subscription is addressed by its ID and the token
+// from one of its emails proves that the caller owns it. version is the one the caller last saw;
+// 0 skips the check.
+
+// Get returns subscription id if token was issued for it.
+func (s *SubscriptionService) Get(ctx context.Context, id int, token string) (domain.Subscription, error) {
+	return s.authorize(ctx, id, token)
+}
+
+func (s *SubscriptionService) Update(ctx context.Context, id int, token string, version int, patch domain.SubscriptionPatch) (_ domain.Subscription, err error) {
+	ctx, span := tracer.Start(ctx, "SubscriptionService.Update")
+	span.SetAttributes(attribute.Int("subscription.id", id))
+	defer func() { endSpan(span, err) }()
+	ctx = util.WithSubscriptionID(ctx, id)
+
+	sub, err := s.authorize(ctx, id, token)
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+	if version != 0 && version != sub.Version {
+		return domain.Subscription{}, domain.ErrPreconditionFailed
+	}
+
+	fields := map[string]string{}
+	cityChanged := false
+	if patch.City != nil {
+		city, problem := normalizeCity(*patch.City)
+		if problem != "" {
+			fields["city"] = problem
+		}
+		cityChanged = city != sub.City
+		sub.City = city
+	}
+	if patch.Frequency != nil {
+		if *patch.Frequency != domain.FrequencyDaily && *patch.Frequency != domain.FrequencyHourly {
+			fields["frequency"] = "must be hourly or daily"
+		}
+		sub.Frequency = *patch.Frequency
+	}
+	if len(fields) > 0 {
+		return domain.Subscription{}, &domain.ValidationError{Fields: fields}
+	}
+	if cityChanged {
+		if err := s.checkCity(ctx, sub.City); err != nil {
+			return domain.Subscription{}, err
+		}
+	}
+
+	updated, err := s.repo.UpdateSubscriptionByID(ctx, sub)
+	if err != nil {
+		if !errors.Is(err, domain.ErrPreconditionFailed) {
+			s.logger.ErrorContext(ctx, "Failed to update subscription", "error", err)
+		}
+		return domain.Subscription{}, err
+	}
+	s.logger.InfoContext(ctx, "Successfully updated subscription", "city", updated.City, "frequency", updated.Frequency)
+	return updated, nil
+}
+
+func (s *SubscriptionService) Delete(ctx context.Context, id int, token string, version int) (err error) {
+	ctx, span := tracer.Start(ctx, "SubscriptionService.Delete")
+	span.SetAttributes(attribute.Int("subscription.id", id))
+	defer func() { endSpan(span, err) }()
+	ctx = util.WithSubscriptionID(ctx, id)
+
+	sub, err := s.authorize(ctx, id, token)
+	if err != nil {
+		return err
+	}
+	if version != 0 && version != sub.Version {
+		return domain.ErrPreconditionFailed
+	}
+	if err := s.repo.DeleteSubscriptionByID(ctx, id, version); err != nil {
+		if !errors.Is(err, domain.ErrPreconditionFailed) {
+			s.logger.ErrorContext(ctx, "Failed to delete subscription", "error", err)
+		}
+		return err
+	}
+
+	s.logger.InfoContext(ctx, "Successfully unsubscribed")
+	return nil
+}
+
+// ConfirmByID confirms subscription id with the token from its confirmation email.
+func (s *SubscriptionService) ConfirmByID(ctx context.Context, id int, token string) (_ domain.Subscription, err error) {
+	ctx, span := tracer.Start(ctx, "SubscriptionService.ConfirmByID")
+	span.SetAttributes(attribute.Int("subscription.id", id))
+	defer func() { endSpan(span, err) }()
+	ctx = util.WithSubscriptionID(ctx, id)
+
+	sub, err := s.subscriptionForToken(ctx, id, token, domain.TokenPurposeConfirm)
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+	if sub.IsConfirmed {
+		return domain.Subscription{}, domain.ErrAlreadyConfirmed
+	}
+
+	sub.IsConfirmed = true
+	confirmed, err := s.repo.UpdateSubscriptionByID(ctx, sub)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to update subscription confirmation", "error", err)
+		return domain.Subscription{}, err
+	}
+	s.logger.InfoContext(ctx, "Successfully confirmed subscription")
+	return confirmed, nil
+}
+
+// authorize accepts any token sent to the subscriber: the one in the confirmation email as well
+// as the unsubscribe one from the updates.
+func (s *SubscriptionService) authorize(ctx context.Context, id int, token string) (domain.Subscription, error) {
+	return s.subscriptionForToken(ctx, id, token, domain.TokenPurposeUnsubscribe, domain.TokenPurposeConfirm)
+}
+
+// subscriptionForToken returns subscription id if token is the one stored with it or was signed
+// for it with one of purposes. Tokens of other subscriptions are answered as if id did not
+// exist, so that IDs can't be probed.
+func (s *SubscriptionService) subscriptionForToken(ctx context.Context, id int, token string, purposes ...domain.TokenPurpose) (domain.Subscription, error) {
+	if token == "" {
+		return domain.Subscription{}, domain.ErrUnauthorized
+	}
+
+	if s.isSignedToken(token) {
+		claims, err := s.parseToken(token, purposes)
+		if err != nil {
+			s.logger.InfoContext(ctx, "Rejected signed token", "error", err)
+			return domain.Subscription{}, err
+		}
+		if claims.SubscriptionID != id {
+			return domain.Subscription{}, domain.ErrSubscriptionNotFound
+		}
+		return s.repo.GetSubscriptionByID(ctx, id)
+	}
+
+	sub, err := s.repo.GetSubscriptionByID(ctx, id)
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+	if subtle.ConstantTimeCompare([]byte(sub.Token), []byte(token)) != 1 {
+		return domain.Subscription{}, domain.ErrSubscriptionNotFound
+	}
+	return sub, nil
+}
+
+// parseToken accepts a signed token issued for any of purposes.
+func (s *SubscriptionService) parseToken(token string, purposes []domain.TokenPurpose) (domain.TokenClaims, error) {
+	err := domain.ErrInvalidToken
+	for _, purpose := range purposes {
+		var claims domain.TokenClaims
+		claims, err = s.signer.ParseToken(token, purpose)
+		if !errors.Is(err, domain.ErrInvalidToken) {
+			return claims, err
+		}
+	}
+	return domain.TokenClaims{}, err
+}
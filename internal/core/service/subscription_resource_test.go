@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+	"weather-api/internal/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"weather-api/internal/core/domain"
+)
+
+func TestSubscriptionService_Update(t *testing.T) {
+	ctx := context.Background()
+	sub := domain.Subscription{ID: 1, Email: "user1@example.com", City: "Kyiv", Frequency: domain.FrequencyDaily, Token: "token123", IsConfirmed: true, Version: 3}
+	city := func(v string) *string { return &v }
+	frequency := func(v domain.Frequency) *domain.Frequency { return &v }
+
+	tests := []struct {
+		name          string
+		token         string
+		version       int
+		patch         domain.SubscriptionPatch
+		setupMocks    func(repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService)
+		expectedError error
+	}{
+		{
+			name:    "change city and frequency",
+			token:   "token123",
+			version: 3,
+			patch:   domain.SubscriptionPatch{City: city("  Lviv "), Frequency: frequency(domain.FrequencyHourly)},
+			setupMocks: func(repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService) {
+				repo.On("GetSubscriptionByID", mock.Anything, 1).Return(sub, nil)
+				weatherSvc.On("GetWeather", mock.Anything, "Lviv").Return(domain.Weather{}, nil)
+				changed := sub
+				changed.City, changed.Frequency = "Lviv", domain.FrequencyHourly
+				updated := changed
+				updated.Version = 4
+				repo.On("UpdateSubscriptionByID", mock.Anything, changed).Return(updated, nil)
+			},
+		},
+		{
+			name:  "any version",
+			token: "token123",
+			patch: domain.SubscriptionPatch{Frequency: frequency(domain.FrequencyHourly)},
+			setupMocks: func(repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService) {
+				repo.On("GetSubscriptionByID", mock.Anything, 1).Return(sub, nil)
+				repo.On("UpdateSubscriptionByID", mock.Anything, mock.Anything).Return(sub, nil)
+			},
+		},
+		{
+			name:    "stale version",
+			token:   "token123",
+			version: 2,
+			patch:   domain.SubscriptionPatch{Frequency: frequency(domain.FrequencyHourly)},
+			setupMocks: func(repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService) {
+				repo.On("GetSubscriptionByID", mock.Anything, 1).Return(sub, nil)
+			},
+			expectedError: domain.ErrPreconditionFailed,
+		},
+		{
+			name:    "token of another subscription",
+			token:   "other",
+			version: 3,
+			patch:   domain.SubscriptionPatch{Frequency: frequency(domain.FrequencyHourly)},
+			setupMocks: func(repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService) {
+				repo.On("GetSubscriptionByID", mock.Anything, 1).Return(sub, nil)
+			},
+			expectedError: domain.ErrSubscriptionNotFound,
+		},
+		{
+			name:          "missing token",
+			version:       3,
+			patch:         domain.SubscriptionPatch{Frequency: frequency(domain.FrequencyHourly)},
+			setupMocks:    func(repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService) {},
+			expectedError: domain.ErrUnauthorized,
+		},
+		{
+			name:    "invalid fields",
+			token:   "token123",
+			version: 3,
+			patch:   domain.SubscriptionPatch{City: city(" "), Frequency: frequency("weekly")},
+			setupMocks: func(repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService) {
+				repo.On("GetSubscriptionByID", mock.Anything, 1).Return(sub, nil)
+			},
+			expectedError: domain.ErrInvalidInput,
+		},
+		{
+			name:    "unknown city",
+			token:   "token123",
+			version: 3,
+			patch:   domain.SubscriptionPatch{City: city("Atlantis")},
+			setupMocks: func(repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService) {
+				repo.On("GetSubscriptionByID", mock.Anything, 1).Return(sub, nil)
+				weatherSvc.On("GetWeather", mock.Anything, "Atlantis").Return(domain.Weather{}, domain.ErrCityNotFound)
+			},
+			expectedError: domain.ErrCityNotFound,
+		},
+		{
+			name:    "changed concurrently",
+			token:   "token123",
+			version: 3,
+			patch:   domain.SubscriptionPatch{Frequency: frequency(domain.FrequencyHourly)},
+			setupMocks: func(repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService) {
+				repo.On("GetSubscriptionByID", mock.Anything, 1).Return(sub, nil)
+				repo.On("UpdateSubscriptionByID", mock.Anything, mock.Anything).Return(domain.Subscription{}, domain.ErrPreconditionFailed)
+			},
+			expectedError: domain.ErrPreconditionFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.MockSubscriptionRepository{}
+			weatherSvc := &mocks.MockWeatherService{}
+			tt.setupMocks(repo, weatherSvc)
+			service := NewSubscriptionService(repo, weatherSvc, &mocks.MockEmailService{}, &mocks.MockTokenService{}, SubscriptionOptions{}, testLogger)
+
+			_, err := service.Update(ctx, 1, tt.token, tt.version, tt.patch)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+			weatherSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSubscriptionService_Delete(t *testing.T) {
+	ctx := context.Background()
+	sub := domain.Subscription{ID: 1, Token: "token123", Version: 2}
+
+	tests := []struct {
+		name          string
+		version       int
+		setupMocks    func(repo *mocks.MockSubscriptionRepository)
+		expectedError error
+	}{
+		{
+			name:    "current version",
+			version: 2,
+			setupMocks: func(repo *mocks.MockSubscriptionRepository) {
+				repo.On("GetSubscriptionByID", mock.Anything, 1).Return(sub, nil)
+				repo.On("DeleteSubscriptionByID", mock.Anything, 1, 2).Return(nil)
+			},
+		},
+		{
+			name: "any version",
+			setupMocks: func(repo *mocks.MockSubscriptionRepository) {
+				repo.On("GetSubscriptionByID", mock.Anything, 1).Return(sub, nil)
+				repo.On("DeleteSubscriptionByID", mock.Anything, 1, 0).Return(nil)
+			},
+		},
+		{
+			name:    "stale version",
+			version: 1,
+			setupMocks: func(repo *mocks.MockSubscriptionRepository) {
+				repo.On("GetSubscriptionByID", mock.Anything, 1).Return(sub, nil)
+			},
+			expectedError: domain.ErrPreconditionFailed,
+		},
+		{
+			name:    "not found",
+			version: 2,
+			setupMocks: func(repo *mocks.MockSubscriptionRepository) {
+				repo.On("GetSubscriptionByID", mock.Anything, 1).Return(domain.Subscription{}, domain.ErrSubscriptionNotFound)
+			},
+			expectedError: domain.ErrSubscriptionNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.MockSubscriptionRepository{}
+			tt.setupMocks(repo)
+			service := NewSubscriptionService(repo, &mocks.MockWeatherService{}, &mocks.MockEmailService{}, &mocks.MockTokenService{}, SubscriptionOptions{}, testLogger)
+
+			err := service.Delete(ctx, 1, "token123", tt.version)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSubscriptionService_ConfirmByID(t *testing.T) {
+	ctx := context.Background()
+	sub := domain.Subscription{ID: 42, Email: "user1@example.com", City: "Kyiv", Frequency: domain.FrequencyDaily, Token: "token123", Version: 1}
+
+	signer, err := NewHMACTokenService([]SigningKey{testKeyCurrent}, time.Hour, 0)
+	assert.NoError(t, err)
+	confirmToken, err := signer.IssueToken(sub.ID, domain.TokenPurposeConfirm)
+	assert.NoError(t, err)
+	unsubscribeToken, err := signer.IssueToken(sub.ID, domain.TokenPurposeUnsubscribe)
+	assert.NoError(t, err)
+	otherToken, err := signer.IssueToken(7, domain.TokenPurposeConfirm)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		token         string
+		setupMocks    func(repo *mocks.MockSubscriptionRepository)
+		expectedError error
+	}{
+		{
+			name:  "signed confirmation token",
+			token: confirmToken,
+			setupMocks: func(repo *mocks.MockSubscriptionRepository) {
+				repo.On("GetSubscriptionByID", mock.Anything, sub.ID).Return(sub, nil)
+				confirmed := sub
+				confirmed.IsConfirmed = true
+				repo.On("UpdateSubscriptionByID", mock.Anything, confirmed).Return(confirmed, nil)
+			},
+		},
+		{
+			name:  "stored token",
+			token: "token123",
+			setupMocks: func(repo *mocks.MockSubscriptionRepository) {
+				repo.On("GetSubscriptionByID", mock.Anything, sub.ID).Return(sub, nil)
+				repo.On("UpdateSubscriptionByID", mock.Anything, mock.Anything).Return(sub, nil)
+			},
+		},
+		{
+			name:          "unsubscribe token does not confirm",
+			token:         unsubscribeToken,
+			setupMocks:    func(repo *mocks.MockSubscriptionRepository) {},
+			expectedError: domain.ErrInvalidToken,
+		},
+		{
+			name:          "token of another subscription",
+			token:         otherToken,
+			setupMocks:    func(repo *mocks.MockSubscriptionRepository) {},
+			expectedError: domain.ErrSubscriptionNotFound,
+		},
+		{
+			name:  "already confirmed",
+			token: confirmToken,
+			setupMocks: func(repo *mocks.MockSubscriptionRepository) {
+				confirmed := sub
+				confirmed.IsConfirmed = true
+				repo.On("GetSubscriptionByID", mock.Anything, sub.ID).Return(confirmed, nil)
+			},
+			expectedError: domain.ErrAlreadyConfirmed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.MockSubscriptionRepository{}
+			tt.setupMocks(repo)
+			service := NewSubscriptionService(repo, &mocks.MockWeatherService{}, &mocks.MockEmailService{}, &mocks.MockTokenService{}, SubscriptionOptions{Signer: signer}, testLogger)
+
+			_, err := service.ConfirmByID(ctx, sub.ID, tt.token)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
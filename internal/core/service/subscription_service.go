@@ -2,11 +2,15 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
 	"errors"
-	"log"
+	"log/slog"
+	"strings"
 	"weather-api/internal/core/domain"
 	"weather-api/internal/core/port"
 	"weather-api/internal/util"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type SubscriptionService struct {
@@ -14,43 +18,96 @@ type SubscriptionService struct {
 	weatherSvc port.WeatherService
 	emailSvc   port.EmailService
 	tokenSvc   port.TokenService
+	signer     port.SignedTokenService
+	mx         port.MXResolver
+	limiter    *RateLimiter
+	slack      port.SlackSender
+	logger     *slog.Logger
+}
+
+// SubscriptionOptions are the optional dependencies of a SubscriptionService. Without Signer
+// only the random tokens stored with the subscription are issued and accepted, without MX the
+// email domain isn't checked for accepting mail, and without Limiter an address can be
+// subscribed any number of times. Slack decides which URLs Slack subscriptions may use; without
+// it any http or https URL is accepted.
+type SubscriptionOptions struct {
+	Signer  port.SignedTokenService
+	MX      port.MXResolver
+	Limiter *RateLimiter
+	Slack   port.SlackSender
 }
 
-func NewSubscriptionService(repo port.SubscriptionRepository, weatherSvc port.WeatherService, emailSvc port.EmailService, tokenSvc port.TokenService) *SubscriptionService {
+func NewSubscriptionService(repo port.SubscriptionRepository, weatherSvc port.WeatherService, emailSvc port.EmailService, tokenSvc port.TokenService, opts SubscriptionOptions, logger *slog.Logger) *SubscriptionService {
 	return &SubscriptionService{
 		repo:       repo,
 		weatherSvc: weatherSvc,
 		emailSvc:   emailSvc,
 		tokenSvc:   tokenSvc,
+		signer:     opts.Signer,
+		mx:         opts.MX,
+		limiter:    opts.Limiter,
+		slack:      opts.Slack,
+		logger:     logger,
 	}
 }
 
+// Subscribe creates an unconfirmed subscription and emails the confirmation link. It returns
+// the token sent in the link.
 func (s *SubscriptionService) Subscribe(ctx context.Context, email string, city string, frequency domain.Frequency) (string, error) {
-	log.Printf("Attempting to create subscription for city: %s, frequency: %s", city, frequency)
+	_, token, err := s.subscribe(ctx, email, city, frequency, domain.ChannelEmail, "")
+	return token, err
+}
+
+// Create is Subscribe for clients that address the subscription by its ID afterwards. Updates
+// may go to a webhook at webhookURL instead of the email address, which still receives the
+// confirmation. The Webhook of a subscription on the webhook channel carries its signing secret.
+func (s *SubscriptionService) Create(ctx context.Context, email string, city string, frequency domain.Frequency, channel domain.Channel, webhookURL string) (domain.Subscription, error) {
+	sub, _, err := s.subscribe(ctx, email, city, frequency, channel, webhookURL)
+	return sub, err
+}
+
+func (s *SubscriptionService) subscribe(ctx context.Context, email string, city string, frequency domain.Frequency, channel domain.Channel, webhookURL string) (_ domain.Subscription, _ string, err error) {
+	ctx, span := tracer.Start(ctx, "SubscriptionService.Subscribe")
+	span.SetAttributes(attribute.String("city", city), attribute.String("frequency", string(frequency)), attribute.String("channel", string(channel)))
+	defer func() { endSpan(span, err) }()
+
+	email, city, err = validateSubscription(email, city, frequency)
+	if err == nil {
+		channel, webhookURL, err = validateChannel(channel, webhookURL, s.slack)
+	}
+	if err != nil {
+		s.logger.InfoContext(ctx, "Rejected invalid subscription", "error", err)
+		return domain.Subscription{}, "", err
+	}
+	// Every subscribe request sends an email, so limit them per address and not only per client.
+	if s.limiter != nil {
+		if err := s.limiter.Check(ctx, RateLimitSubscribeEmail, email); err != nil {
+			return domain.Subscription{}, "", err
+		}
+	}
+	if err := s.checkMailDomain(ctx, email); err != nil {
+		return domain.Subscription{}, "", err
+	}
+
+	s.logger.InfoContext(ctx, "Attempting to create subscription", "email", email, "city", city, "frequency", frequency)
 
 	isSubscribed, err := s.repo.IsEmailSubscribed(ctx, email)
 	if err != nil {
-		log.Printf("Failed to check email subscription: %v", err)
-		return "", err
+		s.logger.ErrorContext(ctx, "Failed to check email subscription", "error", err)
+		return domain.Subscription{}, "", err
 	}
 	if isSubscribed {
-		return "", domain.ErrEmailAlreadySubscribed
+		return domain.Subscription{}, "", domain.ErrEmailAlreadySubscribed
 	}
 
-	_, err = s.weatherSvc.GetWeather(city)
-	if err != nil {
-		if errors.Is(err, domain.ErrCityNotFound) {
-			log.Printf("City not found: %s", city)
-			return "", domain.ErrCityNotFound
-		}
-		log.Printf("Failed to validate city: %v", err)
-		return "", err
+	if err := s.checkCity(ctx, city); err != nil {
+		return domain.Subscription{}, "", err
 	}
 
 	token, err := s.tokenSvc.GenerateToken()
 	if err != nil {
-		log.Printf("Failed to generate token: %v", err)
-		return "", err
+		s.logger.ErrorContext(ctx, "Failed to generate token", "error", err)
+		return domain.Subscription{}, "", err
 	}
 
 	sub := domain.Subscription{
@@ -59,76 +116,213 @@ func (s *SubscriptionService) Subscribe(ctx context.Context, email string, city
 		Frequency:   frequency,
 		Token:       token,
 		IsConfirmed: false,
+		Channel:     channel,
+	}
+	switch channel {
+	case domain.ChannelWebhook:
+		sub.Webhook = &domain.Webhook{URL: webhookURL, Secret: "whsec_" + rand.Text()}
+	case domain.ChannelSlack:
+		sub.Address = webhookURL
+	}
+	sub.ID, err = s.repo.CreateSubscription(ctx, sub)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to create subscription in repository", "error", err)
+		return domain.Subscription{}, "", err
 	}
-	if err := s.repo.CreateSubscription(ctx, sub); err != nil {
-		log.Printf("Failed to create subscription in repository: %v", err)
-		return "", err
+	sub.Version = 1
+	if sub.Webhook != nil {
+		sub.Webhook.SubscriptionID = sub.ID
+	}
+	ctx = util.WithSubscriptionID(ctx, sub.ID)
+	span.SetAttributes(attribute.Int("subscription.id", sub.ID))
+
+	if s.signer != nil {
+		token, err = s.signer.IssueToken(sub.ID, domain.TokenPurposeConfirm)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to issue confirmation token", "error", err)
+			return domain.Subscription{}, "", err
+		}
 	}
 
 	subject, htmlBody := util.BuildConfirmationEmail(city, token)
 	err = s.emailSvc.SendEmail(email, subject, htmlBody)
 	if err != nil {
-		log.Printf("Failed to send confirmation email: %v", err)
-		return "", err
+		s.logger.ErrorContext(ctx, "Failed to send confirmation email", "error", err)
+		return domain.Subscription{}, "", err
 	}
 
-	log.Printf("Successfully created subscription")
-	return token, nil
+	s.logger.InfoContext(ctx, "Successfully created subscription")
+	return sub, token, nil
 }
 
-func (s *SubscriptionService) Confirm(ctx context.Context, token string) error {
-	log.Printf("Attempting to confirm subscription")
+func (s *SubscriptionService) Confirm(ctx context.Context, token string) (err error) {
+	ctx, span := tracer.Start(ctx, "SubscriptionService.Confirm")
+	defer func() { endSpan(span, err) }()
+
+	s.logger.InfoContext(ctx, "Attempting to confirm subscription", "token", token)
 
 	if token == "" {
 		return domain.ErrInvalidToken
 	}
 
-	exists, err := s.repo.IsTokenExists(ctx, token)
-	if err != nil {
-		log.Printf("Failed to check token existence: %v", err)
-		return err
-	}
-	if !exists {
-		return domain.ErrTokenNotFound
-	}
+	var sub domain.Subscription
+	if s.isSignedToken(token) {
+		signedSub, err := s.getSubscriptionBySignedToken(ctx, token, domain.TokenPurposeConfirm)
+		if err != nil {
+			return err
+		}
+		sub = signedSub
+	} else {
+		exists, err := s.repo.IsTokenExists(ctx, token)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to check token existence", "error", err)
+			return err
+		}
+		if !exists {
+			return domain.ErrTokenNotFound
+		}
 
-	sub, err := s.repo.GetSubscriptionByToken(ctx, token)
-	if err != nil {
-		log.Printf("Failed to get subscription: %v", err)
-		return err
+		sub, err = s.repo.GetSubscriptionByToken(ctx, token)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to get subscription", "error", err)
+			return err
+		}
 	}
+	ctx = util.WithSubscriptionID(ctx, sub.ID)
+	span.SetAttributes(attribute.Int("subscription.id", sub.ID))
 	sub.IsConfirmed = true
 	if err := s.repo.UpdateSubscription(ctx, sub); err != nil {
-		log.Printf("Failed to update subscription confirmation: %v", err)
+		s.logger.ErrorContext(ctx, "Failed to update subscription confirmation", "error", err)
 		return err
 	}
 
-	log.Printf("Successfully confirmed subscription")
+	s.logger.InfoContext(ctx, "Successfully confirmed subscription")
 	return nil
 }
 
-func (s *SubscriptionService) Unsubscribe(ctx context.Context, token string) error {
-	log.Printf("Attempting to unsubscribe")
+func (s *SubscriptionService) Unsubscribe(ctx context.Context, token string) (err error) {
+	ctx, span := tracer.Start(ctx, "SubscriptionService.Unsubscribe")
+	defer func() { endSpan(span, err) }()
+
+	s.logger.InfoContext(ctx, "Attempting to unsubscribe", "token", token)
 
 	if token == "" {
 		return domain.ErrInvalidToken
 	}
-	//TODO: validate and generate the token better in future
 
-	exists, err := s.repo.IsTokenExists(ctx, token)
+	if s.isSignedToken(token) {
+		sub, err := s.getSubscriptionBySignedToken(ctx, token, domain.TokenPurposeUnsubscribe)
+		if err != nil {
+			return err
+		}
+		ctx = util.WithSubscriptionID(ctx, sub.ID)
+		span.SetAttributes(attribute.Int("subscription.id", sub.ID))
+		token = sub.Token
+	} else {
+		exists, err := s.repo.IsTokenExists(ctx, token)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to check token existence", "error", err)
+			return err
+		}
+		if !exists {
+			return domain.ErrTokenNotFound
+		}
+	}
+
+	if err := s.repo.DeleteSubscription(ctx, token); err != nil {
+		s.logger.ErrorContext(ctx, "Failed to delete subscription", "error", err)
+		return err
+	}
+
+	s.logger.InfoContext(ctx, "Successfully unsubscribed")
+	return nil
+}
+
+func (s *SubscriptionService) ResendConfirmation(ctx context.Context, id int) (err error) {
+	ctx, span := tracer.Start(ctx, "SubscriptionService.ResendConfirmation")
+	span.SetAttributes(attribute.Int("subscription.id", id))
+	defer func() { endSpan(span, err) }()
+
+	ctx = util.WithSubscriptionID(ctx, id)
+	s.logger.InfoContext(ctx, "Resending confirmation")
+
+	sub, err := s.repo.GetSubscriptionByID(ctx, id)
 	if err != nil {
-		log.Printf("Failed to check token existence: %v", err)
 		return err
 	}
-	if !exists {
-		return domain.ErrTokenNotFound
+	if sub.IsConfirmed {
+		return domain.ErrAlreadyConfirmed
 	}
 
-	if err := s.repo.DeleteSubscription(ctx, token); err != nil {
-		log.Printf("Failed to delete subscription: %v", err)
+	token := sub.Token
+	if s.signer != nil {
+		token, err = s.signer.IssueToken(sub.ID, domain.TokenPurposeConfirm)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to issue confirmation token", "error", err)
+			return err
+		}
+	}
+
+	subject, htmlBody := util.BuildConfirmationEmail(sub.City, token)
+	if err := s.emailSvc.SendEmail(sub.Email, subject, htmlBody); err != nil {
+		s.logger.ErrorContext(ctx, "Failed to resend confirmation email", "error", err)
 		return err
 	}
 
-	log.Printf("Successfully unsubscribed")
+	s.logger.InfoContext(ctx, "Successfully resent confirmation")
 	return nil
 }
+
+// checkCity verifies that the weather provider knows city.
+func (s *SubscriptionService) checkCity(ctx context.Context, city string) error {
+	_, err := s.weatherSvc.GetWeather(ctx, city)
+	if err != nil {
+		if errors.Is(err, domain.ErrCityNotFound) {
+			s.logger.InfoContext(ctx, "City not found", "city", city)
+			return domain.ErrCityNotFound
+		}
+		s.logger.ErrorContext(ctx, "Failed to validate city", "error", err)
+		return err
+	}
+	return nil
+}
+
+// checkMailDomain rejects addresses whose domain cannot receive mail. Lookup failures are let
+// through, since the confirmation email is the final check anyway.
+func (s *SubscriptionService) checkMailDomain(ctx context.Context, email string) error {
+	if s.mx == nil {
+		return nil
+	}
+	host := email[strings.LastIndexByte(email, '@')+1:]
+	ok, err := s.mx.AcceptsMail(ctx, host)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Failed to look up email domain", "domain", host, "error", err)
+		return nil
+	}
+	if !ok {
+		return &domain.ValidationError{Fields: map[string]string{"email": "domain does not accept email"}}
+	}
+	return nil
+}
+
+func (s *SubscriptionService) isSignedToken(token string) bool {
+	return s.signer != nil && s.signer.IsSignedToken(token)
+}
+
+func (s *SubscriptionService) getSubscriptionBySignedToken(ctx context.Context, token string, purpose domain.TokenPurpose) (domain.Subscription, error) {
+	claims, err := s.signer.ParseToken(token, purpose)
+	if err != nil {
+		s.logger.InfoContext(ctx, "Rejected signed token", "purpose", purpose, "error", err)
+		return domain.Subscription{}, err
+	}
+
+	sub, err := s.repo.GetSubscriptionByID(ctx, claims.SubscriptionID)
+	if err != nil {
+		if errors.Is(err, domain.ErrSubscriptionNotFound) {
+			return domain.Subscription{}, domain.ErrTokenNotFound
+		}
+		s.logger.ErrorContext(ctx, "Failed to get subscription", "error", err)
+		return domain.Subscription{}, err
+	}
+	return sub, nil
+}
@@ -1,18 +1,29 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"log/slog"
+	"strings"
 	"testing"
+	"time"
 	"weather-api/internal/mocks"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 
 	"weather-api/internal/core/domain"
 	"weather-api/internal/util"
 )
 
+var testLogger = slog.New(slog.DiscardHandler)
+
 func TestSubscriptionService_Subscribe(t *testing.T) {
 	ctx := context.Background()
 	email := "user1@example.com"
@@ -36,8 +47,8 @@ func TestSubscriptionService_Subscribe(t *testing.T) {
 			frequency: frequency,
 			setupMocks: func(repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService, emailSvc *mocks.MockEmailService, tokenSvc *mocks.MockTokenService) {
 				token := "token123"
-				repo.On("IsEmailSubscribed", ctx, email).Return(false, nil)
-				weatherSvc.On("GetWeather", city).Return(domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny"}, nil)
+				repo.On("IsEmailSubscribed", mock.Anything, email).Return(false, nil)
+				weatherSvc.On("GetWeather", mock.Anything, city).Return(domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny"}, nil)
 				tokenSvc.On("GenerateToken").Return(token, nil)
 				sub := domain.Subscription{
 					Email:       email,
@@ -45,8 +56,9 @@ func TestSubscriptionService_Subscribe(t *testing.T) {
 					Frequency:   frequency,
 					Token:       token,
 					IsConfirmed: false,
+					Channel:     domain.ChannelEmail,
 				}
-				repo.On("CreateSubscription", ctx, sub).Return(nil)
+				repo.On("CreateSubscription", mock.Anything, sub).Return(1, nil)
 				subject, body := util.BuildConfirmationEmail(city, token)
 				emailSvc.On("SendEmail", email, subject, body).Return(nil)
 			},
@@ -65,11 +77,11 @@ func TestSubscriptionService_Subscribe(t *testing.T) {
 			city:      city,
 			frequency: frequency,
 			setupMocks: func(repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService, emailSvc *mocks.MockEmailService, tokenSvc *mocks.MockTokenService) {
-				repo.On("IsEmailSubscribed", ctx, email).Return(true, nil)
+				repo.On("IsEmailSubscribed", mock.Anything, email).Return(true, nil)
 			},
 			verifyMocks: func(t *testing.T, repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService, emailSvc *mocks.MockEmailService, tokenSvc *mocks.MockTokenService) {
 				repo.AssertExpectations(t)
-				weatherSvc.AssertNotCalled(t, "GetWeather", mock.Anything)
+				weatherSvc.AssertNotCalled(t, "GetWeather", mock.Anything, mock.Anything)
 				emailSvc.AssertNotCalled(t, "SendEmail", mock.Anything, mock.Anything, mock.Anything)
 				tokenSvc.AssertNotCalled(t, "GenerateToken")
 			},
@@ -84,7 +96,7 @@ func TestSubscriptionService_Subscribe(t *testing.T) {
 			weatherSvc := &mocks.MockWeatherService{}
 			emailSvc := &mocks.MockEmailService{}
 			tokenSvc := &mocks.MockTokenService{}
-			service := NewSubscriptionService(repo, weatherSvc, emailSvc, tokenSvc)
+			service := NewSubscriptionService(repo, weatherSvc, emailSvc, tokenSvc, SubscriptionOptions{}, testLogger)
 
 			tt.setupMocks(repo, weatherSvc, emailSvc, tokenSvc)
 
@@ -112,7 +124,7 @@ func TestSubscriptionService_Confirm(t *testing.T) {
 			name:  "success",
 			token: token,
 			setupMocks: func(repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService, emailSvc *mocks.MockEmailService, tokenSvc *mocks.MockTokenService) {
-				repo.On("IsTokenExists", ctx, token).Return(true, nil)
+				repo.On("IsTokenExists", mock.Anything, token).Return(true, nil)
 				sub := domain.Subscription{
 					Email:       "user1@example.com",
 					City:        "Kyiv",
@@ -120,14 +132,14 @@ func TestSubscriptionService_Confirm(t *testing.T) {
 					Token:       token,
 					IsConfirmed: false,
 				}
-				repo.On("GetSubscriptionByToken", ctx, token).Return(sub, nil)
+				repo.On("GetSubscriptionByToken", mock.Anything, token).Return(sub, nil)
 				updatedSub := sub
 				updatedSub.IsConfirmed = true
-				repo.On("UpdateSubscription", ctx, updatedSub).Return(nil)
+				repo.On("UpdateSubscription", mock.Anything, updatedSub).Return(nil)
 			},
 			verifyMocks: func(t *testing.T, repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService, emailSvc *mocks.MockEmailService, tokenSvc *mocks.MockTokenService) {
 				repo.AssertExpectations(t)
-				weatherSvc.AssertNotCalled(t, "GetWeather", mock.Anything)
+				weatherSvc.AssertNotCalled(t, "GetWeather", mock.Anything, mock.Anything)
 				emailSvc.AssertNotCalled(t, "SendEmail", mock.Anything, mock.Anything, mock.Anything)
 				tokenSvc.AssertNotCalled(t, "GenerateToken")
 			},
@@ -137,13 +149,13 @@ func TestSubscriptionService_Confirm(t *testing.T) {
 			name:  "token not found",
 			token: token,
 			setupMocks: func(repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService, emailSvc *mocks.MockEmailService, tokenSvc *mocks.MockTokenService) {
-				repo.On("IsTokenExists", ctx, token).Return(false, nil)
+				repo.On("IsTokenExists", mock.Anything, token).Return(false, nil)
 			},
 			verifyMocks: func(t *testing.T, repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService, emailSvc *mocks.MockEmailService, tokenSvc *mocks.MockTokenService) {
 				repo.AssertExpectations(t)
 				repo.AssertNotCalled(t, "GetSubscriptionByToken", mock.Anything, mock.Anything)
 				repo.AssertNotCalled(t, "UpdateSubscription", mock.Anything, mock.Anything)
-				weatherSvc.AssertNotCalled(t, "GetWeather", mock.Anything)
+				weatherSvc.AssertNotCalled(t, "GetWeather", mock.Anything, mock.Anything)
 				emailSvc.AssertNotCalled(t, "SendEmail", mock.Anything, mock.Anything, mock.Anything)
 				tokenSvc.AssertNotCalled(t, "GenerateToken")
 			},
@@ -153,7 +165,7 @@ func TestSubscriptionService_Confirm(t *testing.T) {
 			name:  "update subscription error",
 			token: token,
 			setupMocks: func(repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService, emailSvc *mocks.MockEmailService, tokenSvc *mocks.MockTokenService) {
-				repo.On("IsTokenExists", ctx, token).Return(true, nil)
+				repo.On("IsTokenExists", mock.Anything, token).Return(true, nil)
 				sub := domain.Subscription{
 					Email:       "user1@example.com",
 					City:        "Kyiv",
@@ -161,14 +173,14 @@ func TestSubscriptionService_Confirm(t *testing.T) {
 					Token:       token,
 					IsConfirmed: false,
 				}
-				repo.On("GetSubscriptionByToken", ctx, token).Return(sub, nil)
+				repo.On("GetSubscriptionByToken", mock.Anything, token).Return(sub, nil)
 				updatedSub := sub
 				updatedSub.IsConfirmed = true
-				repo.On("UpdateSubscription", ctx, updatedSub).Return(errors.New("db error"))
+				repo.On("UpdateSubscription", mock.Anything, updatedSub).Return(errors.New("db error"))
 			},
 			verifyMocks: func(t *testing.T, repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService, emailSvc *mocks.MockEmailService, tokenSvc *mocks.MockTokenService) {
 				repo.AssertExpectations(t)
-				weatherSvc.AssertNotCalled(t, "GetWeather", mock.Anything)
+				weatherSvc.AssertNotCalled(t, "GetWeather", mock.Anything, mock.Anything)
 				emailSvc.AssertNotCalled(t, "SendEmail", mock.Anything, mock.Anything, mock.Anything)
 				tokenSvc.AssertNotCalled(t, "GenerateToken")
 			},
@@ -182,7 +194,7 @@ func TestSubscriptionService_Confirm(t *testing.T) {
 			weatherSvc := &mocks.MockWeatherService{}
 			emailSvc := &mocks.MockEmailService{}
 			tokenSvc := &mocks.MockTokenService{}
-			service := NewSubscriptionService(repo, weatherSvc, emailSvc, tokenSvc)
+			service := NewSubscriptionService(repo, weatherSvc, emailSvc, tokenSvc, SubscriptionOptions{}, testLogger)
 
 			tt.setupMocks(repo, weatherSvc, emailSvc, tokenSvc)
 
@@ -209,12 +221,12 @@ func TestSubscriptionService_Unsubscribe(t *testing.T) {
 			name:  "success",
 			token: token,
 			setupMocks: func(repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService, emailSvc *mocks.MockEmailService, tokenSvc *mocks.MockTokenService) {
-				repo.On("IsTokenExists", ctx, token).Return(true, nil)
-				repo.On("DeleteSubscription", ctx, token).Return(nil)
+				repo.On("IsTokenExists", mock.Anything, token).Return(true, nil)
+				repo.On("DeleteSubscription", mock.Anything, token).Return(nil)
 			},
 			verifyMocks: func(t *testing.T, repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService, emailSvc *mocks.MockEmailService, tokenSvc *mocks.MockTokenService) {
 				repo.AssertExpectations(t)
-				weatherSvc.AssertNotCalled(t, "GetWeather", mock.Anything)
+				weatherSvc.AssertNotCalled(t, "GetWeather", mock.Anything, mock.Anything)
 				emailSvc.AssertNotCalled(t, "SendEmail", mock.Anything, mock.Anything, mock.Anything)
 				tokenSvc.AssertNotCalled(t, "GenerateToken")
 			},
@@ -224,12 +236,12 @@ func TestSubscriptionService_Unsubscribe(t *testing.T) {
 			name:  "deletion error",
 			token: token,
 			setupMocks: func(repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService, emailSvc *mocks.MockEmailService, tokenSvc *mocks.MockTokenService) {
-				repo.On("IsTokenExists", ctx, token).Return(true, nil)
-				repo.On("DeleteSubscription", ctx, token).Return(errors.New("not found"))
+				repo.On("IsTokenExists", mock.Anything, token).Return(true, nil)
+				repo.On("DeleteSubscription", mock.Anything, token).Return(errors.New("not found"))
 			},
 			verifyMocks: func(t *testing.T, repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService, emailSvc *mocks.MockEmailService, tokenSvc *mocks.MockTokenService) {
 				repo.AssertExpectations(t)
-				weatherSvc.AssertNotCalled(t, "GetWeather", mock.Anything)
+				weatherSvc.AssertNotCalled(t, "GetWeather", mock.Anything, mock.Anything)
 				emailSvc.AssertNotCalled(t, "SendEmail", mock.Anything, mock.Anything, mock.Anything)
 				tokenSvc.AssertNotCalled(t, "GenerateToken")
 			},
@@ -243,7 +255,7 @@ func TestSubscriptionService_Unsubscribe(t *testing.T) {
 			weatherSvc := &mocks.MockWeatherService{}
 			emailSvc := &mocks.MockEmailService{}
 			tokenSvc := &mocks.MockTokenService{}
-			service := NewSubscriptionService(repo, weatherSvc, emailSvc, tokenSvc)
+			service := NewSubscriptionService(repo, weatherSvc, emailSvc, tokenSvc, SubscriptionOptions{}, testLogger)
 
 			tt.setupMocks(repo, weatherSvc, emailSvc, tokenSvc)
 
@@ -254,3 +266,419 @@ func TestSubscriptionService_Unsubscribe(t *testing.T) {
 		})
 	}
 }
+
+func TestSubscriptionService_SignedTokens(t *testing.T) {
+	ctx := context.Background()
+	sub := domain.Subscription{
+		ID:          42,
+		Email:       "user1@example.com",
+		City:        "Kyiv",
+		Frequency:   domain.FrequencyDaily,
+		Token:       "token123",
+		IsConfirmed: false,
+	}
+
+	signer, err := NewHMACTokenService([]SigningKey{testKeyCurrent}, time.Hour, 0)
+	assert.NoError(t, err)
+	confirmToken, err := signer.IssueToken(sub.ID, domain.TokenPurposeConfirm)
+	assert.NoError(t, err)
+	unsubscribeToken, err := signer.IssueToken(sub.ID, domain.TokenPurposeUnsubscribe)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		call          func(svc *SubscriptionService) error
+		setupMocks    func(repo *mocks.MockSubscriptionRepository)
+		verifyMocks   func(t *testing.T, repo *mocks.MockSubscriptionRepository)
+		expectedError error
+	}{
+		{
+			name: "confirm with signed token",
+			call: func(svc *SubscriptionService) error { return svc.Confirm(ctx, confirmToken) },
+			setupMocks: func(repo *mocks.MockSubscriptionRepository) {
+				repo.On("GetSubscriptionByID", mock.Anything, sub.ID).Return(sub, nil)
+				confirmed := sub
+				confirmed.IsConfirmed = true
+				repo.On("UpdateSubscription", mock.Anything, confirmed).Return(nil)
+			},
+			verifyMocks: func(t *testing.T, repo *mocks.MockSubscriptionRepository) {
+				repo.AssertExpectations(t)
+				repo.AssertNotCalled(t, "IsTokenExists", mock.Anything, mock.Anything)
+			},
+		},
+		{
+			name:       "confirm with unsubscribe token is rejected",
+			call:       func(svc *SubscriptionService) error { return svc.Confirm(ctx, unsubscribeToken) },
+			setupMocks: func(repo *mocks.MockSubscriptionRepository) {},
+			verifyMocks: func(t *testing.T, repo *mocks.MockSubscriptionRepository) {
+				repo.AssertNotCalled(t, "GetSubscriptionByID", mock.Anything, mock.Anything)
+				repo.AssertNotCalled(t, "UpdateSubscription", mock.Anything, mock.Anything)
+			},
+			expectedError: domain.ErrInvalidToken,
+		},
+		{
+			name: "unsubscribe with signed token",
+			call: func(svc *SubscriptionService) error { return svc.Unsubscribe(ctx, unsubscribeToken) },
+			setupMocks: func(repo *mocks.MockSubscriptionRepository) {
+				repo.On("GetSubscriptionByID", mock.Anything, sub.ID).Return(sub, nil)
+				repo.On("DeleteSubscription", mock.Anything, sub.Token).Return(nil)
+			},
+			verifyMocks: func(t *testing.T, repo *mocks.MockSubscriptionRepository) {
+				repo.AssertExpectations(t)
+			},
+		},
+		{
+			name: "unsubscribe with signed token for deleted subscription",
+			call: func(svc *SubscriptionService) error { return svc.Unsubscribe(ctx, unsubscribeToken) },
+			setupMocks: func(repo *mocks.MockSubscriptionRepository) {
+				repo.On("GetSubscriptionByID", mock.Anything, sub.ID).Return(domain.Subscription{}, domain.ErrSubscriptionNotFound)
+			},
+			verifyMocks: func(t *testing.T, repo *mocks.MockSubscriptionRepository) {
+				repo.AssertExpectations(t)
+				repo.AssertNotCalled(t, "DeleteSubscription", mock.Anything, mock.Anything)
+			},
+			expectedError: domain.ErrTokenNotFound,
+		},
+		{
+			name: "legacy token is still accepted",
+			call: func(svc *SubscriptionService) error { return svc.Unsubscribe(ctx, sub.Token) },
+			setupMocks: func(repo *mocks.MockSubscriptionRepository) {
+				repo.On("IsTokenExists", mock.Anything, sub.Token).Return(true, nil)
+				repo.On("DeleteSubscription", mock.Anything, sub.Token).Return(nil)
+			},
+			verifyMocks: func(t *testing.T, repo *mocks.MockSubscriptionRepository) {
+				repo.AssertExpectations(t)
+				repo.AssertNotCalled(t, "GetSubscriptionByID", mock.Anything, mock.Anything)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.MockSubscriptionRepository{}
+			service := NewSubscriptionService(repo, &mocks.MockWeatherService{}, &mocks.MockEmailService{}, &mocks.MockTokenService{}, SubscriptionOptions{Signer: signer}, testLogger)
+
+			tt.setupMocks(repo)
+
+			err := tt.call(service)
+
+			assert.Equal(t, tt.expectedError, err)
+			tt.verifyMocks(t, repo)
+		})
+	}
+}
+
+func TestSubscriptionService_ResendConfirmation(t *testing.T) {
+	ctx := context.Background()
+	sub := domain.Subscription{ID: 42, Email: "user1@example.com", City: "Kyiv", Frequency: domain.FrequencyDaily, Token: "token123"}
+
+	tests := []struct {
+		name          string
+		setupMocks    func(repo *mocks.MockSubscriptionRepository, emailSvc *mocks.MockEmailService)
+		verifyMocks   func(t *testing.T, repo *mocks.MockSubscriptionRepository, emailSvc *mocks.MockEmailService)
+		expectedError error
+	}{
+		{
+			name: "success",
+			setupMocks: func(repo *mocks.MockSubscriptionRepository, emailSvc *mocks.MockEmailService) {
+				repo.On("GetSubscriptionByID", mock.Anything, sub.ID).Return(sub, nil)
+				subject, body := util.BuildConfirmationEmail(sub.City, sub.Token)
+				emailSvc.On("SendEmail", sub.Email, subject, body).Return(nil)
+			},
+			verifyMocks: func(t *testing.T, repo *mocks.MockSubscriptionRepository, emailSvc *mocks.MockEmailService) {
+				repo.AssertExpectations(t)
+				emailSvc.AssertExpectations(t)
+			},
+		},
+		{
+			name: "already confirmed",
+			setupMocks: func(repo *mocks.MockSubscriptionRepository, emailSvc *mocks.MockEmailService) {
+				confirmed := sub
+				confirmed.IsConfirmed = true
+				repo.On("GetSubscriptionByID", mock.Anything, sub.ID).Return(confirmed, nil)
+			},
+			verifyMocks: func(t *testing.T, repo *mocks.MockSubscriptionRepository, emailSvc *mocks.MockEmailService) {
+				emailSvc.AssertNotCalled(t, "SendEmail", mock.Anything, mock.Anything, mock.Anything)
+			},
+			expectedError: domain.ErrAlreadyConfirmed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.MockSubscriptionRepository{}
+			emailSvc := &mocks.MockEmailService{}
+			service := NewSubscriptionService(repo, &mocks.MockWeatherService{}, emailSvc, &mocks.MockTokenService{}, SubscriptionOptions{}, testLogger)
+
+			tt.setupMocks(repo, emailSvc)
+
+			err := service.ResendConfirmation(ctx, sub.ID)
+
+			assert.Equal(t, tt.expectedError, err)
+			tt.verifyMocks(t, repo, emailSvc)
+		})
+	}
+}
+
+func TestSubscriptionService_LogsRedactEmailAndToken(t *testing.T) {
+	ctx := util.WithRequestID(context.Background(), "req-1")
+	email := "user1@example.com"
+	token := "leLuPPmedUXI0bGYddfsOZEO_KaFthyJHWsb9lWfsdo="
+
+	var buf bytes.Buffer
+	logger, err := util.NewLogger(&buf, "json", "debug")
+	assert.NoError(t, err)
+
+	repo := &mocks.MockSubscriptionRepository{}
+	weatherSvc := &mocks.MockWeatherService{}
+	emailSvc := &mocks.MockEmailService{}
+	tokenSvc := &mocks.MockTokenService{}
+	repo.On("IsEmailSubscribed", mock.Anything, email).Return(false, nil)
+	weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{}, nil)
+	tokenSvc.On("GenerateToken").Return(token, nil)
+	repo.On("CreateSubscription", mock.Anything, mock.Anything).Return(7, nil)
+	emailSvc.On("SendEmail", email, mock.Anything, mock.Anything).Return(nil)
+	repo.On("IsTokenExists", mock.Anything, token).Return(false, nil)
+
+	service := NewSubscriptionService(repo, weatherSvc, emailSvc, tokenSvc, SubscriptionOptions{}, logger)
+	_, err = service.Subscribe(ctx, email, "Kyiv", domain.FrequencyDaily)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.ErrTokenNotFound, service.Confirm(ctx, token))
+
+	logs := buf.String()
+	assert.NotContains(t, logs, email)
+	assert.NotContains(t, logs, token)
+	assert.Contains(t, logs, `"email":"u***@example.com"`)
+	assert.Contains(t, logs, `"token":"`+util.HashToken(token)+`"`)
+	assert.Contains(t, logs, `"request_id":"req-1"`)
+	assert.Contains(t, logs, `"subscription_id":7`)
+}
+
+func TestSubscriptionService_Subscribe_RecordsSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	repo := &mocks.MockSubscriptionRepository{}
+	weatherSvc := &mocks.MockWeatherService{}
+	repo.On("IsEmailSubscribed", mock.Anything, "user1@example.com").Return(false, nil)
+	weatherSvc.On("GetWeather", mock.Anything, "Atlantis").Return(domain.Weather{}, domain.ErrCityNotFound)
+
+	service := NewSubscriptionService(repo, weatherSvc, &mocks.MockEmailService{}, &mocks.MockTokenService{}, SubscriptionOptions{}, testLogger)
+	_, err := service.Subscribe(context.Background(), "user1@example.com", "Atlantis", domain.FrequencyDaily)
+	assert.Equal(t, domain.ErrCityNotFound, err)
+
+	spans := recorder.Ended()
+	if assert.Len(t, spans, 1) {
+		assert.Equal(t, "SubscriptionService.Subscribe", spans[0].Name())
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+		assert.Contains(t, spans[0].Attributes(), attribute.String("city", "Atlantis"))
+	}
+}
+
+func TestSubscriptionService_Subscribe_Validation(t *testing.T) {
+	tests := []struct {
+		name           string
+		email          string
+		city           string
+		frequency      domain.Frequency
+		setupMX        func(mx *mocks.MockMXResolver)
+		expectedFields map[string]string
+		expectedEmail  string
+		expectedCity   string
+	}{
+		{
+			name:           "empty fields",
+			frequency:      "weekly",
+			expectedFields: map[string]string{"email": "is required", "city": "is required", "frequency": "must be hourly or daily"},
+		},
+		{
+			name:           "malformed email",
+			email:          "user1@",
+			city:           "Kyiv",
+			frequency:      domain.FrequencyDaily,
+			expectedFields: map[string]string{"email": "must be a valid email address"},
+		},
+		{
+			name:           "display name is rejected",
+			email:          "User <user1@example.com>",
+			city:           "Kyiv",
+			frequency:      domain.FrequencyDaily,
+			expectedFields: map[string]string{"email": "must be a valid email address"},
+		},
+		{
+			name:           "city too long",
+			email:          "user1@example.com",
+			city:           strings.Repeat("a", 101),
+			frequency:      domain.FrequencyDaily,
+			expectedFields: map[string]string{"city": "is too long"},
+		},
+		{
+			name:      "domain without mail server",
+			email:     "user1@example.invalid",
+			city:      "Kyiv",
+			frequency: domain.FrequencyDaily,
+			setupMX: func(mx *mocks.MockMXResolver) {
+				mx.On("AcceptsMail", mock.Anything, "example.invalid").Return(false, nil)
+			},
+			expectedFields: map[string]string{"email": "domain does not accept email"},
+		},
+		{
+			name:      "normalizes email and city",
+			email:     "  User1@Bücher.Example ",
+			city:      "  New   York ",
+			frequency: domain.FrequencyDaily,
+			setupMX: func(mx *mocks.MockMXResolver) {
+				mx.On("AcceptsMail", mock.Anything, "xn--bcher-kva.example").Return(false, errors.New("timeout"))
+			},
+			expectedEmail: "user1@xn--bcher-kva.example",
+			expectedCity:  "New York",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.MockSubscriptionRepository{}
+			mx := &mocks.MockMXResolver{}
+			if tt.setupMX != nil {
+				tt.setupMX(mx)
+			}
+			weatherSvc := &mocks.MockWeatherService{}
+			if tt.expectedEmail != "" {
+				repo.On("IsEmailSubscribed", mock.Anything, tt.expectedEmail).Return(false, nil)
+				weatherSvc.On("GetWeather", mock.Anything, tt.expectedCity).Return(domain.Weather{}, domain.ErrCityNotFound)
+			}
+			service := NewSubscriptionService(repo, weatherSvc, &mocks.MockEmailService{}, &mocks.MockTokenService{}, SubscriptionOptions{MX: mx}, testLogger)
+
+			_, err := service.Subscribe(context.Background(), tt.email, tt.city, tt.frequency)
+
+			if tt.expectedFields != nil {
+				var validation *domain.ValidationError
+				assert.ErrorIs(t, err, domain.ErrInvalidInput)
+				if assert.ErrorAs(t, err, &validation) {
+					assert.Equal(t, tt.expectedFields, validation.Fields)
+				}
+				repo.AssertNotCalled(t, "IsEmailSubscribed", mock.Anything, mock.Anything)
+			} else {
+				assert.Equal(t, domain.ErrCityNotFound, err)
+			}
+			repo.AssertExpectations(t)
+			weatherSvc.AssertExpectations(t)
+			mx.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSubscriptionService_Subscribe_RateLimitedPerEmail(t *testing.T) {
+	repo := &mocks.MockSubscriptionRepository{}
+	store := &mocks.MockRateLimitStore{}
+	limit := domain.RateLimit{Limit: 5, Period: 24 * time.Hour}
+	store.On("Take", mock.Anything, "subscribe_email:user1@example.com", limit).Return(domain.RateLimitResult{Limit: 5, RetryAfter: time.Hour}, nil)
+	limiter := NewRateLimiter(store, map[string]domain.RateLimit{RateLimitSubscribeEmail: limit}, testLogger)
+
+	service := NewSubscriptionService(repo, &mocks.MockWeatherService{}, &mocks.MockEmailService{}, &mocks.MockTokenService{}, SubscriptionOptions{Limiter: limiter}, testLogger)
+	_, err := service.Subscribe(context.Background(), "User1@Example.com", "Kyiv", domain.FrequencyDaily)
+
+	assert.ErrorIs(t, err, domain.ErrRateLimited)
+	assert.Equal(t, time.Hour, domain.RetryAfter(err))
+	repo.AssertNotCalled(t, "IsEmailSubscribed", mock.Anything, mock.Anything)
+	store.AssertExpectations(t)
+}
+
+func TestSubscriptionService_Create_Channels(t *testing.T) {
+	slack := &mocks.MockSlackSender{}
+	slack.On("AcceptsWebhookURL", "https://hooks.slack.com/services/T000/B000/XXX").Return(true)
+	slack.On("AcceptsWebhookURL", "https://example.com/services/T000/B000/XXX").Return(false)
+
+	tests := []struct {
+		name           string
+		channel        domain.Channel
+		webhookURL     string
+		expectedFields map[string]string
+		expected       domain.Subscription
+	}{
+		{
+			name:       "slack posts to incoming webhook",
+			channel:    domain.ChannelSlack,
+			webhookURL: " https://hooks.slack.com/services/T000/B000/XXX ",
+			expected:   domain.Subscription{Channel: domain.ChannelSlack, Address: "https://hooks.slack.com/services/T000/B000/XXX"},
+		},
+		{
+			name:           "slack rejects other URL",
+			channel:        domain.ChannelSlack,
+			webhookURL:     "https://example.com/services/T000/B000/XXX",
+			expectedFields: map[string]string{"webhook_url": "must be a Slack incoming webhook URL"},
+		},
+		{
+			name:           "webhook rejects private address",
+			channel:        domain.ChannelWebhook,
+			webhookURL:     "http://169.254.169.254/latest/meta-data",
+			expectedFields: map[string]string{"webhook_url": "must point to a public address"},
+		},
+		{
+			name:           "webhook rejects localhost",
+			channel:        domain.ChannelWebhook,
+			webhookURL:     "http://LocalHost.:8080/hooks",
+			expectedFields: map[string]string{"webhook_url": "must point to a public address"},
+		},
+		{
+			name:           "webhook rejects loopback IPv6",
+			channel:        domain.ChannelWebhook,
+			webhookURL:     "http://[::1]/hooks",
+			expectedFields: map[string]string{"webhook_url": "must point to a public address"},
+		},
+		{
+			name:           "slack requires URL",
+			channel:        domain.ChannelSlack,
+			expectedFields: map[string]string{"webhook_url": "is required"},
+		},
+		{
+			name:           "telegram is left to the bot",
+			channel:        domain.ChannelTelegram,
+			expectedFields: map[string]string{"channel": "telegram subscriptions are created with the Telegram bot"},
+		},
+		{
+			name:           "unknown channel",
+			channel:        "sms",
+			expectedFields: map[string]string{"channel": "must be email, webhook or slack"},
+		},
+		{
+			name:           "email takes no URL",
+			channel:        domain.ChannelEmail,
+			webhookURL:     "https://hooks.slack.com/services/T000/B000/XXX",
+			expectedFields: map[string]string{"webhook_url": "is only used by the webhook and slack channels"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.MockSubscriptionRepository{}
+			weatherSvc := &mocks.MockWeatherService{}
+			tokenSvc := &mocks.MockTokenService{}
+			emailSvc := &mocks.MockEmailService{}
+			if tt.expectedFields == nil {
+				repo.On("IsEmailSubscribed", mock.Anything, "user1@example.com").Return(false, nil)
+				weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{}, nil)
+				tokenSvc.On("GenerateToken").Return("token123", nil)
+				repo.On("CreateSubscription", mock.Anything, mock.MatchedBy(func(sub domain.Subscription) bool {
+					return sub.Channel == tt.expected.Channel && sub.Address == tt.expected.Address && sub.Webhook == nil
+				})).Return(7, nil)
+				emailSvc.On("SendEmail", "user1@example.com", mock.Anything, mock.Anything).Return(nil)
+			}
+			service := NewSubscriptionService(repo, weatherSvc, emailSvc, tokenSvc, SubscriptionOptions{Slack: slack}, testLogger)
+
+			sub, err := service.Create(context.Background(), "user1@example.com", "Kyiv", domain.FrequencyDaily, tt.channel, tt.webhookURL)
+
+			if tt.expectedFields != nil {
+				var validation *domain.ValidationError
+				if assert.ErrorAs(t, err, &validation) {
+					assert.Equal(t, tt.expectedFields, validation.Fields)
+				}
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.Address, sub.Address)
+			}
+			repo.AssertExpectations(t)
+			emailSvc.AssertExpectations(t)
+		})
+	}
+}
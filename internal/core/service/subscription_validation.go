@@ -0,0 +1,160 @@
+package service
+
+import (
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/port"
+	"weather-api/internal/util"
+
+	"golang.org/x/net/idna"
+)
+
+const (
+	maxEmailLength = 254
+	maxCityLength  = 100
+
+	maxWebhookURLLength = 2048
+)
+
+// normalizeEmail checks that email is a single RFC 5322 address without a display name and
+// returns it trimmed, lowercased and with the domain in its ASCII (punycode) form, or the
+// reason it was rejected.
+func normalizeEmail(email string) (string, string) {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return "", "is required"
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Name != "" || addr.Address != email {
+		return "", "must be a valid email address"
+	}
+
+	at := strings.LastIndexByte(addr.Address, '@')
+	local, host := addr.Address[:at], addr.Address[at+1:]
+	host, err = idna.Lookup.ToASCII(host)
+	if err != nil || !strings.Contains(host, ".") {
+		return "", "must have a valid domain"
+	}
+
+	email = strings.ToLower(local + "@" + host)
+	if len(email) > maxEmailLength {
+		return "", "is too long"
+	}
+	return email, ""
+}
+
+// normalizeCity trims city and collapses inner whitespace, or returns the reason it was rejected.
+func normalizeCity(city string) (string, string) {
+	city = strings.Join(strings.Fields(city), " ")
+	switch {
+	case city == "":
+		return "", "is required"
+	case utf8.RuneCountInString(city) > maxCityLength:
+		return "", "is too long"
+	case strings.ContainsFunc(city, unicode.IsControl):
+		return "", "contains invalid characters"
+	}
+	return city, ""
+}
+
+// validateSubscription normalizes the subscription fields or returns a *domain.ValidationError
+// listing every invalid one.
+func validateSubscription(email, city string, frequency domain.Frequency) (string, string, error) {
+	fields := map[string]string{}
+	email, problem := normalizeEmail(email)
+	if problem != "" {
+		fields["email"] = problem
+	}
+	city, problem = normalizeCity(city)
+	if problem != "" {
+		fields["city"] = problem
+	}
+	if frequency != domain.FrequencyDaily && frequency != domain.FrequencyHourly {
+		fields["frequency"] = "must be hourly or daily"
+	}
+	if len(fields) > 0 {
+		return "", "", &domain.ValidationError{Fields: fields}
+	}
+	return email, city, nil
+}
+
+// validateChatSubscription is validateSubscription for chats, which have no email address.
+func validateChatSubscription(city string, frequency domain.Frequency) (string, error) {
+	fields := map[string]string{}
+	city, problem := normalizeCity(city)
+	if problem != "" {
+		fields["city"] = problem
+	}
+	if frequency != domain.FrequencyDaily && frequency != domain.FrequencyHourly {
+		fields["frequency"] = "must be hourly or daily"
+	}
+	if len(fields) > 0 {
+		return "", &domain.ValidationError{Fields: fields}
+	}
+	return city, nil
+}
+
+// normalizeWebhookURL checks that raw is an absolute http or https URL without credentials and
+// returns it without fragment, or the reason it was rejected. Hosts that are plainly internal,
+// localhost or a non-public IP, are rejected here; hostnames are checked again at every
+// delivery, once they are resolved.
+func normalizeWebhookURL(raw string) (string, string) {
+	raw = strings.TrimSpace(raw)
+	switch {
+	case raw == "":
+		return "", "is required"
+	case len(raw) > maxWebhookURLLength:
+		return "", "is too long"
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", "must be an http or https URL"
+	}
+	if u.User != nil {
+		return "", "must not contain credentials"
+	}
+	if internalHost(u.Hostname()) {
+		return "", "must point to a public address"
+	}
+	u.Fragment = ""
+	return u.String(), ""
+}
+
+func internalHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	addr, err := netip.ParseAddr(host)
+	return err == nil && !util.PublicAddr(addr)
+}
+
+// validateChannel checks the channel of a new subscription, email if empty, and the URL that
+// the webhook and Slack channels post to. Slack URLs must be accepted by slack, if set.
+// Telegram subscriptions are only created by the bot, see SubscribeChat.
+func validateChannel(channel domain.Channel, webhookURL string, slack port.SlackSender) (domain.Channel, string, error) {
+	switch channel {
+	case "", domain.ChannelEmail:
+		if strings.TrimSpace(webhookURL) != "" {
+			return "", "", &domain.ValidationError{Fields: map[string]string{"webhook_url": "is only used by the webhook and slack channels"}}
+		}
+		return domain.ChannelEmail, "", nil
+	case domain.ChannelWebhook, domain.ChannelSlack:
+		webhookURL, problem := normalizeWebhookURL(webhookURL)
+		if problem == "" && channel == domain.ChannelSlack && slack != nil && !slack.AcceptsWebhookURL(webhookURL) {
+			problem = "must be a Slack incoming webhook URL"
+		}
+		if problem != "" {
+			return "", "", &domain.ValidationError{Fields: map[string]string{"webhook_url": problem}}
+		}
+		return channel, webhookURL, nil
+	case domain.ChannelTelegram:
+		return "", "", &domain.ValidationError{Fields: map[string]string{"channel": "telegram subscriptions are created with the Telegram bot"}}
+	}
+	return "", "", &domain.ValidationError{Fields: map[string]string{"channel": "must be email, webhook or slack"}}
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"slices"
+	"strings"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/port"
+)
+
+const (
+	telegramHelp = "Send /subscribe &lt;city&gt; &lt;hourly|daily&gt; to get weather updates in this chat, " +
+		"e.g. /subscribe Kyiv daily. Send it again to change them, or /unsubscribe to stop them."
+	telegramFailed = "Something went wrong, please try again later."
+)
+
+// TelegramBot answers the commands sent to the bot: /subscribe <city> <frequency>, /unsubscribe,
+// and /start or /help. Replies are sent through the Bot API.
+type TelegramBot struct {
+	subscriptions *SubscriptionService
+	sender        port.TelegramSender
+	logger        *slog.Logger
+}
+
+func NewTelegramBot(subscriptions *SubscriptionService, sender port.TelegramSender, logger *slog.Logger) *TelegramBot {
+	return &TelegramBot{subscriptions: subscriptions, sender: sender, logger: logger}
+}
+
+// HandleMessage runs the command in text, a message to chatID, and replies to it. It only fails
+// when the reply could not be sent.
+func (b *TelegramBot) HandleMessage(ctx context.Context, chatID, text string) error {
+	ctx, span := tracer.Start(ctx, "TelegramBot.HandleMessage")
+	defer span.End()
+
+	args := strings.Fields(text)
+	command := ""
+	if len(args) > 0 {
+		// In groups commands may be addressed to a bot, as in /subscribe@WeatherBot.
+		command, _, _ = strings.Cut(strings.ToLower(args[0]), "@")
+		args = args[1:]
+	}
+
+	var reply string
+	switch command {
+	case "/subscribe":
+		reply = b.subscribe(ctx, chatID, args)
+	case "/unsubscribe":
+		reply = b.unsubscribe(ctx, chatID)
+	default:
+		reply = telegramHelp
+	}
+	if err := b.sender.SendMessage(ctx, chatID, reply); err != nil {
+		b.logger.ErrorContext(ctx, "Failed to reply to Telegram command", "command", command, "error", err)
+		return err
+	}
+	return nil
+}
+
+func (b *TelegramBot) subscribe(ctx context.Context, chatID string, args []string) string {
+	if len(args) < 2 {
+		return telegramHelp
+	}
+	city := strings.Join(args[:len(args)-1], " ")
+	frequency := domain.Frequency(strings.ToLower(args[len(args)-1]))
+
+	sub, err := b.subscriptions.SubscribeChat(ctx, chatID, city, frequency)
+	var validation *domain.ValidationError
+	switch {
+	case err == nil:
+		return fmt.Sprintf("Subscribed to %s weather updates for %s.", sub.Frequency, html.EscapeString(sub.City))
+	case errors.As(err, &validation):
+		var problems []string
+		for field, problem := range validation.Fields {
+			problems = append(problems, field+" "+problem)
+		}
+		slices.Sort(problems)
+		return html.EscapeString("Invalid "+strings.Join(problems, ", ")+".") + "\n" + telegramHelp
+	case errors.Is(err, domain.ErrCityNotFound):
+		return fmt.Sprintf("Couldn't find the city %s.", html.EscapeString(city))
+	case errors.Is(err, domain.ErrProviderUnavailable), errors.Is(err, domain.ErrQuotaExhausted):
+		return "The weather service is unavailable right now, please try again later."
+	}
+	b.logger.ErrorContext(ctx, "Failed to subscribe Telegram chat", "error", err)
+	return telegramFailed
+}
+
+func (b *TelegramBot) unsubscribe(ctx context.Context, chatID string) string {
+	err := b.subscriptions.UnsubscribeChat(ctx, chatID)
+	switch {
+	case err == nil:
+		return "Unsubscribed. Send /subscribe to get updates again."
+	case errors.Is(err, domain.ErrSubscriptionNotFound):
+		return "This chat has no subscription."
+	}
+	b.logger.ErrorContext(ctx, "Failed to unsubscribe Telegram chat", "error", err)
+	return telegramFailed
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTelegramBot_HandleMessage(t *testing.T) {
+	ctx := context.Background()
+	chat := domain.Subscription{ID: 3, City: "Kyiv", Frequency: domain.FrequencyDaily, Token: "token123", IsConfirmed: true, Version: 1, Channel: domain.ChannelTelegram, Address: "42"}
+
+	tests := []struct {
+		name          string
+		text          string
+		setupMocks    func(repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService, tokenSvc *mocks.MockTokenService)
+		expectedReply string
+	}{
+		{
+			name: "subscribes chat",
+			text: "/subscribe Kyiv daily",
+			setupMocks: func(repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService, tokenSvc *mocks.MockTokenService) {
+				weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{}, nil)
+				repo.On("GetSubscriptionByAddress", mock.Anything, domain.ChannelTelegram, "42").Return(domain.Subscription{}, domain.ErrSubscriptionNotFound)
+				tokenSvc.On("GenerateToken").Return("token123", nil)
+				repo.On("CreateSubscription", mock.Anything, domain.Subscription{
+					City:        "Kyiv",
+					Frequency:   domain.FrequencyDaily,
+					Token:       "token123",
+					IsConfirmed: true,
+					Channel:     domain.ChannelTelegram,
+					Address:     "42",
+				}).Return(3, nil)
+			},
+			expectedReply: "Subscribed to daily weather updates for Kyiv.",
+		},
+		{
+			name: "changes subscription of chat",
+			text: "/subscribe@WeatherBot New York HOURLY",
+			setupMocks: func(repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService, tokenSvc *mocks.MockTokenService) {
+				weatherSvc.On("GetWeather", mock.Anything, "New York").Return(domain.Weather{}, nil)
+				repo.On("GetSubscriptionByAddress", mock.Anything, domain.ChannelTelegram, "42").Return(chat, nil)
+				changed := chat
+				changed.City, changed.Frequency = "New York", domain.FrequencyHourly
+				updated := changed
+				updated.Version = 2
+				repo.On("UpdateSubscriptionByID", mock.Anything, changed).Return(updated, nil)
+			},
+			expectedReply: "Subscribed to hourly weather updates for New York.",
+		},
+		{
+			name: "shows usage without frequency",
+			text: "/subscribe Kyiv",
+			setupMocks: func(repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService, tokenSvc *mocks.MockTokenService) {
+			},
+			expectedReply: telegramHelp,
+		},
+		{
+			name: "rejects unknown frequency",
+			text: "/subscribe Kyiv weekly",
+			setupMocks: func(repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService, tokenSvc *mocks.MockTokenService) {
+			},
+			expectedReply: "Invalid frequency must be hourly or daily.\n" + telegramHelp,
+		},
+		{
+			name: "reports unknown city",
+			text: "/subscribe <Atlantis> daily",
+			setupMocks: func(repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService, tokenSvc *mocks.MockTokenService) {
+				weatherSvc.On("GetWeather", mock.Anything, "<Atlantis>").Return(domain.Weather{}, domain.ErrCityNotFound)
+			},
+			expectedReply: "Couldn't find the city &lt;Atlantis&gt;.",
+		},
+		{
+			name: "hides internal errors",
+			text: "/subscribe Kyiv daily",
+			setupMocks: func(repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService, tokenSvc *mocks.MockTokenService) {
+				weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{}, nil)
+				repo.On("GetSubscriptionByAddress", mock.Anything, domain.ChannelTelegram, "42").Return(domain.Subscription{}, errors.New("connection refused"))
+			},
+			expectedReply: telegramFailed,
+		},
+		{
+			name: "unsubscribes chat",
+			text: "/unsubscribe",
+			setupMocks: func(repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService, tokenSvc *mocks.MockTokenService) {
+				repo.On("GetSubscriptionByAddress", mock.Anything, domain.ChannelTelegram, "42").Return(chat, nil)
+				repo.On("DeleteSubscriptionByID", mock.Anything, 3, 0).Return(nil)
+			},
+			expectedReply: "Unsubscribed. Send /subscribe to get updates again.",
+		},
+		{
+			name: "unsubscribes chat without subscription",
+			text: "/unsubscribe",
+			setupMocks: func(repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService, tokenSvc *mocks.MockTokenService) {
+				repo.On("GetSubscriptionByAddress", mock.Anything, domain.ChannelTelegram, "42").Return(domain.Subscription{}, domain.ErrSubscriptionNotFound)
+			},
+			expectedReply: "This chat has no subscription.",
+		},
+		{
+			name: "answers anything else with help",
+			text: "hello",
+			setupMocks: func(repo *mocks.MockSubscriptionRepository, weatherSvc *mocks.MockWeatherService, tokenSvc *mocks.MockTokenService) {
+			},
+			expectedReply: telegramHelp,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mocks.MockSubscriptionRepository{}
+			weatherSvc := &mocks.MockWeatherService{}
+			tokenSvc := &mocks.MockTokenService{}
+			sender := &mocks.MockTelegramSender{}
+			tt.setupMocks(repo, weatherSvc, tokenSvc)
+			sender.On("SendMessage", mock.Anything, "42", tt.expectedReply).Return(nil)
+			subscriptions := NewSubscriptionService(repo, weatherSvc, &mocks.MockEmailService{}, tokenSvc, SubscriptionOptions{}, testLogger)
+
+			err := NewTelegramBot(subscriptions, sender, testLogger).HandleMessage(ctx, "42", tt.text)
+
+			assert.NoError(t, err)
+			repo.AssertExpectations(t)
+			sender.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTelegramBot_HandleMessageReturnsReplyError(t *testing.T) {
+	sender := &mocks.MockTelegramSender{}
+	sendErr := errors.New("telegram sendMessage responded with 403: Forbidden: bot was blocked by the user")
+	sender.On("SendMessage", mock.Anything, "42", telegramHelp).Return(sendErr)
+	subscriptions := NewSubscriptionService(&mocks.MockSubscriptionRepository{}, &mocks.MockWeatherService{}, &mocks.MockEmailService{}, &mocks.MockTokenService{}, SubscriptionOptions{}, testLogger)
+
+	err := NewTelegramBot(subscriptions, sender, testLogger).HandleMessage(context.Background(), "42", "/start")
+
+	assert.Equal(t, sendErr, err)
+}
@@ -0,0 +1,20 @@
+package service
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("weather-api/internal/core/service")
+
+// endSpan records err on the span, if any, and ends it. Use it with a named error result:
+//
+//	defer func() { endSpan(span, err) }()
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
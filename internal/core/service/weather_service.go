@@ -1,22 +1,70 @@
 package service
 
 import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
 	"weather-api/internal/core/domain"
 	"weather-api/internal/core/port"
 )
 
 type WeatherService struct {
-	weatherSvc port.WeatherService
+	weatherSvc       port.WeatherService
+	maxBatch         int
+	batchConcurrency int
 }
 
-func NewWeatherService(weatherSvc port.WeatherService) *WeatherService {
-	return &WeatherService{weatherSvc: weatherSvc}
+// NewWeatherService creates the service. Batches are limited to maxBatch distinct cities, of
+// which at most batchConcurrency are looked up at the same time.
+func NewWeatherService(weatherSvc port.WeatherService, maxBatch, batchConcurrency int) *WeatherService {
+	return &WeatherService{weatherSvc: weatherSvc, maxBatch: maxBatch, batchConcurrency: max(batchConcurrency, 1)}
 }
 
-func (s *WeatherService) GetWeather(city string) (domain.Weather, error) {
-	weather, err := s.weatherSvc.GetWeather(city)
+func (s *WeatherService) GetWeather(ctx context.Context, city string) (domain.Weather, error) {
+	weather, err := s.weatherSvc.GetWeather(ctx, city)
 	if err != nil {
 		return domain.Weather{}, err
 	}
 	return weather, nil
 }
+
+// GetWeatherBatch looks up the weather of several cities concurrently. Cities are trimmed and
+// repeated ones dropped; the results keep the order of the first occurrences. A failed lookup
+// only fails its own result.
+func (s *WeatherService) GetWeatherBatch(ctx context.Context, cities []string) ([]domain.CityWeather, error) {
+	var unique []string
+	seen := map[string]bool{}
+	for _, city := range cities {
+		city = strings.TrimSpace(city)
+		if city == "" {
+			return nil, &domain.ValidationError{Fields: map[string]string{"cities": "must not contain empty names"}}
+		}
+		if key := strings.ToLower(city); !seen[key] {
+			seen[key] = true
+			unique = append(unique, city)
+		}
+	}
+	if len(unique) == 0 {
+		return nil, &domain.ValidationError{Fields: map[string]string{"cities": "is required"}}
+	}
+	if len(unique) > s.maxBatch {
+		return nil, &domain.ValidationError{Fields: map[string]string{"cities": fmt.Sprintf("must not list more than %d cities", s.maxBatch)}}
+	}
+
+	results := make([]domain.CityWeather, len(unique))
+	slots := make(chan struct{}, s.batchConcurrency)
+	var wg sync.WaitGroup
+	for i, city := range unique {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			weather, err := s.GetWeather(ctx, city)
+			results[i] = domain.CityWeather{City: city, Weather: weather, Err: err}
+		}()
+	}
+	wg.Wait()
+	return results, nil
+}
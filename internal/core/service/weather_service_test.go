@@ -1,16 +1,22 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"sync/atomic"
 	"testing"
+	"time"
 	"weather-api/internal/mocks"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 	"weather-api/internal/core/domain"
 )
 
 func TestWeatherService_GetWeather(t *testing.T) {
+	ctx := context.Background()
+
 	tests := []struct {
 		name          string
 		city          string
@@ -28,7 +34,7 @@ func TestWeatherService_GetWeather(t *testing.T) {
 					Humidity:    60,
 					Description: "Sunny",
 				}
-				weatherSvc.On("GetWeather", "Kyiv").Return(weather, nil)
+				weatherSvc.On("GetWeather", ctx, "Kyiv").Return(weather, nil)
 			},
 			verifyMocks: func(t *testing.T, weatherSvc *mocks.MockWeatherService) {
 				weatherSvc.AssertExpectations(t)
@@ -44,7 +50,7 @@ func TestWeatherService_GetWeather(t *testing.T) {
 			name: "error from weather service",
 			city: "InvalidCity",
 			setupMocks: func(weatherSvc *mocks.MockWeatherService) {
-				weatherSvc.On("GetWeather", "InvalidCity").Return(domain.Weather{}, errors.New("API error"))
+				weatherSvc.On("GetWeather", ctx, "InvalidCity").Return(domain.Weather{}, errors.New("API error"))
 			},
 			verifyMocks: func(t *testing.T, weatherSvc *mocks.MockWeatherService) {
 				weatherSvc.AssertExpectations(t)
@@ -57,11 +63,11 @@ func TestWeatherService_GetWeather(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			weatherSvc := &mocks.MockWeatherService{}
-			service := NewWeatherService(weatherSvc)
+			service := NewWeatherService(weatherSvc, 3, 2)
 
 			tt.setupMocks(weatherSvc)
 
-			result, err := service.GetWeather(tt.city)
+			result, err := service.GetWeather(ctx, tt.city)
 
 			assert.Equal(t, tt.expected, result, "Weather result should match expected")
 			assert.Equal(t, tt.expectedError, err, "Error should match expected")
@@ -69,3 +75,99 @@ func TestWeatherService_GetWeather(t *testing.T) {
 		})
 	}
 }
+
+func TestWeatherService_GetWeatherBatch(t *testing.T) {
+	ctx := context.Background()
+	sunny := domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny"}
+
+	tests := []struct {
+		name          string
+		cities        []string
+		setupMocks    func(weatherSvc *mocks.MockWeatherService)
+		expected      []domain.CityWeather
+		expectedError error
+	}{
+		{
+			name:   "partial failure",
+			cities: []string{"Kyiv", "Atlantis"},
+			setupMocks: func(weatherSvc *mocks.MockWeatherService) {
+				weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(sunny, nil)
+				weatherSvc.On("GetWeather", mock.Anything, "Atlantis").Return(domain.Weather{}, domain.ErrCityNotFound)
+			},
+			expected: []domain.CityWeather{
+				{City: "Kyiv", Weather: sunny},
+				{City: "Atlantis", Err: domain.ErrCityNotFound},
+			},
+		},
+		{
+			name:   "repeated cities are looked up once",
+			cities: []string{" Kyiv", "kyiv ", "Lviv", "KYIV"},
+			setupMocks: func(weatherSvc *mocks.MockWeatherService) {
+				weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(sunny, nil).Once()
+				weatherSvc.On("GetWeather", mock.Anything, "Lviv").Return(sunny, nil).Once()
+			},
+			expected: []domain.CityWeather{
+				{City: "Kyiv", Weather: sunny},
+				{City: "Lviv", Weather: sunny},
+			},
+		},
+		{
+			name:          "too many cities",
+			cities:        []string{"Kyiv", "Lviv", "Odesa", "Dnipro"},
+			setupMocks:    func(weatherSvc *mocks.MockWeatherService) {},
+			expectedError: domain.ErrInvalidInput,
+		},
+		{
+			name:          "empty city",
+			cities:        []string{"Kyiv", " "},
+			setupMocks:    func(weatherSvc *mocks.MockWeatherService) {},
+			expectedError: domain.ErrInvalidInput,
+		},
+		{
+			name:          "no cities",
+			setupMocks:    func(weatherSvc *mocks.MockWeatherService) {},
+			expectedError: domain.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			weatherSvc := &mocks.MockWeatherService{}
+			tt.setupMocks(weatherSvc)
+			service := NewWeatherService(weatherSvc, 3, 2)
+
+			results, err := service.GetWeatherBatch(ctx, tt.cities)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, results)
+			}
+			weatherSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWeatherService_GetWeatherBatch_LimitsConcurrency(t *testing.T) {
+	var running, peak atomic.Int32
+	weatherSvc := &mocks.MockWeatherService{}
+	weatherSvc.On("GetWeather", mock.Anything, mock.Anything).Return(domain.Weather{}, nil).Run(func(mock.Arguments) {
+		now := running.Add(1)
+		for {
+			old := peak.Load()
+			if now <= old || peak.CompareAndSwap(old, now) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		running.Add(-1)
+	})
+	service := NewWeatherService(weatherSvc, 10, 2)
+
+	results, err := service.GetWeatherBatch(context.Background(), []string{"a", "b", "c", "d", "e", "f"})
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 6)
+	assert.LessOrEqual(t, peak.Load(), int32(2))
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/port"
+)
+
+// WeatherStreamer feeds live weather streams. Each followed city is polled by a single goroutine
+// however many clients follow it: it looks the weather up again as soon as the cached lookup
+// expires, or every pollInterval when it wasn't cached, and pushes it whenever it was refreshed.
+// The poller stops when the last client of its city leaves.
+type WeatherStreamer struct {
+	weatherSvc   port.WeatherService
+	pollInterval time.Duration
+	maxStreams   int
+	logger       *slog.Logger
+	now          func() time.Time
+
+	mu      sync.Mutex
+	pollers map[string]*cityPoller
+	streams int
+	closed  chan struct{}
+}
+
+type cityPoller struct {
+	city        string
+	cancel      context.CancelFunc
+	latest      domain.CityWeather
+	subscribers map[chan domain.CityWeather]struct{}
+}
+
+func NewWeatherStreamer(weatherSvc port.WeatherService, pollInterval time.Duration, maxStreams int, logger *slog.Logger) *WeatherStreamer {
+	return &WeatherStreamer{
+		weatherSvc:   weatherSvc,
+		pollInterval: pollInterval,
+		maxStreams:   maxStreams,
+		logger:       logger,
+		now:          time.Now,
+		pollers:      map[string]*cityPoller{},
+		closed:       make(chan struct{}),
+	}
+}
+
+// Subscribe follows the weather of city until ctx is done. The returned channel first delivers
+// the current weather, then every refresh; a client that falls behind only misses intermediate
+// updates. The channel is closed once ctx is done or the streamer is closed. The city is looked
+// up before anything else, so that an unknown city fails the request rather than the stream.
+func (s *WeatherStreamer) Subscribe(ctx context.Context, city string) (<-chan domain.CityWeather, error) {
+	city = strings.TrimSpace(city)
+	if city == "" {
+		return nil, &domain.ValidationError{Fields: map[string]string{"city": "required"}}
+	}
+	weather, err := s.weatherSvc.GetWeather(ctx, city)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-s.closed:
+		return nil, domain.ErrTooManyStreams
+	default:
+	}
+	if s.streams >= s.maxStreams {
+		return nil, domain.ErrTooManyStreams
+	}
+
+	key := strings.ToLower(city)
+	p, ok := s.pollers[key]
+	if !ok {
+		pollCtx, cancel := context.WithCancel(context.Background())
+		p = &cityPoller{
+			city:        city,
+			cancel:      cancel,
+			latest:      domain.CityWeather{City: city, Weather: weather},
+			subscribers: map[chan domain.CityWeather]struct{}{},
+		}
+		s.pollers[key] = p
+		go s.poll(pollCtx, key, p)
+		s.logger.DebugContext(ctx, "Started polling weather for live streams", "city", city)
+	}
+
+	updates := make(chan domain.CityWeather, 1)
+	updates <- p.latest
+	p.subscribers[updates] = struct{}{}
+	s.streams++
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.unsubscribe(key, p, updates)
+		case <-s.closed:
+		}
+	}()
+	return updates, nil
+}
+
+// Close ends all streams and stops the pollers, e.g. on shutdown so that open streams don't hold
+// up the HTTP server.
+func (s *WeatherStreamer) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-s.closed:
+		return
+	default:
+	}
+	close(s.closed)
+	for key, p := range s.pollers {
+		p.cancel()
+		for updates := range p.subscribers {
+			close(updates)
+		}
+		delete(s.pollers, key)
+	}
+	s.streams = 0
+}
+
+func (s *WeatherStreamer) unsubscribe(key string, p *cityPoller, updates chan domain.CityWeather) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := p.subscribers[updates]; !ok {
+		return
+	}
+	delete(p.subscribers, updates)
+	close(updates)
+	s.streams--
+	if len(p.subscribers) == 0 {
+		p.cancel()
+		delete(s.pollers, key)
+		s.logger.Debug("Stopped polling weather for live streams", "city", p.city)
+	}
+}
+
+func (s *WeatherStreamer) poll(ctx context.Context, key string, p *cityPoller) {
+	s.mu.Lock()
+	last := p.latest
+	s.mu.Unlock()
+
+	timer := time.NewTimer(s.nextPoll(last))
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		weather, err := s.weatherSvc.GetWeather(ctx, p.city)
+		if ctx.Err() != nil {
+			return
+		}
+		update := domain.CityWeather{City: p.city, Weather: weather, Err: err}
+		if err != nil {
+			s.logger.WarnContext(ctx, "Failed to refresh weather for live streams", "city", p.city, "error", err)
+		}
+		if refreshed(last, update) {
+			s.broadcast(key, p, update)
+			last = update
+		}
+		timer.Reset(s.nextPoll(update))
+	}
+}
+
+// nextPoll waits for the cached lookup to expire, as looking up earlier would return the same
+// weather. Uncached or stale weather, and errors, are polled every pollInterval.
+func (s *WeatherStreamer) nextPoll(update domain.CityWeather) time.Duration {
+	if update.Err == nil {
+		if wait := update.Weather.ExpiresAt.Sub(s.now()); wait > 0 {
+			return wait
+		}
+	}
+	return s.pollInterval
+}
+
+func (s *WeatherStreamer) broadcast(key string, p *cityPoller, update domain.CityWeather) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pollers[key] != p {
+		return
+	}
+	p.latest = update
+	for updates := range p.subscribers {
+		// Only the poller sends, under the lock, so after dropping the update the client hasn't
+		// read yet there is room for this one.
+		select {
+		case updates <- update:
+		default:
+			select {
+			case <-updates:
+			default:
+			}
+			updates <- update
+		}
+	}
+}
+
+// refreshed tells whether update differs from what clients got last: other conditions, a new
+// lookup by the cache even if the conditions are the same, or a different error.
+func refreshed(last, update domain.CityWeather) bool {
+	if last.Err != nil || update.Err != nil {
+		return last.Err == nil || update.Err == nil || last.Err.Error() != update.Err.Error()
+	}
+	a, b := last.Weather, update.Weather
+	return a.Temperature != b.Temperature || a.Humidity != b.Humidity || a.Description != b.Description ||
+		!a.ObservedAt.Equal(b.ObservedAt) || !a.ExpiresAt.Equal(b.ExpiresAt)
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"weather-api/internal/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"weather-api/internal/core/domain"
+)
+
+func TestWeatherStreamer_SharesOnePollerPerCity(t *testing.T) {
+	sunny := domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny"}
+	rainy := domain.Weather{Temperature: 14, Humidity: 90, Description: "Rain"}
+	weatherSvc := &mocks.MockWeatherService{}
+	weatherSvc.On("GetWeather", mock.Anything, "kyiv").Return(sunny, nil).Once()
+	weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(sunny, nil).Once()
+	weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(rainy, nil)
+	streamer := NewWeatherStreamer(weatherSvc, 20*time.Millisecond, 10, testLogger)
+
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	first, err := streamer.Subscribe(ctx1, "Kyiv")
+	require.NoError(t, err)
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	second, err := streamer.Subscribe(ctx2, " kyiv ")
+	require.NoError(t, err)
+
+	streamer.mu.Lock()
+	assert.Len(t, streamer.pollers, 1)
+	assert.Equal(t, 2, streamer.streams)
+	streamer.mu.Unlock()
+
+	for _, updates := range []<-chan domain.CityWeather{first, second} {
+		assert.Equal(t, domain.CityWeather{City: "Kyiv", Weather: rainy}, receiveUntil(t, updates, rainy))
+	}
+
+	cancel1()
+	cancel2()
+	assertClosed(t, first)
+	assertClosed(t, second)
+	streamer.mu.Lock()
+	assert.Empty(t, streamer.pollers)
+	assert.Zero(t, streamer.streams)
+	streamer.mu.Unlock()
+}
+
+func TestWeatherStreamer_Subscribe(t *testing.T) {
+	tests := []struct {
+		name          string
+		city          string
+		open          int
+		setupMocks    func(weatherSvc *mocks.MockWeatherService)
+		expectedError error
+	}{
+		{
+			name: "success",
+			city: "Kyiv",
+			setupMocks: func(weatherSvc *mocks.MockWeatherService) {
+				weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{Description: "Sunny"}, nil)
+			},
+		},
+		{
+			name:          "empty city",
+			city:          " ",
+			setupMocks:    func(weatherSvc *mocks.MockWeatherService) {},
+			expectedError: domain.ErrInvalidInput,
+		},
+		{
+			name: "unknown city",
+			city: "Atlantis",
+			setupMocks: func(weatherSvc *mocks.MockWeatherService) {
+				weatherSvc.On("GetWeather", mock.Anything, "Atlantis").Return(domain.Weather{}, domain.ErrCityNotFound)
+			},
+			expectedError: domain.ErrCityNotFound,
+		},
+		{
+			name: "too many streams",
+			city: "Kyiv",
+			open: 2,
+			setupMocks: func(weatherSvc *mocks.MockWeatherService) {
+				weatherSvc.On("GetWeather", mock.Anything, mock.Anything).Return(domain.Weather{Description: "Sunny"}, nil)
+			},
+			expectedError: domain.ErrTooManyStreams,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			weatherSvc := &mocks.MockWeatherService{}
+			tt.setupMocks(weatherSvc)
+			streamer := NewWeatherStreamer(weatherSvc, time.Hour, 2, testLogger)
+			defer streamer.Close()
+			for _, city := range []string{"Lviv", "Odesa"}[:tt.open] {
+				_, err := streamer.Subscribe(context.Background(), city)
+				require.NoError(t, err)
+			}
+
+			updates, err := streamer.Subscribe(context.Background(), tt.city)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, updates)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, domain.CityWeather{City: "Kyiv", Weather: domain.Weather{Description: "Sunny"}}, <-updates)
+			}
+		})
+	}
+}
+
+func TestWeatherStreamer_FreesSlotWhenStreamEnds(t *testing.T) {
+	weatherSvc := &mocks.MockWeatherService{}
+	weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{}, nil)
+	streamer := NewWeatherStreamer(weatherSvc, time.Hour, 1, testLogger)
+	defer streamer.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	updates, err := streamer.Subscribe(ctx, "Kyiv")
+	require.NoError(t, err)
+	_, err = streamer.Subscribe(context.Background(), "Kyiv")
+	assert.ErrorIs(t, err, domain.ErrTooManyStreams)
+
+	cancel()
+	<-updates
+	assertClosed(t, updates)
+	_, err = streamer.Subscribe(context.Background(), "Kyiv")
+	assert.NoError(t, err)
+}
+
+func TestWeatherStreamer_CloseEndsStreams(t *testing.T) {
+	weatherSvc := &mocks.MockWeatherService{}
+	weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{}, nil)
+	streamer := NewWeatherStreamer(weatherSvc, time.Hour, 10, testLogger)
+
+	updates, err := streamer.Subscribe(context.Background(), "Kyiv")
+	require.NoError(t, err)
+	streamer.Close()
+
+	<-updates
+	assertClosed(t, updates)
+	_, err = streamer.Subscribe(context.Background(), "Kyiv")
+	assert.Error(t, err)
+}
+
+func TestWeatherStreamer_NextPoll(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	streamer := NewWeatherStreamer(&mocks.MockWeatherService{}, time.Minute, 10, testLogger)
+	streamer.now = func() time.Time { return now }
+
+	tests := []struct {
+		name     string
+		update   domain.CityWeather
+		expected time.Duration
+	}{
+		{
+			name:     "cached weather",
+			update:   domain.CityWeather{Weather: domain.Weather{ExpiresAt: now.Add(3 * time.Minute)}},
+			expected: 3 * time.Minute,
+		},
+		{
+			name:     "stale weather",
+			update:   domain.CityWeather{Weather: domain.Weather{ExpiresAt: now.Add(-time.Minute)}},
+			expected: time.Minute,
+		},
+		{
+			name:     "uncached weather",
+			update:   domain.CityWeather{},
+			expected: time.Minute,
+		},
+		{
+			name:     "error",
+			update:   domain.CityWeather{Err: errors.New("connection reset")},
+			expected: time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, streamer.nextPoll(tt.update))
+		})
+	}
+}
+
+func TestRefreshed(t *testing.T) {
+	now := time.Now()
+	sunny := domain.CityWeather{City: "Kyiv", Weather: domain.Weather{Temperature: 20.5, Description: "Sunny", ExpiresAt: now}}
+	rainy := sunny
+	rainy.Weather.Description = "Rain"
+	recached := sunny
+	recached.Weather.ExpiresAt = now.Add(time.Minute)
+	failed := domain.CityWeather{City: "Kyiv", Err: domain.ErrProviderUnavailable}
+
+	assert.False(t, refreshed(sunny, sunny))
+	assert.True(t, refreshed(sunny, rainy))
+	assert.True(t, refreshed(sunny, recached))
+	assert.True(t, refreshed(sunny, failed))
+	assert.False(t, refreshed(failed, failed))
+	assert.True(t, refreshed(failed, sunny))
+}
+
+// receiveUntil reads updates until one has the expected weather.
+func receiveUntil(t *testing.T, updates <-chan domain.CityWeather, expected domain.Weather) domain.CityWeather {
+	t.Helper()
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case update := <-updates:
+			if update.Weather == expected {
+				return update
+			}
+		case <-timeout:
+			t.Fatal("no update with the expected weather")
+		}
+	}
+}
+
+func assertClosed(t *testing.T, updates <-chan domain.CityWeather) {
+	t.Helper()
+	select {
+	case _, ok := <-updates:
+		assert.False(t, ok, "stream is still open")
+	case <-time.After(time.Second):
+		t.Fatal("stream was not closed")
+	}
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/port"
+	"weather-api/internal/util"
+)
+
+type WebhookPolicy struct {
+	Retry RetryPolicy
+	// DisableAfter is the number of failed deliveries in a row after which a webhook is
+	// disabled; 0 never disables it.
+	DisableAfter int
+}
+
+// WebhookChannel POSTs notifications as signed JSON to the webhook of a subscription. Failed
+// deliveries are retried with jittered backoff unless the endpoint rejected them, and a delivery
+// that failed for good counts towards disabling the webhook.
+type WebhookChannel struct {
+	webhooks port.WebhookRepository
+	sender   port.WebhookSender
+	policy   WebhookPolicy
+	logger   *slog.Logger
+	now      func() time.Time
+	sleep    func(ctx context.Context, d time.Duration) error
+}
+
+func NewWebhookChannel(webhooks port.WebhookRepository, sender port.WebhookSender, policy WebhookPolicy, logger *slog.Logger) *WebhookChannel {
+	return &WebhookChannel{
+		webhooks: webhooks,
+		sender:   sender,
+		policy:   policy,
+		logger:   logger,
+		now:      time.Now,
+		sleep:    sleepContext,
+	}
+}
+
+// webhookPayload is the body of every delivery.
+type webhookPayload struct {
+	ID             string                   `json:"id"`
+	Event          domain.NotificationEvent `json:"event"`
+	CreatedAt      time.Time                `json:"created_at"`
+	SubscriptionID int                      `json:"subscription_id"`
+	City           string                   `json:"city"`
+	Weather        *domain.Weather          `json:"weather,omitempty"`
+	ObservedAt     *time.Time               `json:"observed_at,omitempty"`
+	UnsubscribeURL string                   `json:"unsubscribe_url,omitempty"`
+}
+
+func (c *WebhookChannel) Notify(ctx context.Context, sub domain.Subscription, notification domain.Notification) error {
+	hook, err := c.webhooks.GetWebhook(ctx, sub.ID)
+	if err != nil {
+		return err
+	}
+	if hook.Disabled() {
+		return domain.ErrWebhookDisabled
+	}
+	delivery, err := c.delivery(sub, notification)
+	if err != nil {
+		return err
+	}
+
+	err = c.send(ctx, hook, delivery)
+	c.record(ctx, hook, err)
+	return err
+}
+
+// Ping sends a ping event once, so that the owner can check the endpoint. A delivered ping
+// enables a disabled webhook again, while a failed one doesn't count towards disabling it.
+func (c *WebhookChannel) Ping(ctx context.Context, sub domain.Subscription) (domain.WebhookPing, error) {
+	if sub.Channel != domain.ChannelWebhook {
+		return domain.WebhookPing{}, &domain.ValidationError{Fields: map[string]string{"channel": "must be webhook"}}
+	}
+	hook, err := c.webhooks.GetWebhook(ctx, sub.ID)
+	if err != nil {
+		return domain.WebhookPing{}, err
+	}
+	delivery, err := c.delivery(sub, domain.Notification{Event: domain.NotificationPing, City: sub.City})
+	if err != nil {
+		return domain.WebhookPing{}, err
+	}
+
+	start := c.now()
+	err = c.sender.Send(ctx, hook, delivery)
+	ping := domain.WebhookPing{Delivered: err == nil, Duration: c.now().Sub(start)}
+	if err != nil {
+		c.logger.InfoContext(ctx, "Webhook ping failed", "delivery_id", delivery.ID, "error", err)
+		ping.Error = webhookFailureReason(err)
+		var webhookErr *domain.WebhookError
+		if errors.As(err, &webhookErr) {
+			ping.StatusCode = webhookErr.StatusCode
+		}
+		return ping, nil
+	}
+	if hook.Failures > 0 || hook.Disabled() {
+		if err := c.webhooks.RecordWebhookSuccess(ctx, hook.SubscriptionID); err != nil {
+			return domain.WebhookPing{}, err
+		}
+		if hook.Disabled() {
+			c.logger.InfoContext(ctx, "Webhook enabled again after a delivered ping")
+		}
+	}
+	return ping, nil
+}
+
+// Webhook returns the webhook of a subscription, e.g. to show whether it is disabled.
+func (c *WebhookChannel) Webhook(ctx context.Context, subscriptionID int) (domain.Webhook, error) {
+	return c.webhooks.GetWebhook(ctx, subscriptionID)
+}
+
+func (c *WebhookChannel) send(ctx context.Context, hook domain.Webhook, delivery domain.WebhookDelivery) error {
+	attempts := max(c.policy.Retry.MaxAttempts, 1)
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			if sleepErr := c.sleep(ctx, c.policy.Retry.backoff(attempt)); sleepErr != nil {
+				return err
+			}
+		}
+		err = c.sender.Send(ctx, hook, delivery)
+		if err == nil {
+			return nil
+		}
+		c.logger.WarnContext(ctx, "Webhook delivery failed", "delivery_id", delivery.ID, "attempt", attempt+1, "error", err)
+		var webhookErr *domain.WebhookError
+		if errors.As(err, &webhookErr) && webhookErr.Permanent() {
+			return err
+		}
+	}
+	return err
+}
+
+func (c *WebhookChannel) record(ctx context.Context, hook domain.Webhook, sendErr error) {
+	if sendErr == nil {
+		if hook.Failures > 0 {
+			if err := c.webhooks.RecordWebhookSuccess(context.WithoutCancel(ctx), hook.SubscriptionID); err != nil {
+				c.logger.ErrorContext(ctx, "Failed to reset webhook failures", "error", err)
+			}
+		}
+		return
+	}
+	// A delivery cut short by a shutdown says nothing about the endpoint.
+	if ctx.Err() != nil {
+		return
+	}
+
+	updated, err := c.webhooks.RecordWebhookFailure(ctx, hook.SubscriptionID, webhookFailureReason(sendErr), c.policy.DisableAfter)
+	if err != nil {
+		c.logger.ErrorContext(ctx, "Failed to record webhook failure", "error", err)
+		return
+	}
+	if updated.Disabled() {
+		c.logger.WarnContext(ctx, "Disabled webhook after repeated failures", "failures", updated.Failures)
+	}
+}
+
+// webhookFailureReason is what the owner of a webhook sees of a failed delivery, in a ping
+// response or as last_error. The full error only goes to the log.
+func webhookFailureReason(err error) string {
+	var webhookErr *domain.WebhookError
+	if errors.As(err, &webhookErr) {
+		return webhookErr.Reason()
+	}
+	return "webhook request failed"
+}
+
+func (c *WebhookChannel) delivery(sub domain.Subscription, notification domain.Notification) (domain.WebhookDelivery, error) {
+	payload := webhookPayload{
+		ID:             rand.Text(),
+		Event:          notification.Event,
+		CreatedAt:      c.now().UTC(),
+		SubscriptionID: sub.ID,
+		City:           notification.City,
+	}
+	if notification.Event == domain.NotificationWeatherUpdate {
+		payload.Weather = &notification.Weather
+		if !notification.Weather.ObservedAt.IsZero() {
+			payload.ObservedAt = &notification.Weather.ObservedAt
+		}
+	}
+	if notification.UnsubscribeToken != "" {
+		payload.UnsubscribeURL = util.UnsubscribeURL(notification.UnsubscribeToken)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	return domain.WebhookDelivery{ID: payload.ID, Event: payload.Event, Payload: body}, nil
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestWebhookChannel(webhooks *mocks.MockWebhookRepository, sender *mocks.MockWebhookSender) *WebhookChannel {
+	channel := NewWebhookChannel(webhooks, sender, WebhookPolicy{
+		Retry:        RetryPolicy{MaxAttempts: 3, BaseDelay: 10 * time.Millisecond, MaxDelay: 100 * time.Millisecond},
+		DisableAfter: 5,
+	}, testLogger)
+	channel.now = func() time.Time { return time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC) }
+	channel.sleep = func(ctx context.Context, d time.Duration) error { return nil }
+	return channel
+}
+
+func TestWebhookChannel_Notify(t *testing.T) {
+	ctx := context.Background()
+	sub := domain.Subscription{ID: 7, City: "Kyiv", Channel: domain.ChannelWebhook}
+	notification := domain.Notification{
+		Event:            domain.NotificationWeatherUpdate,
+		City:             "Kyiv",
+		Weather:          domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny"},
+		UnsubscribeToken: "token123",
+	}
+	hook := domain.Webhook{SubscriptionID: 7, URL: "https://hooks.example.com/weather", Secret: "whsec_test"}
+	unavailable := &domain.WebhookError{StatusCode: 503}
+
+	tests := []struct {
+		name          string
+		setupMocks    func(webhooks *mocks.MockWebhookRepository, sender *mocks.MockWebhookSender)
+		expectedError error
+		expectedSends int
+	}{
+		{
+			name: "delivers on first attempt",
+			setupMocks: func(webhooks *mocks.MockWebhookRepository, sender *mocks.MockWebhookSender) {
+				webhooks.On("GetWebhook", ctx, 7).Return(hook, nil)
+				sender.On("Send", ctx, hook, mock.Anything).Return(nil)
+			},
+			expectedSends: 1,
+		},
+		{
+			name: "retries until the endpoint recovers and resets failures",
+			setupMocks: func(webhooks *mocks.MockWebhookRepository, sender *mocks.MockWebhookSender) {
+				failing := hook
+				failing.Failures = 2
+				webhooks.On("GetWebhook", ctx, 7).Return(failing, nil)
+				sender.On("Send", ctx, failing, mock.Anything).Return(unavailable).Twice()
+				sender.On("Send", ctx, failing, mock.Anything).Return(nil).Once()
+				webhooks.On("RecordWebhookSuccess", mock.Anything, 7).Return(nil)
+			},
+			expectedSends: 3,
+		},
+		{
+			name: "records failure after max attempts",
+			setupMocks: func(webhooks *mocks.MockWebhookRepository, sender *mocks.MockWebhookSender) {
+				webhooks.On("GetWebhook", ctx, 7).Return(hook, nil)
+				sender.On("Send", ctx, hook, mock.Anything).Return(unavailable)
+				webhooks.On("RecordWebhookFailure", ctx, 7, "webhook responded with 503", 5).Return(domain.Webhook{Failures: 1}, nil)
+			},
+			expectedError: unavailable,
+			expectedSends: 3,
+		},
+		{
+			name: "does not retry a rejected delivery",
+			setupMocks: func(webhooks *mocks.MockWebhookRepository, sender *mocks.MockWebhookSender) {
+				gone := &domain.WebhookError{StatusCode: 410}
+				webhooks.On("GetWebhook", ctx, 7).Return(hook, nil)
+				sender.On("Send", ctx, hook, mock.Anything).Return(gone)
+				webhooks.On("RecordWebhookFailure", ctx, 7, "webhook responded with 410", 5).Return(domain.Webhook{Failures: 5, DisabledAt: time.Now()}, nil)
+			},
+			expectedError: &domain.WebhookError{StatusCode: 410},
+			expectedSends: 1,
+		},
+		{
+			name: "skips disabled webhook",
+			setupMocks: func(webhooks *mocks.MockWebhookRepository, sender *mocks.MockWebhookSender) {
+				disabled := hook
+				disabled.DisabledAt = time.Now()
+				webhooks.On("GetWebhook", ctx, 7).Return(disabled, nil)
+			},
+			expectedError: domain.ErrWebhookDisabled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhooks := &mocks.MockWebhookRepository{}
+			sender := &mocks.MockWebhookSender{}
+			tt.setupMocks(webhooks, sender)
+
+			err := newTestWebhookChannel(webhooks, sender).Notify(ctx, sub, notification)
+
+			if tt.expectedError != nil {
+				assert.Equal(t, tt.expectedError.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			sender.AssertNumberOfCalls(t, "Send", tt.expectedSends)
+			webhooks.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhookChannel_NotifySendsPayload(t *testing.T) {
+	ctx := context.Background()
+	webhooks := &mocks.MockWebhookRepository{}
+	sender := &mocks.MockWebhookSender{}
+	hook := domain.Webhook{SubscriptionID: 7, URL: "https://hooks.example.com/weather", Secret: "whsec_test"}
+	webhooks.On("GetWebhook", ctx, 7).Return(hook, nil)
+	var delivery domain.WebhookDelivery
+	sender.On("Send", ctx, hook, mock.Anything).Run(func(args mock.Arguments) {
+		delivery = args.Get(2).(domain.WebhookDelivery)
+	}).Return(nil)
+
+	err := newTestWebhookChannel(webhooks, sender).Notify(ctx, domain.Subscription{ID: 7, City: "Kyiv", Channel: domain.ChannelWebhook}, domain.Notification{
+		Event:   domain.NotificationWeatherUpdate,
+		City:    "Kyiv",
+		Weather: domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.NotificationWeatherUpdate, delivery.Event)
+	assert.NotEmpty(t, delivery.ID)
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal(delivery.Payload, &payload))
+	assert.Equal(t, delivery.ID, payload["id"])
+	assert.Equal(t, "weather.update", payload["event"])
+	assert.Equal(t, "2025-06-01T08:00:00Z", payload["created_at"])
+	assert.Equal(t, float64(7), payload["subscription_id"])
+	assert.Equal(t, map[string]any{"temperature": 20.5, "humidity": float64(60), "description": "Sunny"}, payload["weather"])
+}
+
+func TestWebhookChannel_Ping(t *testing.T) {
+	ctx := context.Background()
+	sub := domain.Subscription{ID: 7, City: "Kyiv", Channel: domain.ChannelWebhook}
+	hook := domain.Webhook{SubscriptionID: 7, URL: "https://hooks.example.com/weather", Secret: "whsec_test"}
+
+	tests := []struct {
+		name          string
+		sub           domain.Subscription
+		setupMocks    func(webhooks *mocks.MockWebhookRepository, sender *mocks.MockWebhookSender)
+		expected      domain.WebhookPing
+		expectedError error
+	}{
+		{
+			name: "delivered ping enables disabled webhook",
+			sub:  sub,
+			setupMocks: func(webhooks *mocks.MockWebhookRepository, sender *mocks.MockWebhookSender) {
+				disabled := hook
+				disabled.Failures, disabled.DisabledAt = 5, time.Now()
+				webhooks.On("GetWebhook", ctx, 7).Return(disabled, nil)
+				sender.On("Send", ctx, disabled, mock.MatchedBy(func(d domain.WebhookDelivery) bool {
+					return d.Event == domain.NotificationPing
+				})).Return(nil)
+				webhooks.On("RecordWebhookSuccess", ctx, 7).Return(nil)
+			},
+			expected: domain.WebhookPing{Delivered: true},
+		},
+		{
+			name: "failed ping is reported without counting it",
+			sub:  sub,
+			setupMocks: func(webhooks *mocks.MockWebhookRepository, sender *mocks.MockWebhookSender) {
+				webhooks.On("GetWebhook", ctx, 7).Return(hook, nil)
+				sender.On("Send", ctx, hook, mock.Anything).Return(&domain.WebhookError{StatusCode: 500}).Once()
+			},
+			expected: domain.WebhookPing{StatusCode: 500, Error: "webhook responded with 500"},
+		},
+		{
+			name: "failed ping hides the network error",
+			sub:  sub,
+			setupMocks: func(webhooks *mocks.MockWebhookRepository, sender *mocks.MockWebhookSender) {
+				webhooks.On("GetWebhook", ctx, 7).Return(hook, nil)
+				refused := &domain.WebhookError{Err: errors.New("dial tcp 10.0.0.5:6379: connect: connection refused")}
+				sender.On("Send", ctx, hook, mock.Anything).Return(refused).Once()
+			},
+			expected: domain.WebhookPing{Error: "webhook request failed"},
+		},
+		{
+			name: "failed ping to internal address",
+			sub:  sub,
+			setupMocks: func(webhooks *mocks.MockWebhookRepository, sender *mocks.MockWebhookSender) {
+				webhooks.On("GetWebhook", ctx, 7).Return(hook, nil)
+				forbidden := &domain.WebhookError{Err: fmt.Errorf("dial: %w: 10.0.0.5", domain.ErrWebhookAddressForbidden)}
+				sender.On("Send", ctx, hook, mock.Anything).Return(forbidden).Once()
+			},
+			expected: domain.WebhookPing{Error: "webhook address is not allowed"},
+		},
+		{
+			name:          "rejects email subscription",
+			sub:           domain.Subscription{ID: 7, Channel: domain.ChannelEmail},
+			setupMocks:    func(webhooks *mocks.MockWebhookRepository, sender *mocks.MockWebhookSender) {},
+			expectedError: domain.ErrInvalidInput,
+		},
+		{
+			name: "returns repository error",
+			sub:  sub,
+			setupMocks: func(webhooks *mocks.MockWebhookRepository, sender *mocks.MockWebhookSender) {
+				webhooks.On("GetWebhook", ctx, 7).Return(domain.Webhook{}, domain.ErrWebhookNotFound)
+			},
+			expectedError: domain.ErrWebhookNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhooks := &mocks.MockWebhookRepository{}
+			sender := &mocks.MockWebhookSender{}
+			tt.setupMocks(webhooks, sender)
+
+			ping, err := newTestWebhookChannel(webhooks, sender).Ping(ctx, tt.sub)
+
+			if tt.expectedError != nil {
+				assert.True(t, errors.Is(err, tt.expectedError), err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, ping)
+			}
+			webhooks.AssertExpectations(t)
+			sender.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package http
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/service"
+	"weather-api/internal/handler/http/middleware"
+	"weather-api/internal/handler/http/request"
+	"weather-api/internal/handler/http/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminHandler struct {
+	adminService        *service.AdminService
+	subscriptionService *service.SubscriptionService
+	runService          *service.RunService
+	quotaService        *service.QuotaWeatherService
+	logger              *slog.Logger
+}
+
+func NewAdminHandler(adminService *service.AdminService, subscriptionService *service.SubscriptionService, runService *service.RunService, quotaService *service.QuotaWeatherService, logger *slog.Logger) *AdminHandler {
+	return &AdminHandler{adminService: adminService, subscriptionService: subscriptionService, runService: runService, quotaService: quotaService, logger: logger}
+}
+
+func (h *AdminHandler) ListSubscriptions(c *gin.Context) {
+	filter, ok := subscriptionFilter(c)
+	if !ok {
+		return
+	}
+	var err error
+	if filter.Offset, err = queryInt(c, "offset"); err != nil {
+		writeInvalidInput(c, "", map[string]string{"offset": "must be an integer"})
+		return
+	}
+
+	page, err := h.adminService.ListSubscriptions(c, filter)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, response.NewAdminSubscriptionList(page))
+}
+
+func (h *AdminHandler) GetSubscription(c *gin.Context) {
+	id, ok := subscriptionID(c)
+	if !ok {
+		return
+	}
+	sub, err := h.adminService.GetSubscription(c, id)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, response.NewAdminSubscription(sub))
+}
+
+func (h *AdminHandler) ConfirmSubscription(c *gin.Context) {
+	id, ok := subscriptionID(c)
+	if !ok {
+		return
+	}
+	sub, err := h.adminService.ConfirmSubscription(c, id)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	h.logger.InfoContext(c, "Admin confirmed subscription", "admin", c.GetString(middleware.APIKeyNameKey), "subscription_id", id)
+	c.JSON(http.StatusOK, response.NewAdminSubscription(sub))
+}
+
+func (h *AdminHandler) DeleteSubscription(c *gin.Context) {
+	id, ok := subscriptionID(c)
+	if !ok {
+		return
+	}
+	if err := h.adminService.DeleteSubscription(c, id); err != nil {
+		writeError(c, err)
+		return
+	}
+	h.logger.InfoContext(c, "Admin deleted subscription", "admin", c.GetString(middleware.APIKeyNameKey), "subscription_id", id)
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AdminHandler) ResendConfirmation(c *gin.Context) {
+	id, ok := subscriptionID(c)
+	if !ok {
+		return
+	}
+	if err := h.subscriptionService.ResendConfirmation(c, id); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "Confirmation email sent"})
+}
+
+func (h *AdminHandler) GetStats(c *gin.Context) {
+	window := 24 * time.Hour
+	if raw := c.Query("window"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 {
+			writeInvalidInput(c, "", map[string]string{"window": "must be a positive duration"})
+			return
+		}
+		window = d
+	}
+
+	stats, err := h.adminService.GetStats(c, time.Now().Add(-window))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+func (h *AdminHandler) GetQuota(c *gin.Context) {
+	usage, err := h.quotaService.Usage(c)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, usage)
+}
+
+func (h *AdminHandler) TriggerRun(c *gin.Context) {
+	var req request.TriggerRunRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.InfoContext(c, "Invalid run request", "error", err)
+		writeInvalidInput(c, "", nil)
+		return
+	}
+	if req.Frequency != "" && req.Frequency != domain.FrequencyDaily && req.Frequency != domain.FrequencyHourly {
+		writeInvalidInput(c, "", map[string]string{"frequency": "must be hourly or daily"})
+		return
+	}
+
+	h.logger.InfoContext(c, "Admin triggered update run", "admin", c.GetString(middleware.APIKeyNameKey),
+		"frequency", req.Frequency, "city", req.City, "subscription_id", req.SubscriptionID, "dry_run", req.DryRun)
+
+	run, err := h.runService.Start(c, domain.RunRequest{
+		Frequency:      req.Frequency,
+		City:           req.City,
+		SubscriptionID: req.SubscriptionID,
+		DryRun:         req.DryRun,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidInput) {
+			writeInvalidInput(c, "frequency or subscription_id is required", nil)
+			return
+		}
+		writeError(c, err)
+		return
+	}
+	c.Header("Location", "/admin/runs/"+strconv.Itoa(run.ID))
+	c.JSON(http.StatusAccepted, run)
+}
+
+// GetRun returns a run started with TriggerRun, with its report once it has finished.
+func (h *AdminHandler) GetRun(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		writeInvalidInput(c, "", map[string]string{"id": "must be a positive integer"})
+		return
+	}
+	run, err := h.runService.GetRun(c, id)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, run)
+}
+
+// subscriptionFilter reads the filter and page size of a subscription listing from the query.
+func subscriptionFilter(c *gin.Context) (domain.SubscriptionFilter, bool) {
+	filter := domain.SubscriptionFilter{
+		Query:     c.Query("q"),
+		City:      c.Query("city"),
+		Frequency: domain.Frequency(c.Query("frequency")),
+	}
+	if filter.Frequency != "" && filter.Frequency != domain.FrequencyDaily && filter.Frequency != domain.FrequencyHourly {
+		writeInvalidInput(c, "", map[string]string{"frequency": "must be hourly or daily"})
+		return filter, false
+	}
+	if raw := c.Query("confirmed"); raw != "" {
+		confirmed, err := strconv.ParseBool(raw)
+		if err != nil {
+			writeInvalidInput(c, "", map[string]string{"confirmed": "must be a boolean"})
+			return filter, false
+		}
+		filter.Confirmed = &confirmed
+	}
+	var err error
+	if filter.Limit, err = queryInt(c, "limit"); err != nil {
+		writeInvalidInput(c, "", map[string]string{"limit": "must be an integer"})
+		return filter, false
+	}
+	return filter, true
+}
+
+func subscriptionID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		writeInvalidInput(c, "", map[string]string{"id": "must be a positive integer"})
+		return 0, false
+	}
+	return id, true
+}
+
+func queryInt(c *gin.Context, key string) (int, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(raw)
+}
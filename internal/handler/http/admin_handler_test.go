@@ -0,0 +1,62 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/service"
+	"weather-api/internal/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminHandler_Runs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	scheduledTime := time.Date(2025, 6, 1, 12, 17, 3, 0, time.UTC)
+	run := domain.JobRun{ID: 12, Job: "daily_updates", ScheduledTime: scheduledTime, Status: domain.JobRunRunning, Owner: "replica-1", StartedAt: scheduledTime}
+	repo := &mocks.MockSubscriptionRepository{}
+	deliveries := &mocks.MockDeliveryRepository{}
+	runs := &mocks.MockJobRunRepository{}
+	repo.On("GetSubscriptionsByFrequency", mock.Anything, "daily").Return([]domain.Subscription{}, nil)
+	deliveries.On("GetDeliveredSubscriptionIDs", mock.Anything, 12).Return(map[int]bool{}, nil)
+	runs.On("AcquireLock", mock.Anything, "daily_updates").Return(func() {}, true, nil).Once()
+	runs.On("AcquireLock", mock.Anything, "daily_updates").Return(nil, false, nil).Once()
+	runs.On("ClaimRun", mock.Anything, "daily_updates", mock.Anything, "replica-1").Return(run, true, nil)
+	runs.On("SaveReport", mock.Anything, 12, mock.Anything).Return(nil)
+	runs.On("FinishRun", mock.Anything, 12, domain.JobRunCompleted, "").Return(nil)
+	runs.On("GetRun", mock.Anything, 99).Return(domain.JobRun{}, domain.ErrJobRunNotFound)
+
+	scheduler := service.NewScheduler(runs, "replica-1", time.Second, testLogger)
+	emailSvc := service.NewEmailService(repo, deliveries, &mocks.MockWeatherService{}, service.NotificationChannels{}, nil, testLogger)
+	handler := NewAdminHandler(nil, nil, service.NewRunService(scheduler, emailSvc, repo, runs, testLogger), nil, testLogger)
+	r := gin.New()
+	r.POST("/admin/runs", handler.TriggerRun)
+	r.GET("/admin/runs/:id", handler.GetRun)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/runs", strings.NewReader(`{"frequency":"daily"}`)))
+	require.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+	assert.Equal(t, "/admin/runs/12", rec.Header().Get("Location"))
+	assert.JSONEq(t, `{"id":12,"job":"daily_updates","scheduled_time":"2025-06-01T12:17:03Z","status":"running","owner":"replica-1","started_at":"2025-06-01T12:17:03Z"}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/runs", strings.NewReader(`{"frequency":"daily"}`)))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.JSONEq(t, `{"code":"job_busy","message":"Job is already running"}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/runs/99", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.JSONEq(t, `{"code":"job_run_not_found","message":"Job run not found"}`, rec.Body.String())
+
+	// Shutdown waits for the started run.
+	assert.NoError(t, scheduler.Shutdown(context.Background(), time.Second))
+	runs.AssertExpectations(t)
+}
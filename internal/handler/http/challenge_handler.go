@@ -0,0 +1,32 @@
+package http
+
+import (
+	"net/http"
+	"weather-api/internal/core/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ChallengeHandler struct {
+	verifier *service.ProofOfWorkVerifier
+}
+
+// NewChallengeHandler creates the handler. verifier is nil when proof of work is disabled, and
+// the page is then told so with a 404, like any unknown API path.
+func NewChallengeHandler(verifier *service.ProofOfWorkVerifier) *ChallengeHandler {
+	return &ChallengeHandler{verifier: verifier}
+}
+
+func (h *ChallengeHandler) IssueChallenge(c *gin.Context) {
+	if h.verifier == nil {
+		writeErrorResponse(c, http.StatusNotFound, "challenge_disabled", "Proof of work is not enabled", nil)
+		return
+	}
+	challenge, err := h.verifier.IssueChallenge()
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, challenge)
+}
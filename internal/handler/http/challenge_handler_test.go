@@ -0,0 +1,46 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChallengeAndUnknownPaths_AnswerJSONWithoutProofOfWork(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	page := filepath.Join(t.TempDir(), "index.html")
+	assert.NoError(t, os.WriteFile(page, []byte("<html></html>"), 0o600))
+	r := gin.New()
+	r.GET("/api/challenge", NewChallengeHandler(nil).IssueChallenge)
+	r.NoRoute(NoRoute(page))
+
+	tests := []struct {
+		path         string
+		expectedCode int
+		expectedBody string
+	}{
+		{path: "/api/challenge", expectedCode: http.StatusNotFound, expectedBody: `{"code":"challenge_disabled","message":"Proof of work is not enabled"}`},
+		{path: "/api/unknown", expectedCode: http.StatusNotFound, expectedBody: `{"code":"not_found","message":"Not found"}`},
+		{path: "/admin", expectedCode: http.StatusNotFound, expectedBody: `{"code":"not_found","message":"Not found"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			assert.Contains(t, rec.Header().Get("Content-Type"), "application/json")
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/apidocs", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "<html></html>", rec.Body.String())
+}
@@ -0,0 +1,127 @@
+package http
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/handler/http/response"
+	"weather-api/internal/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultRetryAfter is suggested to clients when the provider gave no better hint.
+const defaultRetryAfter = 30
+
+const codeInternal = "internal_error"
+
+type errorMapping struct {
+	err    error
+	status int
+	code   string
+}
+
+// errorMappings maps domain errors to HTTP responses. The first match wins, so more specific
+// errors come first.
+var errorMappings = []errorMapping{
+	{domain.ErrInvalidInput, http.StatusBadRequest, "invalid_input"},
+	{domain.ErrInvalidToken, http.StatusBadRequest, "invalid_token"},
+	{domain.ErrTokenExpired, http.StatusGone, "token_expired"},
+	{domain.ErrTokenNotFound, http.StatusNotFound, "token_not_found"},
+	{domain.ErrSubscriptionNotFound, http.StatusNotFound, "subscription_not_found"},
+	{domain.ErrCityNotFound, http.StatusNotFound, "city_not_found"},
+	{domain.ErrWebhookNotFound, http.StatusNotFound, "webhook_not_found"},
+	{domain.ErrFeedNotFound, http.StatusNotFound, "feed_not_found"},
+	{domain.ErrJobRunNotFound, http.StatusNotFound, "job_run_not_found"},
+	{domain.ErrEmailAlreadySubscribed, http.StatusConflict, "email_already_subscribed"},
+	{domain.ErrAlreadyConfirmed, http.StatusConflict, "already_confirmed"},
+	{domain.ErrJobBusy, http.StatusConflict, "job_busy"},
+	{domain.ErrPreconditionFailed, http.StatusPreconditionFailed, "precondition_failed"},
+	{domain.ErrPreconditionRequired, http.StatusPreconditionRequired, "precondition_required"},
+	{domain.ErrUnauthorized, http.StatusUnauthorized, "unauthorized"},
+	{domain.ErrForbidden, http.StatusForbidden, "forbidden"},
+	{domain.ErrBotDetected, http.StatusForbidden, "verification_failed"},
+	{domain.ErrRateLimited, http.StatusTooManyRequests, "rate_limited"},
+	{domain.ErrTooManyStreams, http.StatusServiceUnavailable, "too_many_streams"},
+	{domain.ErrShuttingDown, http.StatusServiceUnavailable, "shutting_down"},
+	{domain.ErrQuotaExhausted, http.StatusServiceUnavailable, "quota_exhausted"},
+	{domain.ErrProviderUnavailable, http.StatusServiceUnavailable, "provider_unavailable"},
+}
+
+// writeError writes the response for err. Errors without a mapping are answered with a generic
+// 500 and attached to the context, so that the request logger records them.
+func writeError(c *gin.Context, err error) {
+	if m, ok := lookupError(err); ok {
+		if m.status == http.StatusServiceUnavailable || m.status == http.StatusTooManyRequests {
+			seconds := defaultRetryAfter
+			if retryAfter := domain.RetryAfter(err); retryAfter > 0 {
+				seconds = int(math.Ceil(retryAfter.Seconds()))
+			}
+			c.Header("Retry-After", strconv.Itoa(seconds))
+		}
+		var details map[string]string
+		var validation *domain.ValidationError
+		if errors.As(err, &validation) {
+			details = validation.Fields
+		}
+		writeErrorResponse(c, m.status, m.code, m.err.Error(), details)
+		return
+	}
+
+	_ = c.Error(err)
+	writeErrorResponse(c, http.StatusInternalServerError, codeInternal, "Internal server error", nil)
+}
+
+func lookupError(err error) (errorMapping, bool) {
+	for _, m := range errorMappings {
+		if errors.Is(err, m.err) {
+			return m, true
+		}
+	}
+	return errorMapping{}, false
+}
+
+// itemError describes the failure of one item of a batch. Unexpected errors are attached to
+// the context like in writeError.
+func itemError(c *gin.Context, err error) *response.Error {
+	if m, ok := lookupError(err); ok {
+		return &response.Error{Code: m.code, Message: m.err.Error()}
+	}
+	_ = c.Error(err)
+	return &response.Error{Code: codeInternal, Message: "Internal server error"}
+}
+
+// writeInvalidInput rejects a request that failed validation in the handler.
+func writeInvalidInput(c *gin.Context, message string, details map[string]string) {
+	if message == "" {
+		message = domain.ErrInvalidInput.Error()
+	}
+	writeErrorResponse(c, http.StatusBadRequest, "invalid_input", message, details)
+}
+
+func writeErrorResponse(c *gin.Context, status int, code, message string, details map[string]string) {
+	c.AbortWithStatusJSON(status, response.Error{
+		Code:      code,
+		Message:   message,
+		Details:   details,
+		RequestID: util.RequestIDFromContext(c),
+	})
+}
+
+// NoRoute answers unknown API and admin paths with a 404 error and everything else with page,
+// so that clients of the API never get the HTML page instead of JSON.
+func NoRoute(page string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		for _, prefix := range []string{"/api", "/admin"} {
+			if path == prefix || strings.HasPrefix(path, prefix+"/") {
+				writeErrorResponse(c, http.StatusNotFound, "not_found", "Not found", nil)
+				return
+			}
+		}
+		c.File(page)
+	}
+}
@@ -0,0 +1,155 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/service"
+	"weather-api/internal/handler/http/middleware"
+	"weather-api/internal/handler/http/response"
+	"weather-api/internal/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteError(t *testing.T) {
+	tests := []struct {
+		name               string
+		err                error
+		expectedStatus     int
+		expectedBody       response.Error
+		expectedRetryAfter string
+		expectedAttached   bool
+	}{
+		{
+			name:           "wrapped domain error",
+			err:            fmt.Errorf("lookup Atlantis: %w", domain.ErrCityNotFound),
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   response.Error{Code: "city_not_found", Message: "City not found"},
+		},
+		{
+			name:           "validation error",
+			err:            &domain.ValidationError{Fields: map[string]string{"email": "is not a valid email address"}},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   response.Error{Code: "invalid_input", Message: "Invalid input", Details: map[string]string{"email": "is not a valid email address"}},
+		},
+		{
+			name:               "rate limited",
+			err:                &domain.RateLimitError{RetryAfter: 1500 * time.Millisecond},
+			expectedStatus:     http.StatusTooManyRequests,
+			expectedBody:       response.Error{Code: "rate_limited", Message: "Too many requests"},
+			expectedRetryAfter: "2",
+		},
+		{
+			name:               "provider unavailable with retry hint",
+			err:                &domain.ProviderUnavailableError{RetryAfter: 2 * time.Minute},
+			expectedStatus:     http.StatusServiceUnavailable,
+			expectedBody:       response.Error{Code: "provider_unavailable", Message: "Weather provider unavailable"},
+			expectedRetryAfter: "120",
+		},
+		{
+			name:               "provider unavailable without retry hint",
+			err:                &domain.ProviderUnavailableError{Err: errors.New("dial tcp: connection refused")},
+			expectedStatus:     http.StatusServiceUnavailable,
+			expectedBody:       response.Error{Code: "provider_unavailable", Message: "Weather provider unavailable"},
+			expectedRetryAfter: "30",
+		},
+		{
+			name:               "quota exhausted before provider unavailable",
+			err:                &domain.ProviderUnavailableError{RetryAfter: time.Hour, Err: domain.ErrQuotaExhausted},
+			expectedStatus:     http.StatusServiceUnavailable,
+			expectedBody:       response.Error{Code: "quota_exhausted", Message: "Weather provider quota exhausted"},
+			expectedRetryAfter: "3600",
+		},
+		{
+			name:             "unexpected error",
+			err:              errors.New("pq: connection refused"),
+			expectedStatus:   http.StatusInternalServerError,
+			expectedBody:     response.Error{Code: "internal_error", Message: "Internal server error"},
+			expectedAttached: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			var attached []*gin.Error
+			r := gin.New()
+			r.ContextWithFallback = true
+			r.Use(middleware.RequestID())
+			r.GET("/", func(c *gin.Context) {
+				writeError(c, tt.err)
+				attached = c.Errors
+			})
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(middleware.RequestIDHeader, "req-1")
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			var body response.Error
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			tt.expectedBody.RequestID = "req-1"
+			assert.Equal(t, tt.expectedBody, body)
+			assert.Equal(t, tt.expectedRetryAfter, rec.Header().Get("Retry-After"))
+			// Internal details are only logged, never sent.
+			assert.NotContains(t, rec.Body.String(), "connection refused")
+			if tt.expectedAttached {
+				require.Len(t, attached, 1)
+				assert.Equal(t, tt.err, attached[0].Err)
+			} else {
+				assert.Empty(t, attached)
+			}
+		})
+	}
+}
+
+func TestLookupError_NoMappingIsShadowed(t *testing.T) {
+	for _, m := range errorMappings {
+		found, ok := lookupError(fmt.Errorf("context: %w", m.err))
+
+		assert.True(t, ok, m.code)
+		assert.Equal(t, m.code, found.code)
+	}
+	_, ok := lookupError(errors.New("unexpected"))
+	assert.False(t, ok)
+}
+
+func TestHandlers_AnswerUnexpectedErrorsWithEnvelope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	weatherSvc := &mocks.MockWeatherService{}
+	weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{}, errors.New("API error: bad response"))
+	repo := &mocks.MockSubscriptionRepository{}
+	repo.On("IsEmailSubscribed", mock.Anything, "user1@example.com").Return(false, errors.New("pq: connection refused"))
+	subscriptions := service.NewSubscriptionService(repo, weatherSvc, &mocks.MockEmailService{}, &mocks.MockTokenService{}, service.SubscriptionOptions{}, testLogger)
+
+	r := gin.New()
+	r.ContextWithFallback = true
+	r.Use(middleware.RequestID())
+	r.GET("/api/weather", NewWeatherHandler(service.NewWeatherService(weatherSvc, 3, 2)).GetWeather)
+	r.POST("/api/subscribe", NewSubscriptionHandler(subscriptions, service.HoneypotVerifier{}, testLogger).Subscribe)
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/api/weather?city=Kyiv", nil),
+		httptest.NewRequest(http.MethodPost, "/api/subscribe", strings.NewReader(`{"email":"user1@example.com","city":"Kyiv","frequency":"daily"}`)),
+	} {
+		t.Run(req.URL.Path, func(t *testing.T) {
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set(middleware.RequestIDHeader, "req-1")
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusInternalServerError, rec.Code)
+			assert.JSONEq(t, `{"code":"internal_error","message":"Internal server error","request_id":"req-1"}`, rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package http
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/service"
+	"weather-api/internal/handler/http/response"
+	"weather-api/internal/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FeedHandler struct {
+	feeds          *service.FeedService
+	forecastMaxAge time.Duration
+	now            func() time.Time
+}
+
+// NewFeedHandler creates the handler. Calendars may be cached for forecastMaxAge, which is also
+// how often calendar apps are asked to refresh them.
+func NewFeedHandler(feeds *service.FeedService, forecastMaxAge time.Duration) *FeedHandler {
+	return &FeedHandler{feeds: feeds, forecastMaxAge: forecastMaxAge, now: time.Now}
+}
+
+// GetFeed serves /feeds/{city}.atom, the recent weather of a city, and /feeds/{city}.ics, its
+// daily forecast as a calendar subscription.
+func (h *FeedHandler) GetFeed(c *gin.Context) {
+	file := c.Param("file")
+	if city, ok := strings.CutSuffix(file, ".atom"); ok {
+		h.atom(c, city)
+		return
+	}
+	if city, ok := strings.CutSuffix(file, ".ics"); ok {
+		h.calendar(c, city)
+		return
+	}
+	writeError(c, domain.ErrFeedNotFound)
+}
+
+// atom is cacheable like the current weather: until the next lookup, which may add an entry.
+func (h *FeedHandler) atom(c *gin.Context, city string) {
+	history, err := h.feeds.History(c, city)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	feed := response.NewAtomFeed(history, response.FeedURL(util.GetBaseURL(), history.City, "atom"), h.now())
+	body, err := xml.Marshal(feed)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	body = append([]byte(xml.Header), body...)
+
+	var lastModified time.Time
+	if len(history.Observations) > 0 {
+		lastModified = history.Observations[0].ObservedAt
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+	etag := bodyETag(body)
+	c.Header("ETag", etag)
+	if maxAge := int(history.ExpiresAt.Sub(h.now()).Seconds()); maxAge > 0 {
+		c.Header("Cache-Control", "public, max-age="+strconv.Itoa(maxAge))
+	} else {
+		c.Header("Cache-Control", "no-cache")
+	}
+
+	if notModified(c.Request, etag, lastModified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "application/atom+xml; charset=utf-8", body)
+}
+
+// calendar is tagged by the forecast rather than the body, whose DTSTAMP changes every time.
+func (h *FeedHandler) calendar(c *gin.Context, city string) {
+	forecast, err := h.feeds.Forecast(c, city)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	data, err := json.Marshal(forecast)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	etag := bodyETag(data)
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "public, max-age="+strconv.Itoa(int(h.forecastMaxAge.Seconds())))
+	if notModified(c.Request, etag, time.Time{}) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	name := strings.TrimSpace(city)
+	body := response.NewForecastCalendar(name, forecast, response.FeedURL(util.GetBaseURL(), name, "ics"), h.forecastMaxAge, h.now())
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", body)
+}
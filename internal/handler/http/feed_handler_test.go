@@ -0,0 +1,165 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/service"
+	"weather-api/internal/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestFeedHandler_AtomCachingHeaders(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 5, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                 string
+		weather              domain.Weather
+		observations         []domain.Observation
+		headers              map[string]string
+		expectedStatus       int
+		expectedCacheControl string
+		expectedLastModified string
+	}{
+		{
+			name:                 "cached weather",
+			weather:              domain.Weather{ExpiresAt: now.Add(2 * time.Minute)},
+			observations:         testObservations,
+			expectedStatus:       http.StatusOK,
+			expectedCacheControl: "public, max-age=120",
+			expectedLastModified: "Sun, 01 Jun 2025 12:00:00 GMT",
+		},
+		{
+			name:                 "uncached weather",
+			observations:         testObservations,
+			expectedStatus:       http.StatusOK,
+			expectedCacheControl: "no-cache",
+			expectedLastModified: "Sun, 01 Jun 2025 12:00:00 GMT",
+		},
+		{
+			name:                 "empty history",
+			weather:              domain.Weather{ExpiresAt: now.Add(2 * time.Minute)},
+			observations:         []domain.Observation{},
+			expectedStatus:       http.StatusOK,
+			expectedCacheControl: "public, max-age=120",
+		},
+		{
+			name:                 "not modified since",
+			weather:              domain.Weather{ExpiresAt: now.Add(2 * time.Minute)},
+			observations:         testObservations,
+			headers:              map[string]string{"If-Modified-Since": "Sun, 01 Jun 2025 12:00:00 GMT"},
+			expectedStatus:       http.StatusNotModified,
+			expectedCacheControl: "public, max-age=120",
+			expectedLastModified: "Sun, 01 Jun 2025 12:00:00 GMT",
+		},
+		{
+			name:                 "new observation",
+			weather:              domain.Weather{ExpiresAt: now.Add(2 * time.Minute)},
+			observations:         testObservations,
+			headers:              map[string]string{"If-Modified-Since": "Sun, 01 Jun 2025 11:00:00 GMT"},
+			expectedStatus:       http.StatusOK,
+			expectedCacheControl: "public, max-age=120",
+			expectedLastModified: "Sun, 01 Jun 2025 12:00:00 GMT",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			weatherSvc := &mocks.MockWeatherService{}
+			weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(tt.weather, nil)
+			history := &mocks.MockObservationRepository{}
+			history.On("GetObservations", mock.Anything, "Kyiv", 48).Return(tt.observations, nil)
+
+			rec := serveFeed(t, now, weatherSvc, history, &mocks.MockForecastService{}, "/api/feeds/Kyiv.atom", tt.headers)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectedCacheControl, rec.Header().Get("Cache-Control"))
+			assert.Equal(t, tt.expectedLastModified, rec.Header().Get("Last-Modified"))
+			assert.NotEmpty(t, rec.Header().Get("ETag"))
+		})
+	}
+}
+
+func TestFeedHandler_Atom(t *testing.T) {
+	weatherSvc := &mocks.MockWeatherService{}
+	weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{}, nil)
+	history := &mocks.MockObservationRepository{}
+	history.On("GetObservations", mock.Anything, "Kyiv", 48).Return(testObservations, nil)
+
+	rec := serveFeed(t, time.Now(), weatherSvc, history, &mocks.MockForecastService{}, "/api/feeds/%20Kyiv%20.atom", nil)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/atom+xml; charset=utf-8", rec.Header().Get("Content-Type"))
+	body := rec.Body.String()
+	assert.Contains(t, body, `<feed xmlns="http://www.w3.org/2005/Atom">`)
+	assert.Contains(t, body, "<updated>2025-06-01T12:00:00Z</updated>")
+	assert.Contains(t, body, "<id>http://localhost:8080/api/feeds/Kyiv.atom#2025-06-01T11:00:00Z</id>")
+	assert.Contains(t, body, "<title>18.0°C, Partly cloudy</title>")
+	assert.Less(t, strings.Index(body, "12:00:00Z</id>"), strings.Index(body, "11:00:00Z</id>"))
+}
+
+func TestFeedHandler_Calendar(t *testing.T) {
+	forecast := []domain.DailyForecast{
+		{Date: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), MinTemperature: 14, MaxTemperature: 22.7, Humidity: 60, Description: "Sunny, then cloudy"},
+		{Date: time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), MinTemperature: 12, MaxTemperature: 18, Humidity: 80, Description: "Patchy rain possible"},
+	}
+	serve := func(now time.Time, headers map[string]string) *httptest.ResponseRecorder {
+		forecasts := &mocks.MockForecastService{}
+		forecasts.On("GetForecast", mock.Anything, "Kyiv", 3).Return(forecast, nil)
+		return serveFeed(t, now, &mocks.MockWeatherService{}, &mocks.MockObservationRepository{}, forecasts, "/api/feeds/Kyiv.ics", headers)
+	}
+
+	rec := serve(time.Date(2025, 6, 1, 6, 0, 0, 0, time.UTC), nil)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/calendar; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "public, max-age=3600", rec.Header().Get("Cache-Control"))
+	body := rec.Body.String()
+	assert.True(t, strings.HasPrefix(body, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(body, "END:VEVENT\r\nEND:VCALENDAR\r\n"))
+	assert.Equal(t, 2, strings.Count(body, "BEGIN:VEVENT\r\n"))
+	assert.Contains(t, body, "UID:20250601-kyiv@weather-api\r\n")
+	assert.Contains(t, body, "DTSTAMP:20250601T060000Z\r\n")
+	assert.Contains(t, body, "DTSTART;VALUE=DATE:20250602\r\nDTEND;VALUE=DATE:20250603\r\n")
+	assert.Contains(t, body, `SUMMARY:23°C / 14°C\, Sunny\, then cloudy`)
+	assert.Contains(t, body, "REFRESH-INTERVAL;VALUE=DURATION:PT60M\r\n")
+	for _, line := range strings.Split(body, "\r\n") {
+		assert.LessOrEqual(t, len(line), 75, line)
+	}
+
+	// Calendars are stamped when they are served, which mustn't change their ETag.
+	etag := rec.Header().Get("ETag")
+	later := serve(time.Date(2025, 6, 1, 7, 0, 0, 0, time.UTC), nil)
+	assert.Equal(t, etag, later.Header().Get("ETag"))
+	assert.Equal(t, http.StatusNotModified, serve(time.Now(), map[string]string{"If-None-Match": etag}).Code)
+}
+
+func TestFeedHandler_UnknownFormat(t *testing.T) {
+	rec := serveFeed(t, time.Now(), &mocks.MockWeatherService{}, &mocks.MockObservationRepository{}, &mocks.MockForecastService{}, "/api/feeds/Kyiv.rss", nil)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"feed_not_found"`)
+}
+
+func serveFeed(t *testing.T, now time.Time, weatherSvc *mocks.MockWeatherService, history *mocks.MockObservationRepository, forecasts *mocks.MockForecastService, target string, headers map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	handler := NewFeedHandler(service.NewFeedService(weatherSvc, history, forecasts, 48, 3, testLogger), time.Hour)
+	handler.now = func() time.Time { return now }
+
+	r := gin.New()
+	r.GET("/api/feeds/:file", handler.GetFeed)
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
@@ -0,0 +1,41 @@
+package http
+
+import (
+	"net/http"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthHandler struct {
+	healthService *service.HealthService
+}
+
+func NewHealthHandler(healthService *service.HealthService) *HealthHandler {
+	return &HealthHandler{healthService: healthService}
+}
+
+func (h *HealthHandler) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": domain.HealthStatusUp})
+}
+
+// Readiness only exposes the status of each dependency; errors and details are admin-only.
+func (h *HealthHandler) Readiness(c *gin.Context) {
+	report := h.healthService.Readiness(c)
+
+	components := gin.H{}
+	for _, component := range report.Components {
+		components[component.Name] = component.Status
+	}
+
+	code := http.StatusOK
+	if report.Status != domain.HealthStatusUp {
+		code = http.StatusServiceUnavailable
+	}
+	c.JSON(code, gin.H{"status": report.Status, "components": components})
+}
+
+func (h *HealthHandler) Status(c *gin.Context) {
+	c.JSON(http.StatusOK, h.healthService.Status(c))
+}
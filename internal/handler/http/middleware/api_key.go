@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/service"
+	"weather-api/internal/handler/http/response"
+	"weather-api/internal/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+const APIKeyNameKey = "api_key_name"
+
+// RequireScope rejects requests without a valid admin API key carrying the given scope.
+// The key is read from "Authorization: Bearer <key>" or the X-API-Key header.
+func RequireScope(auth *service.APIKeyService, scope domain.Scope, logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key, err := auth.Authenticate(apiKeyFromRequest(c.Request))
+		if err != nil {
+			logger.WarnContext(c, "Rejected admin request: invalid API key", "route", c.FullPath())
+			c.Header("WWW-Authenticate", `Bearer realm="admin"`)
+			abortWithError(c, http.StatusUnauthorized, "unauthorized", domain.ErrUnauthorized)
+			return
+		}
+		if !key.HasScope(scope) {
+			logger.WarnContext(c, "Rejected admin request: missing scope", "route", c.FullPath(), "admin", key.Name, "scope", scope)
+			abortWithError(c, http.StatusForbidden, "forbidden", domain.ErrForbidden)
+			return
+		}
+		c.Set(APIKeyNameKey, key.Name)
+		c.Next()
+	}
+}
+
+func apiKeyFromRequest(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		if token, ok := strings.CutPrefix(auth, "Bearer "); ok {
+			return strings.TrimSpace(token)
+		}
+	}
+	return r.Header.Get("X-API-Key")
+}
+
+func abortWithError(c *gin.Context, status int, code string, err error) {
+	c.AbortWithStatusJSON(status, response.Error{
+		Code:      code,
+		Message:   err.Error(),
+		RequestID: util.RequestIDFromContext(c),
+	})
+}
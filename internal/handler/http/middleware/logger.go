@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Logger logs one line per request. Only the route pattern is logged, never the raw path,
+// since confirm and unsubscribe paths contain tokens.
+func Logger(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		level := slog.LevelInfo
+		if c.Writer.Status() >= 500 {
+			level = slog.LevelError
+		}
+		attrs := []any{
+			"method", c.Request.Method,
+			"route", c.FullPath(),
+			"status", c.Writer.Status(),
+			"duration_ms", time.Since(start).Milliseconds(),
+			"client_ip", c.ClientIP(),
+		}
+		// Unexpected errors are answered with a generic message, the cause is only logged.
+		if err := c.Errors.Last(); err != nil {
+			attrs = append(attrs, "error", err.Err)
+		}
+		logger.Log(c, level, "Handled request", attrs...)
+	}
+}
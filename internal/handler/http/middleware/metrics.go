@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HTTPObserver interface {
+	ObserveHTTPRequest(method, route string, status int, duration time.Duration)
+}
+
+// Metrics reports every request to observer, labelled with the route pattern rather than the
+// raw path so that tokens and ids don't create a series each.
+func Metrics(observer HTTPObserver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		observer.ObserveHTTPRequest(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
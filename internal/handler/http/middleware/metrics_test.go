@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type observedRequest struct {
+	method, route string
+	status        int
+}
+
+type recordingHTTPObserver struct {
+	requests []observedRequest
+}
+
+func (o *recordingHTTPObserver) ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	o.requests = append(o.requests, observedRequest{method: method, route: route, status: status})
+}
+
+func TestMetrics_LabelsRequestsByRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	observer := &recordingHTTPObserver{}
+	r := gin.New()
+	r.Use(Metrics(observer))
+	r.GET("/api/confirm/:token", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.DELETE("/api/v2/subscriptions/:id", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/api/confirm/token123", nil),
+		httptest.NewRequest(http.MethodDelete, "/api/v2/subscriptions/7", nil),
+		httptest.NewRequest(http.MethodGet, "/wp-login.php", nil),
+	} {
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	assert.Equal(t, []observedRequest{
+		{method: http.MethodGet, route: "/api/confirm/:token", status: http.StatusOK},
+		{method: http.MethodDelete, route: "/api/v2/subscriptions/:id", status: http.StatusNotFound},
+		{method: http.MethodGet, route: "unmatched", status: http.StatusNotFound},
+	}, observer.requests)
+}
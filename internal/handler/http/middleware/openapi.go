@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/handler/http/response"
+	"weather-api/internal/util"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	// The stock decoder turns every property missing from a form into null, which fails
+	// validation of optional, non-nullable fields.
+	openapi3filter.RegisterBodyDecoder("application/x-www-form-urlencoded", func(body io.Reader, header http.Header, schema *openapi3.SchemaRef, encFn openapi3filter.EncodingFn) (any, error) {
+		value, err := openapi3filter.UrlencodedBodyDecoder(body, header, schema, encFn)
+		if obj, ok := value.(map[string]any); ok {
+			for name, v := range obj {
+				if v == nil {
+					delete(obj, name)
+				}
+			}
+		}
+		return value, err
+	})
+}
+
+// ValidateRequest rejects requests to operations described in doc whose parameters or body
+// don't match the spec. Requests to other routes are passed through unchecked.
+func ValidateRequest(doc *openapi3.T, logger *slog.Logger) (gin.HandlerFunc, error) {
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, err
+	}
+	options := &openapi3filter.Options{AuthenticationFunc: openapi3filter.NoopAuthenticationFunc}
+
+	return func(c *gin.Context) {
+		route, pathParams, err := router.FindRoute(c.Request)
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		err = openapi3filter.ValidateRequest(c, &openapi3filter.RequestValidationInput{
+			Request:    c.Request,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		})
+		if err != nil {
+			logger.InfoContext(c, "Request does not match API spec", "route", c.FullPath(), "error", err)
+			c.AbortWithStatusJSON(http.StatusBadRequest, response.Error{
+				Code:      "invalid_input",
+				Message:   domain.ErrInvalidInput.Error(),
+				Details:   validationDetails(err),
+				RequestID: util.RequestIDFromContext(c),
+			})
+			return
+		}
+		c.Next()
+	}, nil
+}
+
+func validationDetails(err error) map[string]string {
+	var requestErr *openapi3filter.RequestError
+	if !errors.As(err, &requestErr) {
+		return nil
+	}
+	if requestErr.Parameter != nil {
+		return map[string]string{requestErr.Parameter.Name: reason(requestErr)}
+	}
+
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) {
+		field := strings.Join(schemaErr.JSONPointer(), ".")
+		if field == "" {
+			field = "body"
+		}
+		return map[string]string{field: schemaErr.Reason}
+	}
+	return map[string]string{"body": reason(requestErr)}
+}
+
+func reason(err *openapi3filter.RequestError) string {
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) {
+		return schemaErr.Reason
+	}
+	if err.Err != nil {
+		return err.Err.Error()
+	}
+	return err.Reason
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/service"
+	"weather-api/internal/handler/http/response"
+	"weather-api/internal/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitByIP limits requests per client IP under the given rule and reports the state of the
+// client's bucket in the RateLimit-* headers of the IETF draft.
+func RateLimitByIP(limiter *service.RateLimiter, rule string) gin.HandlerFunc {
+	limit, ok := limiter.Limit(rule)
+	if !ok {
+		return func(c *gin.Context) { c.Next() }
+	}
+	policy := fmt.Sprintf("%d;w=%d", limit.Limit, int(limit.Period.Seconds()))
+
+	return func(c *gin.Context) {
+		result, ok := limiter.Allow(c, rule, c.ClientIP())
+		if !ok {
+			c.Next()
+			return
+		}
+		c.Header("RateLimit-Policy", policy)
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, response.Error{
+				Code:      "rate_limited",
+				Message:   domain.ErrRateLimited.Error(),
+				RequestID: util.RequestIDFromContext(c),
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
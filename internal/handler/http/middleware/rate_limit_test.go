@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"weather-api/internal/adapter/ratelimit"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/service"
+	"weather-api/internal/handler/http/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitByIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter := service.NewRateLimiter(ratelimit.NewMemoryStore(), map[string]domain.RateLimit{
+		"weather_ip": {Limit: 2, Period: time.Hour},
+	}, slog.New(slog.DiscardHandler))
+	r := gin.New()
+	r.ContextWithFallback = true
+	r.Use(RequestID())
+	r.GET("/limited", RateLimitByIP(limiter, "weather_ip"), func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/unlimited", RateLimitByIP(limiter, "unknown_rule"), func(c *gin.Context) { c.Status(http.StatusOK) })
+	serve := func(path, ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = ip + ":12345"
+		req.Header.Set(RequestIDHeader, "req-1")
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	tests := []struct {
+		ip                 string
+		expectedStatus     int
+		expectedRemaining  string
+		expectedRetryAfter string
+	}{
+		{ip: "192.0.2.1", expectedStatus: http.StatusOK, expectedRemaining: "1"},
+		{ip: "192.0.2.1", expectedStatus: http.StatusOK, expectedRemaining: "0"},
+		{ip: "192.0.2.1", expectedStatus: http.StatusTooManyRequests, expectedRemaining: "0", expectedRetryAfter: "1800"},
+		{ip: "192.0.2.2", expectedStatus: http.StatusOK, expectedRemaining: "1"},
+	}
+	for _, tt := range tests {
+		rec := serve("/limited", tt.ip)
+
+		assert.Equal(t, tt.expectedStatus, rec.Code)
+		assert.Equal(t, "2;w=3600", rec.Header().Get("RateLimit-Policy"))
+		assert.Equal(t, "2", rec.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, tt.expectedRemaining, rec.Header().Get("RateLimit-Remaining"))
+		assert.NotEmpty(t, rec.Header().Get("RateLimit-Reset"))
+		assert.Equal(t, tt.expectedRetryAfter, rec.Header().Get("Retry-After"))
+		if tt.expectedStatus == http.StatusTooManyRequests {
+			assert.Equal(t, "3600", rec.Header().Get("RateLimit-Reset"))
+			var body response.Error
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, response.Error{Code: "rate_limited", Message: "Too many requests", RequestID: "req-1"}, body)
+		}
+	}
+
+	rec := serve("/unlimited", "192.0.2.1")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("RateLimit-Limit"))
+}
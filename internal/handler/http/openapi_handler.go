@@ -0,0 +1,43 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gin-gonic/gin"
+)
+
+const swaggerUIPage = `<!DOCTYPE html>
+<html>
+<head>
+    <title>Weather API</title>
+    <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+    <div id="swagger-ui"></div>
+    <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+    <script>
+        SwaggerUIBundle({ url: "/api/openapi.json", dom_id: "#swagger-ui" });
+    </script>
+</body>
+</html>`
+
+type OpenAPIHandler struct {
+	spec []byte
+}
+
+func NewOpenAPIHandler(doc *openapi3.T) (*OpenAPIHandler, error) {
+	spec, err := doc.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return &OpenAPIHandler{spec: spec}, nil
+}
+
+func (h *OpenAPIHandler) Spec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json", h.spec)
+}
+
+func (h *OpenAPIHandler) SwaggerUI(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerUIPage))
+}
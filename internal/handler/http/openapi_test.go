@@ -0,0 +1,566 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"weather-api/api"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/service"
+	"weather-api/internal/handler/http/middleware"
+	"weather-api/internal/handler/http/response"
+	"weather-api/internal/mocks"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testLogger = slog.New(slog.DiscardHandler)
+
+var testSubscription = domain.Subscription{ID: 7, Email: "user1@example.com", City: "Kyiv", Frequency: domain.FrequencyDaily, Token: "token123", Version: 2}
+
+var testObservations = []domain.Observation{
+	{City: "Kyiv", Weather: domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny"}, ObservedAt: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)},
+	{City: "Kyiv", Weather: domain.Weather{Temperature: 18, Humidity: 70, Description: "Partly cloudy"}, ObservedAt: time.Date(2025, 6, 1, 11, 0, 0, 0, time.UTC)},
+}
+
+type testDeps struct {
+	repo       *mocks.MockSubscriptionRepository
+	weatherSvc *mocks.MockWeatherService
+	emailSvc   *mocks.MockEmailService
+	tokenSvc   *mocks.MockTokenService
+	webhooks   *mocks.MockWebhookRepository
+	sender     *mocks.MockWebhookSender
+	telegram   *mocks.MockTelegramSender
+	history    *mocks.MockObservationRepository
+	forecasts  *mocks.MockForecastService
+}
+
+func newTestRouter(t *testing.T, doc *openapi3.T, deps testDeps) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	weatherService := service.NewWeatherService(deps.weatherSvc, 3, 2)
+	slack := &mocks.MockSlackSender{}
+	slack.On("AcceptsWebhookURL", mock.MatchedBy(func(url string) bool {
+		return strings.HasPrefix(url, "https://hooks.slack.com/services/")
+	})).Return(true)
+	slack.On("AcceptsWebhookURL", mock.Anything).Return(false)
+	subscriptionService := service.NewSubscriptionService(deps.repo, deps.weatherSvc, deps.emailSvc, deps.tokenSvc, service.SubscriptionOptions{Slack: slack}, testLogger)
+	weatherHandler := NewWeatherHandler(weatherService)
+	adminService := service.NewAdminService(deps.repo, &mocks.MockDeliveryRepository{}, testLogger)
+	subscriptionHandler := NewSubscriptionHandler(subscriptionService, service.HoneypotVerifier{}, testLogger)
+	webhookChannel := service.NewWebhookChannel(deps.webhooks, deps.sender, service.WebhookPolicy{}, testLogger)
+	telegramHandler := NewTelegramHandler(service.NewTelegramBot(subscriptionService, deps.telegram, testLogger), "telegram-secret", testLogger)
+	resourceHandler := NewSubscriptionResourceHandler(subscriptionService, adminService, webhookChannel, service.HoneypotVerifier{}, testLogger)
+	feedHandler := NewFeedHandler(service.NewFeedService(deps.weatherSvc, deps.history, deps.forecasts, 48, 3, testLogger), time.Hour)
+
+	validateRequest, err := middleware.ValidateRequest(doc, testLogger)
+	require.NoError(t, err)
+
+	r := gin.New()
+	r.ContextWithFallback = true
+	r.Use(middleware.RequestID())
+	api := r.Group("/api", validateRequest)
+	api.GET("/weather", weatherHandler.GetWeather)
+	api.GET("/weather/batch", weatherHandler.GetWeatherBatch)
+	api.POST("/weather/batch", weatherHandler.GetWeatherBatch)
+	api.GET("/feeds/:file", feedHandler.GetFeed)
+	api.POST("/subscribe", subscriptionHandler.Subscribe)
+	api.GET("/confirm/:token", subscriptionHandler.Confirm)
+	api.GET("/unsubscribe/:token", subscriptionHandler.Unsubscribe)
+	api.POST("/telegram/webhook", telegramHandler.Webhook)
+	v2 := api.Group("/v2")
+	v2.POST("/subscriptions", resourceHandler.Create)
+	v2.GET("/subscriptions", resourceHandler.List)
+	v2.GET("/subscriptions/:id", resourceHandler.Get)
+	v2.PATCH("/subscriptions/:id", resourceHandler.Update)
+	v2.DELETE("/subscriptions/:id", resourceHandler.Delete)
+	v2.POST("/subscriptions/:id/confirmations", resourceHandler.Confirm)
+	v2.POST("/subscriptions/:id/pings", resourceHandler.Ping)
+	return r
+}
+
+func init() {
+	// The validator decodes response bodies to check them; feeds are checked as plain text.
+	openapi3filter.RegisterBodyDecoder("application/atom+xml", openapi3filter.PlainBodyDecoder)
+	openapi3filter.RegisterBodyDecoder("text/calendar", openapi3filter.PlainBodyDecoder)
+}
+
+func TestHandlers_ConformToOpenAPISpec(t *testing.T) {
+	doc, err := api.LoadSpec()
+	require.NoError(t, err)
+	router, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		contentType    string
+		headers        map[string]string
+		body           string
+		setupMocks     func(deps testDeps)
+		expectedStatus int
+	}{
+		{
+			name:   "weather",
+			method: http.MethodGet,
+			target: "/api/weather?city=Kyiv",
+			setupMocks: func(deps testDeps) {
+				deps.weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "weather for unknown city",
+			method: http.MethodGet,
+			target: "/api/weather?city=Atlantis",
+			setupMocks: func(deps testDeps) {
+				deps.weatherSvc.On("GetWeather", mock.Anything, "Atlantis").Return(domain.Weather{}, domain.ErrCityNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "weather provider unavailable",
+			method: http.MethodGet,
+			target: "/api/weather?city=Kyiv",
+			setupMocks: func(deps testDeps) {
+				deps.weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{}, &domain.ProviderUnavailableError{})
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:   "weather batch with a failed city",
+			method: http.MethodGet,
+			target: "/api/weather/batch?city=Kyiv&city=Atlantis",
+			setupMocks: func(deps testDeps) {
+				deps.weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny"}, nil)
+				deps.weatherSvc.On("GetWeather", mock.Anything, "Atlantis").Return(domain.Weather{}, domain.ErrCityNotFound)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:        "weather batch with JSON body",
+			method:      http.MethodPost,
+			target:      "/api/weather/batch",
+			contentType: "application/json",
+			body:        `{"cities":["Kyiv","Lviv"]}`,
+			setupMocks: func(deps testDeps) {
+				deps.weatherSvc.On("GetWeather", mock.Anything, mock.Anything).Return(domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "weather batch over the limit",
+			method:         http.MethodGet,
+			target:         "/api/weather/batch?city=Kyiv&city=Lviv&city=Odesa&city=Dnipro",
+			setupMocks:     func(deps testDeps) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "weather without city",
+			method:         http.MethodGet,
+			target:         "/api/weather",
+			setupMocks:     func(deps testDeps) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "subscribe with form body",
+			method:      http.MethodPost,
+			target:      "/api/subscribe",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "email=user1%40example.com&city=Kyiv&frequency=daily",
+			setupMocks: func(deps testDeps) {
+				deps.repo.On("IsEmailSubscribed", mock.Anything, "user1@example.com").Return(false, nil)
+				deps.weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{}, nil)
+				deps.tokenSvc.On("GenerateToken").Return("token123", nil)
+				deps.repo.On("CreateSubscription", mock.Anything, mock.Anything).Return(1, nil)
+				deps.emailSvc.On("SendEmail", "user1@example.com", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "subscribe with invalid email",
+			method:         http.MethodPost,
+			target:         "/api/subscribe",
+			contentType:    "application/json",
+			body:           `{"email":"user1@","city":"Kyiv","frequency":"daily"}`,
+			setupMocks:     func(deps testDeps) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "subscribe with unknown frequency",
+			method:         http.MethodPost,
+			target:         "/api/subscribe",
+			contentType:    "application/json",
+			body:           `{"email":"user1@example.com","city":"Kyiv","frequency":"weekly"}`,
+			setupMocks:     func(deps testDeps) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "subscribe with filled honeypot",
+			method:         http.MethodPost,
+			target:         "/api/subscribe",
+			contentType:    "application/json",
+			body:           `{"email":"user1@example.com","city":"Kyiv","frequency":"daily","website":"http://spam.example"}`,
+			setupMocks:     func(deps testDeps) {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:        "subscribe twice",
+			method:      http.MethodPost,
+			target:      "/api/subscribe",
+			contentType: "application/json",
+			body:        `{"email":"user1@example.com","city":"Kyiv","frequency":"daily"}`,
+			setupMocks: func(deps testDeps) {
+				deps.repo.On("IsEmailSubscribed", mock.Anything, "user1@example.com").Return(true, nil)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:   "confirm",
+			method: http.MethodGet,
+			target: "/api/confirm/token123",
+			setupMocks: func(deps testDeps) {
+				deps.repo.On("IsTokenExists", mock.Anything, "token123").Return(true, nil)
+				deps.repo.On("GetSubscriptionByToken", mock.Anything, "token123").Return(domain.Subscription{ID: 1, Token: "token123"}, nil)
+				deps.repo.On("UpdateSubscription", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "unsubscribe with unknown token",
+			method: http.MethodGet,
+			target: "/api/unsubscribe/token123",
+			setupMocks: func(deps testDeps) {
+				deps.repo.On("IsTokenExists", mock.Anything, "token123").Return(false, nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:        "v2 create subscription",
+			method:      http.MethodPost,
+			target:      "/api/v2/subscriptions",
+			contentType: "application/json",
+			body:        `{"email":"user1@example.com","city":"Kyiv","frequency":"daily"}`,
+			setupMocks: func(deps testDeps) {
+				deps.repo.On("IsEmailSubscribed", mock.Anything, "user1@example.com").Return(false, nil)
+				deps.weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{}, nil)
+				deps.tokenSvc.On("GenerateToken").Return("token123", nil)
+				deps.repo.On("CreateSubscription", mock.Anything, mock.Anything).Return(7, nil)
+				deps.emailSvc.On("SendEmail", "user1@example.com", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:   "v2 list subscriptions",
+			method: http.MethodGet,
+			target: "/api/v2/subscriptions?limit=1",
+			setupMocks: func(deps testDeps) {
+				deps.repo.On("ListSubscriptionsAfter", mock.Anything, mock.Anything).Return([]domain.Subscription{testSubscription, {ID: 8}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:    "v2 get subscription",
+			method:  http.MethodGet,
+			target:  "/api/v2/subscriptions/7",
+			headers: map[string]string{"Authorization": "Bearer token123"},
+			setupMocks: func(deps testDeps) {
+				deps.repo.On("GetSubscriptionByID", mock.Anything, 7).Return(testSubscription, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:    "v2 get unchanged subscription",
+			method:  http.MethodGet,
+			target:  "/api/v2/subscriptions/7",
+			headers: map[string]string{"Authorization": "Bearer token123", "If-None-Match": `"2"`},
+			setupMocks: func(deps testDeps) {
+				deps.repo.On("GetSubscriptionByID", mock.Anything, 7).Return(testSubscription, nil)
+			},
+			expectedStatus: http.StatusNotModified,
+		},
+		{
+			name:           "v2 get subscription without token",
+			method:         http.MethodGet,
+			target:         "/api/v2/subscriptions/7",
+			setupMocks:     func(deps testDeps) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:        "v2 update subscription",
+			method:      http.MethodPatch,
+			target:      "/api/v2/subscriptions/7",
+			contentType: "application/merge-patch+json",
+			headers:     map[string]string{"Authorization": "Bearer token123", "If-Match": `"2"`},
+			body:        `{"frequency":"hourly"}`,
+			setupMocks: func(deps testDeps) {
+				deps.repo.On("GetSubscriptionByID", mock.Anything, 7).Return(testSubscription, nil)
+				updated := testSubscription
+				updated.Frequency, updated.Version = domain.FrequencyHourly, 3
+				deps.repo.On("UpdateSubscriptionByID", mock.Anything, mock.Anything).Return(updated, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "v2 update subscription without If-Match",
+			method:         http.MethodPatch,
+			target:         "/api/v2/subscriptions/7",
+			contentType:    "application/json",
+			headers:        map[string]string{"Authorization": "Bearer token123"},
+			body:           `{"frequency":"hourly"}`,
+			setupMocks:     func(deps testDeps) {},
+			expectedStatus: http.StatusPreconditionRequired,
+		},
+		{
+			name:        "v2 update stale subscription",
+			method:      http.MethodPatch,
+			target:      "/api/v2/subscriptions/7",
+			contentType: "application/json",
+			headers:     map[string]string{"Authorization": "Bearer token123", "If-Match": `"1"`},
+			body:        `{"frequency":"hourly"}`,
+			setupMocks: func(deps testDeps) {
+				deps.repo.On("GetSubscriptionByID", mock.Anything, 7).Return(testSubscription, nil)
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:    "v2 delete subscription",
+			method:  http.MethodDelete,
+			target:  "/api/v2/subscriptions/7",
+			headers: map[string]string{"Authorization": "Bearer token123"},
+			setupMocks: func(deps testDeps) {
+				deps.repo.On("GetSubscriptionByID", mock.Anything, 7).Return(testSubscription, nil)
+				deps.repo.On("DeleteSubscriptionByID", mock.Anything, 7, 0).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:        "v2 confirm subscription",
+			method:      http.MethodPost,
+			target:      "/api/v2/subscriptions/7/confirmations",
+			contentType: "application/json",
+			body:        `{"token":"token123"}`,
+			setupMocks: func(deps testDeps) {
+				deps.repo.On("GetSubscriptionByID", mock.Anything, 7).Return(testSubscription, nil)
+				confirmed := testSubscription
+				confirmed.IsConfirmed, confirmed.Version = true, 3
+				deps.repo.On("UpdateSubscriptionByID", mock.Anything, mock.Anything).Return(confirmed, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:        "v2 create webhook subscription",
+			method:      http.MethodPost,
+			target:      "/api/v2/subscriptions",
+			contentType: "application/json",
+			body:        `{"email":"user1@example.com","city":"Kyiv","frequency":"daily","channel":"webhook","webhook_url":"https://hooks.example.com/weather"}`,
+			setupMocks: func(deps testDeps) {
+				deps.repo.On("IsEmailSubscribed", mock.Anything, "user1@example.com").Return(false, nil)
+				deps.weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{}, nil)
+				deps.tokenSvc.On("GenerateToken").Return("token123", nil)
+				deps.repo.On("CreateSubscription", mock.Anything, mock.MatchedBy(func(sub domain.Subscription) bool {
+					return sub.Channel == domain.ChannelWebhook && sub.Webhook != nil && sub.Webhook.URL == "https://hooks.example.com/weather"
+				})).Return(7, nil)
+				deps.emailSvc.On("SendEmail", "user1@example.com", mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "v2 create slack subscription outside slack",
+			method:         http.MethodPost,
+			target:         "/api/v2/subscriptions",
+			contentType:    "application/json",
+			body:           `{"email":"user1@example.com","city":"Kyiv","frequency":"daily","channel":"slack","webhook_url":"https://example.com/services/T000"}`,
+			setupMocks:     func(deps testDeps) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "telegram subscribe command",
+			method:      http.MethodPost,
+			target:      "/api/telegram/webhook",
+			contentType: "application/json",
+			headers:     map[string]string{TelegramSecretHeader: "telegram-secret"},
+			body:        `{"update_id":1,"message":{"message_id":5,"chat":{"id":42,"type":"private"},"text":"/subscribe Kyiv daily"}}`,
+			setupMocks: func(deps testDeps) {
+				deps.weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{}, nil)
+				deps.repo.On("GetSubscriptionByAddress", mock.Anything, domain.ChannelTelegram, "42").Return(domain.Subscription{}, domain.ErrSubscriptionNotFound)
+				deps.tokenSvc.On("GenerateToken").Return("token123", nil)
+				deps.repo.On("CreateSubscription", mock.Anything, mock.Anything).Return(3, nil)
+				deps.telegram.On("SendMessage", mock.Anything, "42", "Subscribed to daily weather updates for Kyiv.").Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "telegram update with wrong secret",
+			method:         http.MethodPost,
+			target:         "/api/telegram/webhook",
+			contentType:    "application/json",
+			headers:        map[string]string{TelegramSecretHeader: "guess"},
+			body:           `{"update_id":1,"message":{"chat":{"id":42},"text":"/unsubscribe"}}`,
+			setupMocks:     func(deps testDeps) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "v2 create webhook subscription without URL",
+			method:         http.MethodPost,
+			target:         "/api/v2/subscriptions",
+			contentType:    "application/json",
+			body:           `{"email":"user1@example.com","city":"Kyiv","frequency":"daily","channel":"webhook"}`,
+			setupMocks:     func(deps testDeps) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:    "v2 ping webhook",
+			method:  http.MethodPost,
+			target:  "/api/v2/subscriptions/7/pings",
+			headers: map[string]string{"Authorization": "Bearer token123"},
+			setupMocks: func(deps testDeps) {
+				sub := testSubscription
+				sub.Channel = domain.ChannelWebhook
+				deps.repo.On("GetSubscriptionByID", mock.Anything, 7).Return(sub, nil)
+				deps.webhooks.On("GetWebhook", mock.Anything, 7).Return(domain.Webhook{SubscriptionID: 7, URL: "https://hooks.example.com/weather", Secret: "whsec_test"}, nil)
+				deps.sender.On("Send", mock.Anything, mock.Anything, mock.Anything).Return(&domain.WebhookError{StatusCode: http.StatusInternalServerError})
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:    "v2 ping email subscription",
+			method:  http.MethodPost,
+			target:  "/api/v2/subscriptions/7/pings",
+			headers: map[string]string{"Authorization": "Bearer token123"},
+			setupMocks: func(deps testDeps) {
+				deps.repo.On("GetSubscriptionByID", mock.Anything, 7).Return(testSubscription, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "atom feed",
+			method: http.MethodGet,
+			target: "/api/feeds/Kyiv.atom",
+			setupMocks: func(deps testDeps) {
+				deps.weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny"}, nil)
+				deps.history.On("GetObservations", mock.Anything, "Kyiv", 48).Return(testObservations, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:    "atom feed not modified",
+			method:  http.MethodGet,
+			target:  "/api/feeds/Kyiv.atom",
+			headers: map[string]string{"If-Modified-Since": "Sun, 01 Jun 2025 12:00:00 GMT"},
+			setupMocks: func(deps testDeps) {
+				deps.weatherSvc.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny"}, nil)
+				deps.history.On("GetObservations", mock.Anything, "Kyiv", 48).Return(testObservations, nil)
+			},
+			expectedStatus: http.StatusNotModified,
+		},
+		{
+			name:   "atom feed of unknown city",
+			method: http.MethodGet,
+			target: "/api/feeds/Atlantis.atom",
+			setupMocks: func(deps testDeps) {
+				deps.weatherSvc.On("GetWeather", mock.Anything, "Atlantis").Return(domain.Weather{}, domain.ErrCityNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "forecast calendar",
+			method: http.MethodGet,
+			target: "/api/feeds/New%20York.ics",
+			setupMocks: func(deps testDeps) {
+				deps.forecasts.On("GetForecast", mock.Anything, "New York", 3).Return([]domain.DailyForecast{
+					{Date: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), MinTemperature: 14, MaxTemperature: 22.5, Humidity: 60, Description: "Sunny"},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "forecast calendar while the provider is down",
+			method: http.MethodGet,
+			target: "/api/feeds/Kyiv.ics",
+			setupMocks: func(deps testDeps) {
+				deps.forecasts.On("GetForecast", mock.Anything, "Kyiv", 3).Return([]domain.DailyForecast(nil), &domain.ProviderUnavailableError{})
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deps := testDeps{
+				repo:       &mocks.MockSubscriptionRepository{},
+				weatherSvc: &mocks.MockWeatherService{},
+				emailSvc:   &mocks.MockEmailService{},
+				tokenSvc:   &mocks.MockTokenService{},
+				webhooks:   &mocks.MockWebhookRepository{},
+				sender:     &mocks.MockWebhookSender{},
+				telegram:   &mocks.MockTelegramSender{},
+				history:    &mocks.MockObservationRepository{},
+				forecasts:  &mocks.MockForecastService{},
+			}
+			tt.setupMocks(deps)
+
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			rec := httptest.NewRecorder()
+			newTestRouter(t, doc, deps).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code, rec.Body.String())
+			assertMatchesSpec(t, router, req, rec)
+			deps.telegram.AssertExpectations(t)
+		})
+	}
+}
+
+func TestValidateRequest_ReportsInvalidFields(t *testing.T) {
+	doc, err := api.LoadSpec()
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/subscribe", strings.NewReader(`{"email":"user1@example.com","city":"Kyiv","frequency":"weekly"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	newTestRouter(t, doc, testDeps{}).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	var body response.Error
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "invalid_input", body.Code)
+	assert.Contains(t, body.Details, "frequency")
+	assert.NotEmpty(t, body.RequestID)
+}
+
+func assertMatchesSpec(t *testing.T, router routers.Router, req *http.Request, rec *httptest.ResponseRecorder) {
+	t.Helper()
+	// The request body was consumed by the handler; it is not needed to check the response.
+	req.Body = io.NopCloser(bytes.NewReader(nil))
+	route, pathParams, err := router.FindRoute(req)
+	require.NoError(t, err)
+
+	err = openapi3filter.ValidateResponse(context.Background(), &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: &openapi3filter.RequestValidationInput{Request: req, PathParams: pathParams, Route: route},
+		Status:                 rec.Code,
+		Header:                 rec.Header(),
+		Body:                   io.NopCloser(bytes.NewReader(rec.Body.Bytes())),
+		Options:                &openapi3filter.Options{IncludeResponseStatus: true},
+	})
+	assert.NoError(t, err, rec.Body.String())
+}
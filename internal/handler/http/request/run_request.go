@@ -0,0 +1,10 @@
+package request
+
+import "weather-api/internal/core/domain"
+
+type TriggerRunRequest struct {
+	Frequency      domain.Frequency `json:"frequency"`
+	City           string           `json:"city"`
+	SubscriptionID int              `json:"subscription_id"`
+	DryRun         bool             `json:"dry_run"`
+}
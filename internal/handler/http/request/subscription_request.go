@@ -2,8 +2,35 @@ package request
 
 import "weather-api/internal/core/domain"
 
+// SubscribeRequest is bound from JSON or from a form-encoded body. The fields are validated and
+// normalized by the subscription service.
 type SubscribeRequest struct {
-	Email     string           `json:"email"`
-	City      string           `json:"city"`
-	Frequency domain.Frequency `json:"frequency"`
+	Email     string           `json:"email" form:"email"`
+	City      string           `json:"city" form:"city"`
+	Frequency domain.Frequency `json:"frequency" form:"frequency"`
+
+	// Website is the honeypot field, hidden on the page and left empty by people.
+	Website      string `json:"website" form:"website"`
+	Challenge    string `json:"challenge" form:"challenge"`
+	Nonce        string `json:"nonce" form:"nonce"`
+	CaptchaToken string `json:"captcha_token" form:"captcha_token"`
+}
+
+// CreateSubscriptionRequest is the body of a new v2 subscription, which may have its updates
+// delivered to a webhook.
+type CreateSubscriptionRequest struct {
+	SubscribeRequest
+	Channel    domain.Channel `json:"channel"`
+	WebhookURL string         `json:"webhook_url"`
+}
+
+// UpdateSubscriptionRequest is a JSON merge patch of a v2 subscription; absent fields are left
+// unchanged.
+type UpdateSubscriptionRequest struct {
+	City      *string           `json:"city"`
+	Frequency *domain.Frequency `json:"frequency"`
+}
+
+type ConfirmSubscriptionRequest struct {
+	Token string `json:"token"`
 }
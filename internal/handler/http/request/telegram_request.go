@@ -0,0 +1,15 @@
+package request
+
+// TelegramUpdate is the part of a Telegram Bot API update that the bot reads. Updates other than
+// messages, such as edits, have no Message.
+type TelegramUpdate struct {
+	UpdateID int              `json:"update_id"`
+	Message  *TelegramMessage `json:"message"`
+}
+
+type TelegramMessage struct {
+	Chat struct {
+		ID int64 `json:"id"`
+	} `json:"chat"`
+	Text string `json:"text"`
+}
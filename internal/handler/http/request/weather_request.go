@@ -0,0 +1,5 @@
+package request
+
+type WeatherBatchRequest struct {
+	Cities []string `json:"cities"`
+}
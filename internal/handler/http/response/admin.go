@@ -0,0 +1,36 @@
+package response
+
+import "weather-api/internal/core/domain"
+
+type AdminSubscription struct {
+	ID          int              `json:"id"`
+	Email       string           `json:"email"`
+	City        string           `json:"city"`
+	Frequency   domain.Frequency `json:"frequency"`
+	IsConfirmed bool             `json:"is_confirmed"`
+}
+
+type AdminSubscriptionList struct {
+	Items  []AdminSubscription `json:"items"`
+	Total  int                 `json:"total"`
+	Limit  int                 `json:"limit"`
+	Offset int                 `json:"offset"`
+}
+
+func NewAdminSubscription(sub domain.Subscription) AdminSubscription {
+	return AdminSubscription{
+		ID:          sub.ID,
+		Email:       sub.Email,
+		City:        sub.City,
+		Frequency:   sub.Frequency,
+		IsConfirmed: sub.IsConfirmed,
+	}
+}
+
+func NewAdminSubscriptionList(page domain.SubscriptionPage) AdminSubscriptionList {
+	items := make([]AdminSubscription, 0, len(page.Items))
+	for _, sub := range page.Items {
+		items = append(items, NewAdminSubscription(sub))
+	}
+	return AdminSubscriptionList{Items: items, Total: page.Total, Limit: page.Limit, Offset: page.Offset}
+}
@@ -0,0 +1,9 @@
+package response
+
+// Error is the body of every error response.
+type Error struct {
+	Code      string            `json:"code"`
+	Message   string            `json:"message"`
+	Details   map[string]string `json:"details,omitempty"`
+	RequestID string            `json:"request_id,omitempty"`
+}
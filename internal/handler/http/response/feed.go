@@ -0,0 +1,139 @@
+package response
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"weather-api/internal/core/domain"
+)
+
+type AtomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  AtomAuthor  `xml:"author"`
+	Link    AtomLink    `xml:"link"`
+	Entries []AtomEntry `xml:"entry"`
+}
+
+type AtomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type AtomLink struct {
+	Rel  string `xml:"rel,attr"`
+	Href string `xml:"href,attr"`
+}
+
+type AtomEntry struct {
+	ID      string `xml:"id"`
+	Title   string `xml:"title"`
+	Updated string `xml:"updated"`
+	Summary string `xml:"summary"`
+}
+
+// NewAtomFeed lists the observations of history as entries. The feed and its entries are
+// identified by feedURL, the URL the feed is served at, so that readers recognize entries they
+// have already seen. An empty feed is dated now.
+func NewAtomFeed(history domain.ObservationHistory, feedURL string, now time.Time) AtomFeed {
+	feed := AtomFeed{
+		ID:      feedURL,
+		Title:   "Weather in " + history.City,
+		Updated: now.UTC().Format(time.RFC3339),
+		Author:  AtomAuthor{Name: "Weather API"},
+		Link:    AtomLink{Rel: "self", Href: feedURL},
+		Entries: make([]AtomEntry, 0, len(history.Observations)),
+	}
+	if len(history.Observations) > 0 {
+		feed.Updated = history.Observations[0].ObservedAt.UTC().Format(time.RFC3339)
+	}
+	for _, observation := range history.Observations {
+		observedAt := observation.ObservedAt.UTC().Format(time.RFC3339)
+		weather := observation.Weather
+		feed.Entries = append(feed.Entries, AtomEntry{
+			ID:      feedURL + "#" + observedAt,
+			Title:   fmt.Sprintf("%.1f°C, %s", weather.Temperature, weather.Description),
+			Updated: observedAt,
+			Summary: fmt.Sprintf("Temperature %.2f°C, humidity %d%%, %s.", weather.Temperature, weather.Humidity, weather.Description),
+		})
+	}
+	return feed
+}
+
+// FeedURL returns the URL of a feed of city, with ext being "atom" or "ics".
+func FeedURL(baseURL, city, ext string) string {
+	return fmt.Sprintf("%s/api/feeds/%s.%s", baseURL, url.PathEscape(city), ext)
+}
+
+const icsTimeFormat = "20060102T150405Z"
+
+// NewForecastCalendar renders the forecast as an iCalendar with an all-day event per day.
+// Events keep their UID across refreshes, so that calendar apps update them in place; refresh
+// tells them how often to do that.
+func NewForecastCalendar(city string, forecast []domain.DailyForecast, calendarURL string, refresh time.Duration, now time.Time) []byte {
+	var b strings.Builder
+	line := func(name, value string) {
+		writeFolded(&b, name+":"+value)
+	}
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//Weather API//Forecast//EN")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	line("X-WR-CALNAME", icsText("Weather in "+city))
+	line("URL", calendarURL)
+	line("REFRESH-INTERVAL;VALUE=DURATION", icsDuration(refresh))
+	line("X-PUBLISHED-TTL", icsDuration(refresh))
+	stamp := now.UTC().Format(icsTimeFormat)
+	for _, day := range forecast {
+		date := day.Date.UTC()
+		line("BEGIN", "VEVENT")
+		line("UID", date.Format("20060102")+"-"+url.PathEscape(strings.ToLower(city))+"@weather-api")
+		line("DTSTAMP", stamp)
+		line("DTSTART;VALUE=DATE", date.Format("20060102"))
+		line("DTEND;VALUE=DATE", date.AddDate(0, 0, 1).Format("20060102"))
+		line("SUMMARY", icsText(fmt.Sprintf("%.0f°C / %.0f°C, %s", day.MaxTemperature, day.MinTemperature, day.Description)))
+		line("DESCRIPTION", icsText(fmt.Sprintf("High %.1f°C, low %.1f°C, humidity %d%%, %s.",
+			day.MaxTemperature, day.MinTemperature, day.Humidity, day.Description)))
+		line("TRANSP", "TRANSPARENT")
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return []byte(b.String())
+}
+
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", "")
+
+// icsText escapes a TEXT value as RFC 5545 section 3.3.11 requires.
+func icsText(s string) string {
+	return icsEscaper.Replace(s)
+}
+
+func icsDuration(d time.Duration) string {
+	if d < time.Minute {
+		d = time.Minute
+	}
+	return fmt.Sprintf("PT%dM", int(d.Minutes()))
+}
+
+// writeFolded writes a content line, folded after at most 75 octets without splitting a UTF-8
+// sequence, and terminated by CRLF.
+func writeFolded(b *strings.Builder, line string) {
+	const limit = 75
+	width := 0
+	for _, r := range line {
+		size := utf8.RuneLen(r)
+		if width+size > limit {
+			b.WriteString("\r\n ")
+			// The space that continues the line counts toward its length.
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	b.WriteString("\r\n")
+}
@@ -0,0 +1,80 @@
+package response
+
+import (
+	"time"
+	"weather-api/internal/core/domain"
+)
+
+// Subscription is the v2 subscription resource. Its version is sent in the ETag header.
+type Subscription struct {
+	ID          int              `json:"id"`
+	Email       string           `json:"email"`
+	City        string           `json:"city"`
+	Frequency   domain.Frequency `json:"frequency"`
+	IsConfirmed bool             `json:"is_confirmed"`
+	Channel     domain.Channel   `json:"channel"`
+	Webhook     *Webhook         `json:"webhook,omitempty"`
+}
+
+// Webhook describes the endpoint of a subscription on the webhook channel. The secret is only
+// sent once, when the subscription is created.
+type Webhook struct {
+	URL        string     `json:"url"`
+	Secret     string     `json:"secret,omitempty"`
+	Enabled    bool       `json:"enabled"`
+	Failures   int        `json:"failures"`
+	LastError  string     `json:"last_error,omitempty"`
+	DisabledAt *time.Time `json:"disabled_at,omitempty"`
+}
+
+type WebhookPing struct {
+	Delivered  bool   `json:"delivered"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+type SubscriptionList struct {
+	Items      []Subscription `json:"items"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+func NewSubscription(sub domain.Subscription) Subscription {
+	channel := sub.Channel
+	if channel == "" {
+		channel = domain.ChannelEmail
+	}
+	return Subscription{
+		ID:          sub.ID,
+		Email:       sub.Email,
+		City:        sub.City,
+		Frequency:   sub.Frequency,
+		IsConfirmed: sub.IsConfirmed,
+		Channel:     channel,
+	}
+}
+
+func NewWebhook(hook domain.Webhook) *Webhook {
+	webhook := &Webhook{URL: hook.URL, Enabled: !hook.Disabled(), Failures: hook.Failures, LastError: hook.LastError}
+	if hook.Disabled() {
+		webhook.DisabledAt = &hook.DisabledAt
+	}
+	return webhook
+}
+
+func NewWebhookPing(ping domain.WebhookPing) WebhookPing {
+	return WebhookPing{
+		Delivered:  ping.Delivered,
+		StatusCode: ping.StatusCode,
+		Error:      ping.Error,
+		DurationMs: ping.Duration.Milliseconds(),
+	}
+}
+
+func NewSubscriptionList(page domain.SubscriptionCursorPage) SubscriptionList {
+	items := make([]Subscription, 0, len(page.Items))
+	for _, sub := range page.Items {
+		items = append(items, NewSubscription(sub))
+	}
+	return SubscriptionList{Items: items, NextCursor: page.NextCursor}
+}
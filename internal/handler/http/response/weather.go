@@ -0,0 +1,15 @@
+package response
+
+import "weather-api/internal/core/domain"
+
+// CityWeather is one result of a batch lookup or one update of a live stream, with either the
+// weather or the error.
+type CityWeather struct {
+	City    string          `json:"city"`
+	Weather *domain.Weather `json:"weather,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+type WeatherBatch struct {
+	Results []CityWeather `json:"results"`
+}
@@ -1,10 +1,10 @@
 package http
 
 import (
-	"errors"
-	"log"
+	"log/slog"
 	"net/http"
 	"weather-api/internal/core/domain"
+	"weather-api/internal/core/port"
 	"weather-api/internal/core/service"
 	"weather-api/internal/handler/http/request"
 
@@ -13,80 +13,83 @@ import (
 
 type SubscriptionHandler struct {
 	subscriptionService *service.SubscriptionService
+	botVerifier         port.BotVerifier
+	logger              *slog.Logger
 }
 
-func NewSubscriptionHandler(subscriptionService *service.SubscriptionService) *SubscriptionHandler {
-	return &SubscriptionHandler{subscriptionService: subscriptionService}
+// NewSubscriptionHandler creates the handler. botVerifier may be nil to accept subscribe
+// requests without bot checks.
+func NewSubscriptionHandler(subscriptionService *service.SubscriptionService, botVerifier port.BotVerifier, logger *slog.Logger) *SubscriptionHandler {
+	return &SubscriptionHandler{subscriptionService: subscriptionService, botVerifier: botVerifier, logger: logger}
 }
 
 func (h *SubscriptionHandler) Subscribe(c *gin.Context) {
 	var req request.SubscribeRequest
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		log.Printf("Invalid subscription request: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidInput.Error()})
+	if err := c.ShouldBind(&req); err != nil {
+		h.logger.InfoContext(c, "Invalid subscription request", "error", err)
+		writeInvalidInput(c, "", nil)
 		return
 	}
 
-	log.Printf("Received subscription request for city: %s, frequency: %s", req.City, req.Frequency)
+	h.logger.InfoContext(c, "Received subscription request", "city", req.City, "frequency", req.Frequency)
 
-	if req.Frequency != domain.FrequencyDaily && req.Frequency != domain.FrequencyHourly {
-		log.Printf("Invalid frequency in subscription request: %s", req.Frequency)
-		c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidInput.Error()})
+	if !verifyHuman(c, h.botVerifier, req, h.logger) {
 		return
 	}
 
 	_, err := h.subscriptionService.Subscribe(c, req.Email, req.City, req.Frequency)
 	if err != nil {
-		log.Printf("Failed to process subscription: %v", err)
-		switch {
-		case errors.Is(err, domain.ErrEmailAlreadySubscribed):
-			c.JSON(http.StatusConflict, gin.H{"error": domain.ErrEmailAlreadySubscribed.Error()})
-		case errors.Is(err, domain.ErrCityNotFound):
-			c.JSON(http.StatusNotFound, gin.H{"error": "City not found"})
-		}
+		h.logger.ErrorContext(c, "Failed to process subscription", "error", err)
+		writeError(c, err)
 		return
 	}
-	log.Printf("Successfully processed subscription request")
+	h.logger.InfoContext(c, "Successfully processed subscription request")
 	c.JSON(http.StatusOK, gin.H{"message": "Subscription successful. Confirmation email sent."})
 }
 
 func (h *SubscriptionHandler) Confirm(c *gin.Context) {
 	token := c.Param("token")
-	log.Printf("Received confirmation request")
+	h.logger.InfoContext(c, "Received confirmation request")
 
 	if err := h.subscriptionService.Confirm(c, token); err != nil {
-		log.Printf("Failed to confirm subscription: %v", err)
-		switch {
-		case errors.Is(err, domain.ErrInvalidToken):
-			c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidToken.Error()})
-		case errors.Is(err, domain.ErrTokenNotFound):
-			c.JSON(http.StatusNotFound, gin.H{"error": domain.ErrTokenNotFound.Error()})
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		}
+		h.logger.ErrorContext(c, "Failed to confirm subscription", "error", err)
+		writeError(c, err)
 		return
 	}
-	log.Printf("Successfully confirmed subscription")
+	h.logger.InfoContext(c, "Successfully confirmed subscription")
 	c.JSON(http.StatusOK, gin.H{"message": "Subscription confirmed"})
 }
 
 func (h *SubscriptionHandler) Unsubscribe(c *gin.Context) {
 	token := c.Param("token")
-	log.Printf("Received unsubscribe request")
+	h.logger.InfoContext(c, "Received unsubscribe request")
 
 	if err := h.subscriptionService.Unsubscribe(c, token); err != nil {
-		log.Printf("Failed to unsubscribe: %v", err)
-		switch {
-		case errors.Is(err, domain.ErrInvalidToken):
-			c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidToken.Error()})
-		case errors.Is(err, domain.ErrTokenNotFound):
-			c.JSON(http.StatusNotFound, gin.H{"error": domain.ErrTokenNotFound.Error()})
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		}
+		h.logger.ErrorContext(c, "Failed to unsubscribe", "error", err)
+		writeError(c, err)
 		return
 	}
-	log.Printf("Successfully processed unsubscribe request")
+	h.logger.InfoContext(c, "Successfully processed unsubscribe request")
 	c.JSON(http.StatusOK, gin.H{"message": "Unsubscribed"})
 }
+
+// verifyHuman runs the bot checks on a subscribe request and answers it if they fail.
+func verifyHuman(c *gin.Context, verifier port.BotVerifier, req request.SubscribeRequest, logger *slog.Logger) bool {
+	if verifier == nil {
+		return true
+	}
+	err := verifier.Verify(c, domain.BotCheck{
+		Honeypot:     req.Website,
+		Challenge:    req.Challenge,
+		Nonce:        req.Nonce,
+		CaptchaToken: req.CaptchaToken,
+		ClientIP:     c.ClientIP(),
+	})
+	if err != nil {
+		logger.WarnContext(c, "Rejected subscription request from suspected bot", "error", err)
+		writeError(c, err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,246 @@
+package http
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/port"
+	"weather-api/internal/core/service"
+	"weather-api/internal/handler/http/request"
+	"weather-api/internal/handler/http/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SubscriptionResourceHandler serves subscriptions as resources under /api/v2. Single
+// subscriptions are accessed with a token from the subscriber's emails as bearer token, and
+// carry their version as ETag for conditional requests.
+type SubscriptionResourceHandler struct {
+	subscriptionService *service.SubscriptionService
+	adminService        *service.AdminService
+	webhooks            *service.WebhookChannel
+	botVerifier         port.BotVerifier
+	logger              *slog.Logger
+}
+
+// NewSubscriptionResourceHandler creates the handler. botVerifier may be nil to accept new
+// subscriptions without bot checks.
+func NewSubscriptionResourceHandler(subscriptionService *service.SubscriptionService, adminService *service.AdminService, webhooks *service.WebhookChannel, botVerifier port.BotVerifier, logger *slog.Logger) *SubscriptionResourceHandler {
+	return &SubscriptionResourceHandler{subscriptionService: subscriptionService, adminService: adminService, webhooks: webhooks, botVerifier: botVerifier, logger: logger}
+}
+
+// Create answers with the webhook secret, if any; it can't be retrieved later.
+func (h *SubscriptionResourceHandler) Create(c *gin.Context) {
+	var req request.CreateSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.InfoContext(c, "Invalid subscription request", "error", err)
+		writeInvalidInput(c, "", nil)
+		return
+	}
+	if !verifyHuman(c, h.botVerifier, req.SubscribeRequest, h.logger) {
+		return
+	}
+
+	sub, err := h.subscriptionService.Create(c, req.Email, req.City, req.Frequency, req.Channel, req.WebhookURL)
+	if err != nil {
+		h.logger.ErrorContext(c, "Failed to process subscription", "error", err)
+		writeError(c, err)
+		return
+	}
+	var webhook *response.Webhook
+	if sub.Webhook != nil {
+		webhook = response.NewWebhook(*sub.Webhook)
+		webhook.Secret = sub.Webhook.Secret
+	}
+	c.Header("Location", c.Request.URL.Path+"/"+strconv.Itoa(sub.ID))
+	writeSubscription(c, http.StatusCreated, sub, webhook)
+}
+
+func (h *SubscriptionResourceHandler) List(c *gin.Context) {
+	filter, ok := subscriptionFilter(c)
+	if !ok {
+		return
+	}
+	page, err := h.adminService.ListSubscriptionsByCursor(c, filter, c.Query("cursor"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	if page.NextCursor != "" {
+		next := *c.Request.URL
+		query := next.Query()
+		query.Set("cursor", page.NextCursor)
+		next.RawQuery = query.Encode()
+		c.Header("Link", fmt.Sprintf(`<%s>; rel="next"`, next.RequestURI()))
+	}
+	c.JSON(http.StatusOK, response.NewSubscriptionList(page))
+}
+
+func (h *SubscriptionResourceHandler) Get(c *gin.Context) {
+	id, ok := subscriptionID(c)
+	if !ok {
+		return
+	}
+	sub, err := h.subscriptionService.Get(c, id, bearerToken(c))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	if etag := subscriptionETag(sub); etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Header("ETag", etag)
+		c.Status(http.StatusNotModified)
+		return
+	}
+	var webhook *response.Webhook
+	if sub.Channel == domain.ChannelWebhook {
+		hook, err := h.webhooks.Webhook(c, sub.ID)
+		if err != nil {
+			writeError(c, err)
+			return
+		}
+		webhook = response.NewWebhook(hook)
+	}
+	writeSubscription(c, http.StatusOK, sub, webhook)
+}
+
+// Update applies a merge patch. It requires If-Match, so that a client can't overwrite changes
+// it has not seen.
+func (h *SubscriptionResourceHandler) Update(c *gin.Context) {
+	id, ok := subscriptionID(c)
+	if !ok {
+		return
+	}
+	ifMatch := c.GetHeader("If-Match")
+	if ifMatch == "" {
+		writeError(c, domain.ErrPreconditionRequired)
+		return
+	}
+	var req request.UpdateSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.InfoContext(c, "Invalid subscription update", "error", err)
+		writeInvalidInput(c, "", nil)
+		return
+	}
+
+	sub, err := h.subscriptionService.Update(c, id, bearerToken(c), ifMatchVersion(ifMatch), domain.SubscriptionPatch{
+		City:      req.City,
+		Frequency: req.Frequency,
+	})
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	writeSubscription(c, http.StatusOK, sub, nil)
+}
+
+func (h *SubscriptionResourceHandler) Delete(c *gin.Context) {
+	id, ok := subscriptionID(c)
+	if !ok {
+		return
+	}
+	version := 0
+	if ifMatch := c.GetHeader("If-Match"); ifMatch != "" {
+		version = ifMatchVersion(ifMatch)
+	}
+	if err := h.subscriptionService.Delete(c, id, bearerToken(c), version); err != nil {
+		writeError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *SubscriptionResourceHandler) Confirm(c *gin.Context) {
+	id, ok := subscriptionID(c)
+	if !ok {
+		return
+	}
+	var req request.ConfirmSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.InfoContext(c, "Invalid confirmation request", "error", err)
+		writeInvalidInput(c, "", nil)
+		return
+	}
+	sub, err := h.subscriptionService.ConfirmByID(c, id, req.Token)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	writeSubscription(c, http.StatusOK, sub, nil)
+}
+
+// Ping sends a test delivery to the webhook of the subscription and reports how it went. The
+// webhook state is only changed by a delivered ping, which enables a disabled webhook again.
+func (h *SubscriptionResourceHandler) Ping(c *gin.Context) {
+	id, ok := subscriptionID(c)
+	if !ok {
+		return
+	}
+	sub, err := h.subscriptionService.Get(c, id, bearerToken(c))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	ping, err := h.webhooks.Ping(c, sub)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, response.NewWebhookPing(ping))
+}
+
+// writeSubscription writes the subscription with its webhook, when the caller loaded it.
+func writeSubscription(c *gin.Context, status int, sub domain.Subscription, webhook *response.Webhook) {
+	body := response.NewSubscription(sub)
+	body.Webhook = webhook
+	c.Header("ETag", subscriptionETag(sub))
+	c.JSON(status, body)
+}
+
+func subscriptionETag(sub domain.Subscription) string {
+	return `"` + strconv.Itoa(sub.Version) + `"`
+}
+
+// ifMatchVersion returns the version named by an If-Match header: 0 for "*", which matches any
+// version, and -1 for values that can't match, such as weak or foreign ETags.
+func ifMatchVersion(header string) int {
+	header = strings.TrimSpace(header)
+	if header == "*" {
+		return 0
+	}
+	unquoted, ok := strings.CutPrefix(header, `"`)
+	if !ok {
+		return -1
+	}
+	unquoted, ok = strings.CutSuffix(unquoted, `"`)
+	if !ok {
+		return -1
+	}
+	version, err := strconv.Atoi(unquoted)
+	if err != nil || version <= 0 {
+		return -1
+	}
+	return version
+}
+
+// etagMatches reports whether an If-None-Match header names etag, using weak comparison.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func bearerToken(c *gin.Context) string {
+	token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || strings.TrimSpace(token) == "" {
+		c.Header("WWW-Authenticate", `Bearer realm="subscription"`)
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
@@ -0,0 +1,49 @@
+package http
+
+import (
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/service"
+	"weather-api/internal/handler/http/request"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TelegramSecretHeader carries the secret_token that the bot's webhook was registered with.
+const TelegramSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+type TelegramHandler struct {
+	bot    *service.TelegramBot
+	secret string
+	logger *slog.Logger
+}
+
+func NewTelegramHandler(bot *service.TelegramBot, secret string, logger *slog.Logger) *TelegramHandler {
+	return &TelegramHandler{bot: bot, secret: secret, logger: logger}
+}
+
+// Webhook receives updates from Telegram. Every authentic update is acknowledged with 200, even
+// when the reply failed, since Telegram would otherwise deliver it again and again.
+func (h *TelegramHandler) Webhook(c *gin.Context) {
+	if subtle.ConstantTimeCompare([]byte(c.GetHeader(TelegramSecretHeader)), []byte(h.secret)) != 1 {
+		writeError(c, domain.ErrUnauthorized)
+		return
+	}
+	var update request.TelegramUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		h.logger.InfoContext(c, "Invalid Telegram update", "error", err)
+		writeInvalidInput(c, "", nil)
+		return
+	}
+
+	if update.Message != nil && update.Message.Text != "" {
+		chatID := strconv.FormatInt(update.Message.Chat.ID, 10)
+		if err := h.bot.HandleMessage(c, chatID, update.Message.Text); err != nil {
+			h.logger.WarnContext(c, "Failed to handle Telegram update", "update_id", update.UpdateID, "error", err)
+		}
+	}
+	c.Status(http.StatusOK)
+}
@@ -1,34 +1,147 @@
 package http
 
 import (
-	"errors"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"net/http"
+	"strconv"
+	"time"
 	"weather-api/internal/core/domain"
 	"weather-api/internal/core/service"
+	"weather-api/internal/handler/http/request"
+	"weather-api/internal/handler/http/response"
 
 	"github.com/gin-gonic/gin"
 )
 
+// weatherVary lists the request headers a weather response may depend on. Descriptions are not
+// localized yet; listing Accept-Language keeps shared caches from mixing languages once they
+// are. There is no choice of units: temperatures are always in °C.
+const weatherVary = "Accept-Language"
+
+// providerUpdateInterval is how often weatherapi.com publishes new current conditions.
+const providerUpdateInterval = 15 * time.Minute
+
 type WeatherHandler struct {
 	weatherService *service.WeatherService
+	now            func() time.Time
 }
 
 func NewWeatherHandler(weatherService *service.WeatherService) *WeatherHandler {
-	return &WeatherHandler{weatherService: weatherService}
+	return &WeatherHandler{weatherService: weatherService, now: time.Now}
 }
 
+// GetWeather answers with the current weather, cacheable by browsers and CDNs until our own
+// cached lookup is refreshed or the provider is due to publish a newer observation, whichever
+// comes first. The observation time is sent as Last-Modified and a hash of the body as ETag, so
+// that clients can revalidate with a conditional request.
 func (h *WeatherHandler) GetWeather(c *gin.Context) {
 	city := c.Query("city")
 	if city == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "City parameter is required"})
+		writeInvalidInput(c, "City parameter is required", map[string]string{"city": "required"})
+		return
+	}
+	weather, err := h.weatherService.GetWeather(c, city)
+	if err != nil {
+		writeError(c, err)
 		return
 	}
-	weather, err := h.weatherService.GetWeather(city)
+	body, err := json.Marshal(weather)
 	if err != nil {
-		if errors.Is(err, domain.ErrCityNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "City not found"})
+		writeError(c, err)
+		return
+	}
+
+	etag := bodyETag(body)
+	c.Header("ETag", etag)
+	c.Header("Vary", weatherVary)
+	if maxAge := weatherMaxAge(weather, h.now()); maxAge > 0 {
+		c.Header("Cache-Control", "public, max-age="+strconv.Itoa(maxAge))
+	} else {
+		// Not cached, or served from an expired entry while the provider is unavailable.
+		c.Header("Cache-Control", "no-cache")
+	}
+	if !weather.ObservedAt.IsZero() {
+		c.Header("Last-Modified", weather.ObservedAt.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(c.Request, etag, weather.ObservedAt) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
+
+// weatherMaxAge returns how many seconds weather stays fresh: until it expires from our cache,
+// or until the provider's next observation if that is due earlier. Once the next observation is
+// overdue only the cache counts, and an expired entry is never fresh.
+func weatherMaxAge(weather domain.Weather, now time.Time) int {
+	expires := weather.ExpiresAt
+	if !weather.ObservedAt.IsZero() {
+		next := weather.ObservedAt.Add(providerUpdateInterval)
+		if next.After(now) && (expires.IsZero() || next.Before(expires)) {
+			expires = next
+		}
+	}
+	return int(expires.Sub(now).Seconds())
+}
+
+// GetWeatherBatch looks up several cities, given as repeated city query parameters or as a JSON
+// body on POST. It answers 200 as long as the request itself is valid, with an error in place of
+// the weather for each city whose lookup failed.
+func (h *WeatherHandler) GetWeatherBatch(c *gin.Context) {
+	cities := c.QueryArray("city")
+	if c.Request.Method == http.MethodPost {
+		var req request.WeatherBatchRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			writeInvalidInput(c, "", nil)
 			return
 		}
+		cities = req.Cities
+	}
+
+	results, err := h.weatherService.GetWeatherBatch(c, cities)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	batch := response.WeatherBatch{Results: make([]response.CityWeather, 0, len(results))}
+	for _, result := range results {
+		batch.Results = append(batch.Results, cityWeather(c, result))
+	}
+	c.JSON(http.StatusOK, batch)
+}
+
+func cityWeather(c *gin.Context, result domain.CityWeather) response.CityWeather {
+	item := response.CityWeather{City: result.City}
+	if result.Err != nil {
+		item.Error = itemError(c, result.Err)
+	} else {
+		item.Weather = &result.Weather
+	}
+	return item
+}
+
+// bodyETag returns a strong entity tag for a response body.
+func bodyETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// notModified evaluates If-None-Match, or If-Modified-Since when it is absent, as RFC 9110
+// prescribes.
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if header := r.Header.Get("If-None-Match"); header != "" {
+		return etagMatches(header, etag)
+	}
+	if lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
 	}
-	c.JSON(http.StatusOK, weather)
+	return !lastModified.Truncate(time.Second).After(since)
 }
@@ -0,0 +1,186 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/service"
+	"weather-api/internal/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWeatherHandler_CachingHeaders(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	observedAt := now.Add(-10 * time.Minute)
+	fresh := domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny", ObservedAt: observedAt, ExpiresAt: now.Add(2 * time.Minute)}
+	etag := etagOf(t, fresh)
+
+	tests := []struct {
+		name                 string
+		weather              domain.Weather
+		headers              map[string]string
+		expectedStatus       int
+		expectedCacheControl string
+		expectedLastModified string
+	}{
+		{
+			name:                 "cached weather",
+			weather:              fresh,
+			expectedStatus:       http.StatusOK,
+			expectedCacheControl: "public, max-age=120",
+			expectedLastModified: "Sun, 01 Jun 2025 11:50:00 GMT",
+		},
+		{
+			name:                 "stale weather while the provider is down",
+			weather:              domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny", ObservedAt: observedAt, ExpiresAt: now.Add(-time.Minute)},
+			expectedStatus:       http.StatusOK,
+			expectedCacheControl: "no-cache",
+			expectedLastModified: "Sun, 01 Jun 2025 11:50:00 GMT",
+		},
+		{
+			name:                 "next observation due before the cache expires",
+			weather:              domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny", ObservedAt: now.Add(-14 * time.Minute), ExpiresAt: now.Add(5 * time.Minute)},
+			expectedStatus:       http.StatusOK,
+			expectedCacheControl: "public, max-age=60",
+			expectedLastModified: "Sun, 01 Jun 2025 11:46:00 GMT",
+		},
+		{
+			name:                 "next observation overdue",
+			weather:              domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny", ObservedAt: now.Add(-20 * time.Minute), ExpiresAt: now.Add(5 * time.Minute)},
+			expectedStatus:       http.StatusOK,
+			expectedCacheControl: "public, max-age=300",
+			expectedLastModified: "Sun, 01 Jun 2025 11:40:00 GMT",
+		},
+		{
+			name:                 "uncached weather with observation time",
+			weather:              domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny", ObservedAt: now.Add(-5 * time.Minute)},
+			expectedStatus:       http.StatusOK,
+			expectedCacheControl: "public, max-age=600",
+			expectedLastModified: "Sun, 01 Jun 2025 11:55:00 GMT",
+		},
+		{
+			name:                 "uncached weather without observation time",
+			weather:              domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny"},
+			expectedStatus:       http.StatusOK,
+			expectedCacheControl: "no-cache",
+		},
+		{
+			name:                 "matching If-None-Match",
+			weather:              fresh,
+			headers:              map[string]string{"If-None-Match": `"a", W/` + etag},
+			expectedStatus:       http.StatusNotModified,
+			expectedCacheControl: "public, max-age=120",
+			expectedLastModified: "Sun, 01 Jun 2025 11:50:00 GMT",
+		},
+		{
+			name:                 "changed weather",
+			weather:              fresh,
+			headers:              map[string]string{"If-None-Match": `"a"`, "If-Modified-Since": "Sun, 01 Jun 2025 11:55:00 GMT"},
+			expectedStatus:       http.StatusOK,
+			expectedCacheControl: "public, max-age=120",
+			expectedLastModified: "Sun, 01 Jun 2025 11:50:00 GMT",
+		},
+		{
+			name:                 "not modified since",
+			weather:              fresh,
+			headers:              map[string]string{"If-Modified-Since": "Sun, 01 Jun 2025 11:50:00 GMT"},
+			expectedStatus:       http.StatusNotModified,
+			expectedCacheControl: "public, max-age=120",
+			expectedLastModified: "Sun, 01 Jun 2025 11:50:00 GMT",
+		},
+		{
+			name:                 "modified since",
+			weather:              fresh,
+			headers:              map[string]string{"If-Modified-Since": "Sun, 01 Jun 2025 11:45:00 GMT"},
+			expectedStatus:       http.StatusOK,
+			expectedCacheControl: "public, max-age=120",
+			expectedLastModified: "Sun, 01 Jun 2025 11:50:00 GMT",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveWeather(t, now, tt.weather, nil, tt.headers)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectedCacheControl, rec.Header().Get("Cache-Control"))
+			assert.Equal(t, tt.expectedLastModified, rec.Header().Get("Last-Modified"))
+			assert.Equal(t, "Accept-Language", rec.Header().Get("Vary"))
+			assert.NotEmpty(t, rec.Header().Get("ETag"))
+			if tt.expectedStatus == http.StatusNotModified {
+				assert.Empty(t, rec.Body.String())
+			} else {
+				assert.Contains(t, rec.Body.String(), `"temperature":20.5`)
+			}
+		})
+	}
+}
+
+func TestWeatherHandler_ETagFollowsContent(t *testing.T) {
+	now := time.Now()
+	sunny := domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny", ExpiresAt: now.Add(time.Minute)}
+	rainy := sunny
+	rainy.Description = "Rain"
+
+	assert.Equal(t, etagOf(t, sunny), etagOf(t, sunny))
+	assert.NotEqual(t, etagOf(t, sunny), etagOf(t, rainy))
+}
+
+func TestWeatherHandler_ErrorsAreNotCached(t *testing.T) {
+	rec := serveWeather(t, time.Now(), domain.Weather{}, domain.ErrCityNotFound, nil)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Empty(t, rec.Header().Get("Cache-Control"))
+	assert.Empty(t, rec.Header().Get("ETag"))
+}
+
+func serveWeather(t *testing.T, now time.Time, weather domain.Weather, err error, headers map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	provider := &mocks.MockWeatherService{}
+	provider.On("GetWeather", mock.Anything, "Kyiv").Return(weather, err)
+	handler := NewWeatherHandler(service.NewWeatherService(provider, 3, 2))
+	handler.now = func() time.Time { return now }
+
+	r := gin.New()
+	r.GET("/api/weather", handler.GetWeather)
+	req := httptest.NewRequest(http.MethodGet, "/api/weather?city=Kyiv", nil)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func etagOf(t *testing.T, weather domain.Weather) string {
+	t.Helper()
+	return serveWeather(t, time.Now(), weather, nil, nil).Header().Get("ETag")
+}
+
+func TestWeatherHandler_BatchReturnsPartialResults(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	provider := &mocks.MockWeatherService{}
+	provider.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny"}, nil)
+	provider.On("GetWeather", mock.Anything, "Atlantis").Return(domain.Weather{}, domain.ErrCityNotFound)
+	provider.On("GetWeather", mock.Anything, "Lviv").Return(domain.Weather{}, errors.New("connection reset"))
+	handler := NewWeatherHandler(service.NewWeatherService(provider, 3, 2))
+
+	r := gin.New()
+	r.GET("/api/weather/batch", handler.GetWeatherBatch)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/weather/batch?city=Kyiv&city=Atlantis&city=Lviv", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"results":[
+		{"city":"Kyiv","weather":{"temperature":20.5,"humidity":60,"description":"Sunny"}},
+		{"city":"Atlantis","error":{"code":"city_not_found","message":"City not found"}},
+		{"city":"Lviv","error":{"code":"internal_error","message":"Internal server error"}}
+	]}`, rec.Body.String())
+}
@@ -0,0 +1,146 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// streamWriteWait bounds every write to a stream. It replaces the server's WriteTimeout, which
+// would otherwise cut each stream off a few seconds after it started.
+const streamWriteWait = 10 * time.Second
+
+type WeatherStreamHandler struct {
+	streamer  *service.WeatherStreamer
+	heartbeat time.Duration
+	// The zero Upgrader only accepts WebSocket handshakes from pages served by this host.
+	upgrader websocket.Upgrader
+	logger   *slog.Logger
+}
+
+func NewWeatherStreamHandler(streamer *service.WeatherStreamer, heartbeat time.Duration, logger *slog.Logger) *WeatherStreamHandler {
+	return &WeatherStreamHandler{streamer: streamer, heartbeat: heartbeat, logger: logger}
+}
+
+// Stream pushes the weather of a city as server-sent events, or over a WebSocket when the
+// client asks for an upgrade. Both send a heartbeat so that proxies don't close an idle
+// connection and clients that went away are noticed.
+func (h *WeatherStreamHandler) Stream(c *gin.Context) {
+	city := c.Query("city")
+	if city == "" {
+		writeInvalidInput(c, "City parameter is required", map[string]string{"city": "required"})
+		return
+	}
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	updates, err := h.streamer.Subscribe(ctx, city)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	if c.IsWebsocket() {
+		h.streamWebSocket(c, cancel, updates)
+		return
+	}
+	h.streamEvents(c, updates)
+}
+
+// streamEvents sends every update as a "weather" or "error" event, whose data is the same JSON
+// as a result of the batch endpoint, and a comment line as heartbeat.
+func (h *WeatherStreamHandler) streamEvents(c *gin.Context, updates <-chan domain.CityWeather) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-store")
+	// Keeps nginx from buffering the events.
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	rc := http.NewResponseController(c.Writer)
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+	for {
+		var chunk string
+		select {
+		case update, ok := <-updates:
+			if !ok {
+				return
+			}
+			item := cityWeather(c, update)
+			data, err := json.Marshal(item)
+			if err != nil {
+				h.logger.ErrorContext(c, "Failed to encode weather event", "error", err)
+				return
+			}
+			event := "weather"
+			if item.Error != nil {
+				event = "error"
+			}
+			chunk = "event: " + event + "\ndata: " + string(data) + "\n\n"
+		case <-heartbeat.C:
+			chunk = ": heartbeat\n\n"
+		}
+
+		// Recorders in tests can't set deadlines; real connections can.
+		_ = rc.SetWriteDeadline(time.Now().Add(streamWriteWait))
+		if _, err := io.WriteString(c.Writer, chunk); err != nil {
+			return
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// streamWebSocket sends every update as a JSON text message and a ping as heartbeat. Messages
+// from the client are discarded; failing to read one, or a missing pong, ends the stream.
+func (h *WeatherStreamHandler) streamWebSocket(c *gin.Context, cancel context.CancelFunc, updates <-chan domain.CityWeather) {
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// The upgrader has already answered with an error.
+		return
+	}
+	defer conn.Close()
+
+	conn.SetReadLimit(512)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * h.heartbeat))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(2 * h.heartbeat))
+	})
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case update, ok := <-updates:
+			if !ok {
+				closing := websocket.FormatCloseMessage(websocket.CloseGoingAway, "")
+				_ = conn.WriteControl(websocket.CloseMessage, closing, time.Now().Add(streamWriteWait))
+				return
+			}
+			_ = conn.SetWriteDeadline(time.Now().Add(streamWriteWait))
+			err = conn.WriteJSON(cityWeather(c, update))
+		case <-heartbeat.C:
+			err = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(streamWriteWait))
+		}
+		if err != nil {
+			return
+		}
+	}
+}
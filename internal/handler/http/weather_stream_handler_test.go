@@ -0,0 +1,108 @@
+package http
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"weather-api/internal/core/domain"
+	"weather-api/internal/core/service"
+	"weather-api/internal/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newStreamServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	provider := &mocks.MockWeatherService{}
+	provider.On("GetWeather", mock.Anything, "Kyiv").Return(domain.Weather{Temperature: 20.5, Humidity: 60, Description: "Sunny"}, nil)
+	provider.On("GetWeather", mock.Anything, "Atlantis").Return(domain.Weather{}, domain.ErrCityNotFound)
+	streamer := service.NewWeatherStreamer(provider, time.Hour, 10, testLogger)
+	handler := NewWeatherStreamHandler(streamer, 20*time.Millisecond, testLogger)
+
+	r := gin.New()
+	r.GET("/api/weather/stream", handler.Stream)
+	server := httptest.NewServer(r)
+	t.Cleanup(func() {
+		streamer.Close()
+		server.Close()
+	})
+	return server
+}
+
+func TestWeatherStreamHandler_ServerSentEvents(t *testing.T) {
+	server := newStreamServer(t)
+
+	resp, err := http.Get(server.URL + "/api/weather/stream?city=Kyiv")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	reader := bufio.NewReader(resp.Body)
+	var lines []string
+	for len(lines) < 5 {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		lines = append(lines, strings.TrimSuffix(line, "\n"))
+	}
+	assert.Equal(t, []string{
+		"event: weather",
+		`data: {"city":"Kyiv","weather":{"temperature":20.5,"humidity":60,"description":"Sunny"}}`,
+		"",
+		": heartbeat",
+		"",
+	}, lines)
+}
+
+func TestWeatherStreamHandler_WebSocket(t *testing.T) {
+	server := newStreamServer(t)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/api/weather/stream?city=Kyiv", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	pinged := make(chan struct{}, 1)
+	conn.SetPingHandler(func(string) error {
+		select {
+		case pinged <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+
+	_, message, err := conn.ReadMessage()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"city":"Kyiv","weather":{"temperature":20.5,"humidity":60,"description":"Sunny"}}`, string(message))
+
+	// Pings are handled while reading.
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+	select {
+	case <-pinged:
+	case <-time.After(time.Second):
+		t.Fatal("no heartbeat")
+	}
+}
+
+func TestWeatherStreamHandler_UnknownCityFailsBeforeStreaming(t *testing.T) {
+	server := newStreamServer(t)
+
+	resp, err := http.Get(server.URL + "/api/weather/stream?city=Atlantis")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "application/json")
+}
@@ -3,6 +3,7 @@ package mocks
 import (
 	"context"
 	"github.com/stretchr/testify/mock"
+	"time"
 	"weather-api/internal/core/domain"
 )
 
@@ -15,9 +16,9 @@ func (m *MockSubscriptionRepository) GetSubscriptionsByFrequency(ctx context.Con
 	return args.Get(0).([]domain.Subscription), args.Error(1)
 }
 
-func (m *MockSubscriptionRepository) CreateSubscription(ctx context.Context, sub domain.Subscription) error {
+func (m *MockSubscriptionRepository) CreateSubscription(ctx context.Context, sub domain.Subscription) (int, error) {
 	args := m.Called(ctx, sub)
-	return args.Error(0)
+	return args.Int(0), args.Error(1)
 }
 
 func (m *MockSubscriptionRepository) GetSubscriptionByToken(ctx context.Context, token string) (domain.Subscription, error) {
@@ -25,6 +26,16 @@ func (m *MockSubscriptionRepository) GetSubscriptionByToken(ctx context.Context,
 	return args.Get(0).(domain.Subscription), args.Error(1)
 }
 
+func (m *MockSubscriptionRepository) GetSubscriptionByID(ctx context.Context, id int) (domain.Subscription, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.Subscription), args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) GetSubscriptionByAddress(ctx context.Context, channel domain.Channel, address string) (domain.Subscription, error) {
+	args := m.Called(ctx, channel, address)
+	return args.Get(0).(domain.Subscription), args.Error(1)
+}
+
 func (m *MockSubscriptionRepository) UpdateSubscription(ctx context.Context, sub domain.Subscription) error {
 	args := m.Called(ctx, sub)
 	return args.Error(0)
@@ -45,12 +56,56 @@ func (m *MockSubscriptionRepository) IsTokenExists(ctx context.Context, token st
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockSubscriptionRepository) ListSubscriptions(ctx context.Context, filter domain.SubscriptionFilter) (domain.SubscriptionPage, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(domain.SubscriptionPage), args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) GetSubscriptionCounts(ctx context.Context) ([]domain.SubscriptionCount, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.SubscriptionCount), args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) UpdateSubscriptionByID(ctx context.Context, sub domain.Subscription) (domain.Subscription, error) {
+	args := m.Called(ctx, sub)
+	return args.Get(0).(domain.Subscription), args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) DeleteSubscriptionByID(ctx context.Context, id, version int) error {
+	args := m.Called(ctx, id, version)
+	return args.Error(0)
+}
+
+func (m *MockSubscriptionRepository) ListSubscriptionsAfter(ctx context.Context, filter domain.SubscriptionFilter) ([]domain.Subscription, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]domain.Subscription), args.Error(1)
+}
+
+type MockDeliveryRepository struct {
+	mock.Mock
+}
+
+func (m *MockDeliveryRepository) RecordDelivery(ctx context.Context, delivery domain.Delivery) error {
+	args := m.Called(ctx, delivery)
+	return args.Error(0)
+}
+
+func (m *MockDeliveryRepository) GetDeliveryStats(ctx context.Context, since time.Time) ([]domain.DeliveryStats, error) {
+	args := m.Called(ctx, since)
+	return args.Get(0).([]domain.DeliveryStats), args.Error(1)
+}
+
+func (m *MockDeliveryRepository) GetDeliveredSubscriptionIDs(ctx context.Context, runID int) (map[int]bool, error) {
+	args := m.Called(ctx, runID)
+	return args.Get(0).(map[int]bool), args.Error(1)
+}
+
 type MockWeatherService struct {
 	mock.Mock
 }
 
-func (m *MockWeatherService) GetWeather(city string) (domain.Weather, error) {
-	args := m.Called(city)
+func (m *MockWeatherService) GetWeather(ctx context.Context, city string) (domain.Weather, error) {
+	args := m.Called(ctx, city)
 	return args.Get(0).(domain.Weather), args.Error(1)
 }
 
@@ -71,3 +126,183 @@ func (m *MockTokenService) GenerateToken() (string, error) {
 	args := m.Called()
 	return args.String(0), args.Error(1)
 }
+
+type MockJobRunRepository struct {
+	mock.Mock
+}
+
+func (m *MockJobRunRepository) AcquireLock(ctx context.Context, job string) (func(), bool, error) {
+	args := m.Called(ctx, job)
+	release, _ := args.Get(0).(func())
+	return release, args.Bool(1), args.Error(2)
+}
+
+func (m *MockJobRunRepository) ClaimRun(ctx context.Context, job string, scheduledTime time.Time, owner string) (domain.JobRun, bool, error) {
+	args := m.Called(ctx, job, scheduledTime, owner)
+	return args.Get(0).(domain.JobRun), args.Bool(1), args.Error(2)
+}
+
+func (m *MockJobRunRepository) FinishRun(ctx context.Context, id int, status domain.JobRunStatus, errMsg string) error {
+	args := m.Called(ctx, id, status, errMsg)
+	return args.Error(0)
+}
+
+func (m *MockJobRunRepository) GetLatestRuns(ctx context.Context) ([]domain.JobRun, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.JobRun), args.Error(1)
+}
+
+func (m *MockJobRunRepository) GetRun(ctx context.Context, id int) (domain.JobRun, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(domain.JobRun), args.Error(1)
+}
+
+func (m *MockJobRunRepository) SaveReport(ctx context.Context, id int, report domain.RunReport) error {
+	args := m.Called(ctx, id, report)
+	return args.Error(0)
+}
+
+type MockHealthChecker struct {
+	mock.Mock
+}
+
+func (m *MockHealthChecker) Name() string {
+	args := m.Called()
+	return args.String(0)
+}
+
+func (m *MockHealthChecker) Check(ctx context.Context) (map[string]any, error) {
+	args := m.Called(ctx)
+	details, _ := args.Get(0).(map[string]any)
+	return details, args.Error(1)
+}
+
+type MockQuotaRepository struct {
+	mock.Mock
+}
+
+func (m *MockQuotaRepository) IncrementUsage(ctx context.Context, provider string, periodStart time.Time) (int, error) {
+	args := m.Called(ctx, provider, periodStart)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockQuotaRepository) GetUsage(ctx context.Context, provider string, periodStart time.Time) (int, error) {
+	args := m.Called(ctx, provider, periodStart)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockQuotaRepository) MarkAlerted(ctx context.Context, provider string, periodStart time.Time, percent int) (bool, error) {
+	args := m.Called(ctx, provider, periodStart, percent)
+	return args.Bool(0), args.Error(1)
+}
+
+type MockMXResolver struct {
+	mock.Mock
+}
+
+func (m *MockMXResolver) AcceptsMail(ctx context.Context, domain string) (bool, error) {
+	args := m.Called(ctx, domain)
+	return args.Bool(0), args.Error(1)
+}
+
+type MockRateLimitStore struct {
+	mock.Mock
+}
+
+func (m *MockRateLimitStore) Take(ctx context.Context, key string, limit domain.RateLimit) (domain.RateLimitResult, error) {
+	args := m.Called(ctx, key, limit)
+	return args.Get(0).(domain.RateLimitResult), args.Error(1)
+}
+
+func (m *MockRateLimitStore) Prune(ctx context.Context, before time.Time) error {
+	args := m.Called(ctx, before)
+	return args.Error(0)
+}
+
+type MockNotificationChannel struct {
+	mock.Mock
+}
+
+func (m *MockNotificationChannel) Notify(ctx context.Context, sub domain.Subscription, notification domain.Notification) error {
+	args := m.Called(ctx, sub, notification)
+	return args.Error(0)
+}
+
+type MockWebhookSender struct {
+	mock.Mock
+}
+
+func (m *MockWebhookSender) Send(ctx context.Context, hook domain.Webhook, delivery domain.WebhookDelivery) error {
+	args := m.Called(ctx, hook, delivery)
+	return args.Error(0)
+}
+
+type MockTelegramSender struct {
+	mock.Mock
+}
+
+func (m *MockTelegramSender) SendMessage(ctx context.Context, chatID, text string) error {
+	args := m.Called(ctx, chatID, text)
+	return args.Error(0)
+}
+
+type MockSlackSender struct {
+	mock.Mock
+}
+
+func (m *MockSlackSender) PostMessage(ctx context.Context, webhookURL string, message []byte) error {
+	args := m.Called(ctx, webhookURL, message)
+	return args.Error(0)
+}
+
+func (m *MockSlackSender) AcceptsWebhookURL(webhookURL string) bool {
+	args := m.Called(webhookURL)
+	return args.Bool(0)
+}
+
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) GetWebhook(ctx context.Context, subscriptionID int) (domain.Webhook, error) {
+	args := m.Called(ctx, subscriptionID)
+	return args.Get(0).(domain.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) RecordWebhookSuccess(ctx context.Context, subscriptionID int) error {
+	args := m.Called(ctx, subscriptionID)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) RecordWebhookFailure(ctx context.Context, subscriptionID int, deliveryErr string, disableAfter int) (domain.Webhook, error) {
+	args := m.Called(ctx, subscriptionID, deliveryErr, disableAfter)
+	return args.Get(0).(domain.Webhook), args.Error(1)
+}
+
+type MockObservationRepository struct {
+	mock.Mock
+}
+
+func (m *MockObservationRepository) RecordObservation(ctx context.Context, observation domain.Observation) error {
+	args := m.Called(ctx, observation)
+	return args.Error(0)
+}
+
+func (m *MockObservationRepository) GetObservations(ctx context.Context, city string, limit int) ([]domain.Observation, error) {
+	args := m.Called(ctx, city, limit)
+	return args.Get(0).([]domain.Observation), args.Error(1)
+}
+
+func (m *MockObservationRepository) PruneObservations(ctx context.Context, before time.Time) error {
+	args := m.Called(ctx, before)
+	return args.Error(0)
+}
+
+type MockForecastService struct {
+	mock.Mock
+}
+
+func (m *MockForecastService) GetForecast(ctx context.Context, city string, days int) ([]domain.DailyForecast, error) {
+	args := m.Called(ctx, city, days)
+	return args.Get(0).([]domain.DailyForecast), args.Error(1)
+}
@@ -0,0 +1,49 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+)
+
+// BuildTelegramWeatherUpdate renders an update in Telegram's HTML markup. Chats unsubscribe with
+// a bot command rather than a link.
+func BuildTelegramWeatherUpdate(city string, temperature float64, humidity int, description string) string {
+	return fmt.Sprintf("<b>Weather in %s</b>\nTemp %.2f°C, Humidity %d%%, %s\n\nSend /unsubscribe to stop these updates.",
+		html.EscapeString(city), temperature, humidity, html.EscapeString(description))
+}
+
+// BuildSlackWeatherUpdate renders an update as a Slack message with Block Kit blocks; text is
+// the fallback for notifications.
+func BuildSlackWeatherUpdate(city string, temperature float64, humidity int, description, token string) []byte {
+	text := fmt.Sprintf("Weather in %s: Temp %.2f°C, Humidity %d%%, %s", slackEscaper.Replace(city), temperature, humidity, slackEscaper.Replace(description))
+	message := map[string]any{
+		"text": text,
+		"blocks": []any{
+			map[string]any{
+				"type": "header",
+				"text": map[string]any{"type": "plain_text", "text": "Weather in " + city},
+			},
+			map[string]any{
+				"type": "section",
+				"fields": []any{
+					map[string]any{"type": "mrkdwn", "text": fmt.Sprintf("*Temperature*\n%.2f°C", temperature)},
+					map[string]any{"type": "mrkdwn", "text": fmt.Sprintf("*Humidity*\n%d%%", humidity)},
+					map[string]any{"type": "mrkdwn", "text": "*Conditions*\n" + slackEscaper.Replace(description)},
+				},
+			},
+			map[string]any{
+				"type": "context",
+				"elements": []any{
+					map[string]any{"type": "mrkdwn", "text": fmt.Sprintf("<%s|Unsubscribe>", UnsubscribeURL(token))},
+				},
+			},
+		},
+	}
+	body, _ := json.Marshal(message)
+	return body
+}
+
+// slackEscaper escapes the characters that Slack's mrkdwn treats as markup.
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
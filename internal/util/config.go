@@ -3,6 +3,7 @@ package util
 import (
 	"os"
 	"strconv"
+	"time"
 )
 
 type Config struct {
@@ -12,8 +13,91 @@ type Config struct {
 	SMTPPort      int
 	SMTPUser      string
 	SMTPPass      string
-	Port          int
-	BaseUrl       string
+
+	EmailMXCheck   bool
+	EmailMXTimeout time.Duration
+	Port           int
+	BaseUrl        string
+
+	LogFormat string
+	LogLevel  string
+
+	TracesExporter    string
+	TracesSampleRatio float64
+
+	TokenSigningKeys    string
+	ConfirmTokenTTL     time.Duration
+	UnsubscribeTokenTTL time.Duration
+
+	AdminAPIKeys string
+
+	SchedulerInstanceID string
+	SchedulerLockWait   time.Duration
+	SchedulerCatchUp    time.Duration
+
+	WeatherAPIBaseURL   string
+	WeatherAPITimeout   time.Duration
+	WeatherAPIUserAgent string
+	WeatherAPIProxy     string
+	WeatherCacheTTL     time.Duration
+
+	WeatherBatchMax         int
+	WeatherBatchConcurrency int
+
+	WeatherStreamMax          int
+	WeatherStreamPollInterval time.Duration
+	WeatherStreamHeartbeat    time.Duration
+
+	WeatherRetryAttempts      int
+	WeatherRetryBaseDelay     time.Duration
+	WeatherRetryMaxDelay      time.Duration
+	WeatherBreakerFailures    int
+	WeatherBreakerOpenTimeout time.Duration
+
+	WeatherQuotaLimit     int
+	WeatherQuotaPeriod    string
+	WeatherQuotaDegradeAt float64
+	WeatherQuotaAlerts    string
+	OpsEmail              string
+
+	WeatherFallbackProvider   string
+	OpenMeteoGeocodingBaseURL string
+	OpenMeteoForecastBaseURL  string
+
+	WebhookTimeout        time.Duration
+	WebhookUserAgent      string
+	WebhookRetryAttempts  int
+	WebhookRetryBaseDelay time.Duration
+	WebhookRetryMaxDelay  time.Duration
+	WebhookDisableAfter   int
+
+	SlackWebhookBaseURL   string
+	TelegramAPIBaseURL    string
+	TelegramBotToken      string
+	TelegramWebhookSecret string
+
+	FeedHistorySize      int
+	FeedHistoryRetention time.Duration
+	FeedForecastDays     int
+	FeedForecastMaxAge   time.Duration
+
+	BotProtection    string
+	PowSecret        string
+	PowDifficulty    int
+	PowChallengeTTL  time.Duration
+	CaptchaVerifyURL string
+	CaptchaSecret    string
+
+	RateLimits     string
+	RateLimitStore string
+	TrustedProxies string
+
+	HealthCheckTimeout  time.Duration
+	HealthCheckCacheTTL time.Duration
+
+	ShutdownHTTPTimeout      time.Duration
+	ShutdownJobsTimeout      time.Duration
+	ShutdownInterruptTimeout time.Duration
 }
 
 func LoadConfig() (*Config, error) {
@@ -26,6 +110,89 @@ func LoadConfig() (*Config, error) {
 		SMTPUser:      os.Getenv("SMTP_USER"),
 		SMTPPass:      os.Getenv("SMTP_PASS"),
 		Port:          GetEnv("PORT", 8080),
+
+		EmailMXCheck:   GetEnv("EMAIL_MX_CHECK", false),
+		EmailMXTimeout: GetEnv("EMAIL_MX_TIMEOUT", 2*time.Second),
+
+		LogFormat: GetEnv("LOG_FORMAT", "json"),
+		LogLevel:  GetEnv("LOG_LEVEL", "info"),
+
+		TracesExporter:    GetEnv("TRACES_EXPORTER", "none"),
+		TracesSampleRatio: GetEnv("TRACES_SAMPLE_RATIO", 1.0),
+
+		TokenSigningKeys:    os.Getenv("TOKEN_SIGNING_KEYS"),
+		ConfirmTokenTTL:     GetEnv("CONFIRM_TOKEN_TTL", 72*time.Hour),
+		UnsubscribeTokenTTL: GetEnv("UNSUBSCRIBE_TOKEN_TTL", time.Duration(0)),
+
+		AdminAPIKeys: os.Getenv("ADMIN_API_KEYS"),
+
+		SchedulerInstanceID: GetEnv("SCHEDULER_INSTANCE_ID", defaultInstanceID()),
+		SchedulerLockWait:   GetEnv("SCHEDULER_LOCK_WAIT", 30*time.Minute),
+		SchedulerCatchUp:    GetEnv("SCHEDULER_CATCHUP_WINDOW", 2*time.Hour),
+
+		WeatherAPIBaseURL:   GetEnv("WEATHER_API_BASE_URL", "http://api.weatherapi.com/v1"),
+		WeatherAPITimeout:   GetEnv("WEATHER_API_TIMEOUT", 5*time.Second),
+		WeatherAPIUserAgent: GetEnv("WEATHER_API_USER_AGENT", "weather-api/1.0"),
+		WeatherAPIProxy:     os.Getenv("WEATHER_API_PROXY"),
+		WeatherCacheTTL:     GetEnv("WEATHER_CACHE_TTL", 5*time.Minute),
+
+		WeatherBatchMax:         GetEnv("WEATHER_BATCH_MAX", 25),
+		WeatherBatchConcurrency: GetEnv("WEATHER_BATCH_CONCURRENCY", 5),
+
+		WeatherStreamMax:          GetEnv("WEATHER_STREAM_MAX", 100),
+		WeatherStreamPollInterval: GetEnv("WEATHER_STREAM_POLL_INTERVAL", time.Minute),
+		WeatherStreamHeartbeat:    GetEnv("WEATHER_STREAM_HEARTBEAT", 15*time.Second),
+
+		WeatherRetryAttempts:      GetEnv("WEATHER_RETRY_ATTEMPTS", 3),
+		WeatherRetryBaseDelay:     GetEnv("WEATHER_RETRY_BASE_DELAY", 200*time.Millisecond),
+		WeatherRetryMaxDelay:      GetEnv("WEATHER_RETRY_MAX_DELAY", 2*time.Second),
+		WeatherBreakerFailures:    GetEnv("WEATHER_BREAKER_FAILURES", 5),
+		WeatherBreakerOpenTimeout: GetEnv("WEATHER_BREAKER_OPEN_TIMEOUT", 30*time.Second),
+
+		WeatherQuotaLimit:     GetEnv("WEATHER_QUOTA_LIMIT", 0),
+		WeatherQuotaPeriod:    GetEnv("WEATHER_QUOTA_PERIOD", "monthly"),
+		WeatherQuotaDegradeAt: GetEnv("WEATHER_QUOTA_DEGRADE_AT", 0.95),
+		WeatherQuotaAlerts:    GetEnv("WEATHER_QUOTA_ALERT_PERCENTS", "80,95"),
+		OpsEmail:              os.Getenv("OPS_EMAIL"),
+
+		WeatherFallbackProvider:   GetEnv("WEATHER_FALLBACK_PROVIDER", "none"),
+		OpenMeteoGeocodingBaseURL: GetEnv("OPEN_METEO_GEOCODING_URL", "https://geocoding-api.open-meteo.com/v1"),
+		OpenMeteoForecastBaseURL:  GetEnv("OPEN_METEO_FORECAST_URL", "https://api.open-meteo.com/v1"),
+
+		WebhookTimeout:        GetEnv("WEBHOOK_TIMEOUT", 5*time.Second),
+		WebhookUserAgent:      GetEnv("WEBHOOK_USER_AGENT", "weather-api-webhooks/1.0"),
+		WebhookRetryAttempts:  GetEnv("WEBHOOK_RETRY_ATTEMPTS", 3),
+		WebhookRetryBaseDelay: GetEnv("WEBHOOK_RETRY_BASE_DELAY", 500*time.Millisecond),
+		WebhookRetryMaxDelay:  GetEnv("WEBHOOK_RETRY_MAX_DELAY", 5*time.Second),
+		WebhookDisableAfter:   GetEnv("WEBHOOK_DISABLE_AFTER", 5),
+
+		SlackWebhookBaseURL:   GetEnv("SLACK_WEBHOOK_BASE_URL", "https://hooks.slack.com/services/"),
+		TelegramAPIBaseURL:    GetEnv("TELEGRAM_API_BASE_URL", "https://api.telegram.org"),
+		TelegramBotToken:      os.Getenv("TELEGRAM_BOT_TOKEN"),
+		TelegramWebhookSecret: os.Getenv("TELEGRAM_WEBHOOK_SECRET"),
+
+		FeedHistorySize:      GetEnv("FEED_HISTORY_SIZE", 48),
+		FeedHistoryRetention: GetEnv("FEED_HISTORY_RETENTION", 7*24*time.Hour),
+		FeedForecastDays:     GetEnv("FEED_FORECAST_DAYS", 3),
+		FeedForecastMaxAge:   GetEnv("FEED_FORECAST_MAX_AGE", time.Hour),
+
+		BotProtection:    GetEnv("BOT_PROTECTION", "honeypot"),
+		PowSecret:        os.Getenv("POW_SECRET"),
+		PowDifficulty:    GetEnv("POW_DIFFICULTY", 18),
+		PowChallengeTTL:  GetEnv("POW_CHALLENGE_TTL", 5*time.Minute),
+		CaptchaVerifyURL: GetEnv("CAPTCHA_VERIFY_URL", "https://challenges.cloudflare.com/turnstile/v0/siteverify"),
+		CaptchaSecret:    os.Getenv("CAPTCHA_SECRET"),
+
+		RateLimits:     GetEnv("RATE_LIMITS", "weather_ip=60/1m,weather_batch_ip=10/1m,subscribe_ip=10/1h,subscribe_email=5/24h,token_ip=30/1m,challenge_ip=30/1m"),
+		RateLimitStore: GetEnv("RATE_LIMIT_STORE", "postgres"),
+		TrustedProxies: os.Getenv("TRUSTED_PROXIES"),
+
+		HealthCheckTimeout:  GetEnv("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+		HealthCheckCacheTTL: GetEnv("HEALTH_CHECK_CACHE_TTL", 10*time.Second),
+
+		ShutdownHTTPTimeout:      GetEnv("SHUTDOWN_HTTP_TIMEOUT", 10*time.Second),
+		ShutdownJobsTimeout:      GetEnv("SHUTDOWN_JOBS_TIMEOUT", 60*time.Second),
+		ShutdownInterruptTimeout: GetEnv("SHUTDOWN_INTERRUPT_TIMEOUT", 10*time.Second),
 	}, nil
 }
 
@@ -36,16 +203,36 @@ func GetEnv[T any](key string, defaultValue T) T {
 	}
 
 	switch any(defaultValue).(type) {
+	case time.Duration:
+		if d, err := time.ParseDuration(val); err == nil {
+			return any(d).(T)
+		}
 	case int:
 		if i, err := strconv.Atoi(val); err == nil {
 			return any(i).(T)
 		}
+	case float64:
+		if f, err := strconv.ParseFloat(val, 64); err == nil {
+			return any(f).(T)
+		}
+	case bool:
+		if b, err := strconv.ParseBool(val); err == nil {
+			return any(b).(T)
+		}
 	case string:
 		return any(val).(T)
 	}
 	return defaultValue
 }
 
+func defaultInstanceID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return host + "-" + strconv.Itoa(os.Getpid())
+}
+
 func GetBaseURL() string {
 	return GetEnv("BASE_URL", "http://localhost:8080")
 }
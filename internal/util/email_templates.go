@@ -2,6 +2,7 @@ package util
 
 import (
 	"fmt"
+	"time"
 )
 
 func BuildConfirmationEmail(city, token string) (subject, body string) {
@@ -21,8 +22,7 @@ func BuildConfirmationEmail(city, token string) (subject, body string) {
 }
 
 func BuildWeatherUpdateEmail(city string, temperature float64, humidity int, description, token string) (subject, body string) {
-	baseURL := GetBaseURL()
-	unsubscribeURL := fmt.Sprintf("%s/api/unsubscribe/%s", baseURL, token)
+	unsubscribeURL := UnsubscribeURL(token)
 	subject = "Weather Update"
 	body = fmt.Sprintf(`
         <html>
@@ -34,3 +34,20 @@ func BuildWeatherUpdateEmail(city string, temperature float64, humidity int, des
     `, city, temperature, humidity, description, unsubscribeURL)
 	return
 }
+
+func UnsubscribeURL(token string) string {
+	return fmt.Sprintf("%s/api/unsubscribe/%s", GetBaseURL(), token)
+}
+
+func BuildQuotaAlertEmail(provider string, percent, calls, limit int, resetsAt time.Time) (subject, body string) {
+	subject = fmt.Sprintf("Weather provider quota at %d%%", percent)
+	body = fmt.Sprintf(`
+        <html>
+            <body>
+                <p>The %s quota has reached %d%%: %d of %d calls used.</p>
+                <p>The quota resets at %s.</p>
+            </body>
+        </html>
+    `, provider, percent, calls, limit, resetsAt.UTC().Format(time.RFC1123))
+	return
+}
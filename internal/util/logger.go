@@ -0,0 +1,122 @@
+package util
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type logContextKey int
+
+const (
+	requestIDKey logContextKey = iota
+	subscriptionIDKey
+)
+
+// Attributes with these keys, or keys ending in "_" and one of them such as "ops_email", are
+// redacted by every logger created with NewLogger, also inside groups.
+const (
+	LogKeyEmail = "email"
+	LogKeyToken = "token"
+)
+
+// NewLogger creates a logger writing to w in "json" or "text" format at the given level
+// (debug, info, warn or error). Request, subscription and trace ids stored in the context are
+// added to every record, and emails and tokens are redacted.
+func NewLogger(w io.Writer, format, level string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q", level)
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl, ReplaceAttr: redactAttr}
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case "json":
+		handler = slog.NewJSONHandler(w, opts)
+	case "text":
+		handler = slog.NewTextHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("invalid log format %q", format)
+	}
+	return slog.New(contextHandler{handler}), nil
+}
+
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+func WithSubscriptionID(ctx context.Context, id int) context.Context {
+	return context.WithValue(ctx, subscriptionIDKey, id)
+}
+
+// RedactEmail keeps the first character of the local part and the domain, e.g. "j***@example.com".
+func RedactEmail(email string) string {
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok || local == "" {
+		return "***"
+	}
+	return local[:1] + "***@" + domain
+}
+
+// HashToken returns a short hash of the token, enough to correlate log lines without exposing it.
+func HashToken(token string) string {
+	if token == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(token))
+	return "sha256:" + hex.EncodeToString(sum[:6])
+}
+
+func redactAttr(_ []string, a slog.Attr) slog.Attr {
+	if a.Value.Kind() != slog.KindString {
+		return a
+	}
+	switch {
+	case matchesLogKey(a.Key, LogKeyEmail):
+		return slog.String(a.Key, RedactEmail(a.Value.String()))
+	case matchesLogKey(a.Key, LogKeyToken):
+		return slog.String(a.Key, HashToken(a.Value.String()))
+	}
+	return a
+}
+
+func matchesLogKey(key, sensitive string) bool {
+	return key == sensitive || strings.HasSuffix(key, "_"+sensitive)
+}
+
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if id := RequestIDFromContext(ctx); id != "" {
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	if id, ok := ctx.Value(subscriptionIDKey).(int); ok {
+		r.AddAttrs(slog.Int("subscription_id", id))
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(slog.String("trace_id", sc.TraceID().String()), slog.String("span_id", sc.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,126 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactEmail(t *testing.T) {
+	tests := []struct {
+		email    string
+		expected string
+	}{
+		{email: "john@example.com", expected: "j***@example.com"},
+		{email: "j@example.com", expected: "j***@example.com"},
+		{email: "@example.com", expected: "***"},
+		{email: "not an email", expected: "***"},
+		{email: "", expected: "***"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			assert.Equal(t, tt.expected, RedactEmail(tt.email))
+		})
+	}
+}
+
+func TestHashToken(t *testing.T) {
+	assert.Equal(t, "", HashToken(""))
+	assert.Regexp(t, `^sha256:[0-9a-f]{12}$`, HashToken("token123"))
+	assert.Equal(t, HashToken("token123"), HashToken("token123"))
+	assert.NotEqual(t, HashToken("token123"), HashToken("token124"))
+}
+
+func TestNewLogger_RedactsAttributes(t *testing.T) {
+	tests := []struct {
+		name     string
+		log      func(logger *slog.Logger)
+		expected map[string]any
+	}{
+		{
+			name: "email and token",
+			log: func(logger *slog.Logger) {
+				logger.Info("msg", LogKeyEmail, "john@example.com", LogKeyToken, "token123")
+			},
+			expected: map[string]any{"email": "j***@example.com", "token": HashToken("token123")},
+		},
+		{
+			name: "keys ending in email or token",
+			log: func(logger *slog.Logger) {
+				logger.Info("msg", "ops_email", "ops@example.com", "unsubscribe_token", "token123")
+			},
+			expected: map[string]any{"ops_email": "o***@example.com", "unsubscribe_token": HashToken("token123")},
+		},
+		{
+			name: "attributes in groups",
+			log: func(logger *slog.Logger) {
+				logger.Info("msg", slog.Group("subscription", LogKeyEmail, "john@example.com", "city", "Kyiv"))
+			},
+			expected: map[string]any{"subscription": map[string]any{"email": "j***@example.com", "city": "Kyiv"}},
+		},
+		{
+			name: "attributes of a logger with a group",
+			log: func(logger *slog.Logger) {
+				logger.WithGroup("request").With(LogKeyToken, "token123").Info("msg")
+			},
+			expected: map[string]any{"request": map[string]any{"token": HashToken("token123")}},
+		},
+		{
+			name: "other keys",
+			log: func(logger *slog.Logger) {
+				logger.Info("msg", "city", "john@example.com", "emails", "john@example.com", "tokenizer", "token123")
+			},
+			expected: map[string]any{"city": "john@example.com", "emails": "john@example.com", "tokenizer": "token123"},
+		},
+		{
+			name: "values other than strings",
+			log: func(logger *slog.Logger) {
+				logger.Info("msg", LogKeyToken, 42)
+			},
+			expected: map[string]any{"token": 42.0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger, err := NewLogger(&buf, "json", "info")
+			require.NoError(t, err)
+
+			tt.log(logger)
+
+			var record map[string]any
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+			for key, value := range tt.expected {
+				assert.Equal(t, value, record[key], key)
+			}
+		})
+	}
+}
+
+func TestNewLogger_AddsContextIDs(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(&buf, "json", "info")
+	require.NoError(t, err)
+	ctx := WithSubscriptionID(WithRequestID(context.Background(), "req-1"), 7)
+
+	logger.InfoContext(ctx, "msg")
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "req-1", record["request_id"])
+	assert.Equal(t, 7.0, record["subscription_id"])
+}
+
+func TestNewLogger_RejectsInvalidSettings(t *testing.T) {
+	_, err := NewLogger(&bytes.Buffer{}, "xml", "info")
+	assert.EqualError(t, err, `invalid log format "xml"`)
+	_, err = NewLogger(&bytes.Buffer{}, "json", "loud")
+	assert.EqualError(t, err, `invalid log level "loud"`)
+}
@@ -0,0 +1,93 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+const ServiceName = "weather-api"
+
+// InitTracing installs the global tracer provider and propagator. exporter is "otlp" (configured
+// through the standard OTEL_EXPORTER_OTLP_* variables), "stdout" for local use, or "none".
+// The returned function flushes pending spans and must be called on shutdown.
+func InitTracing(ctx context.Context, exporter string, sampleRatio float64) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var spanExporter sdktrace.SpanExporter
+	var err error
+	switch exporter {
+	case "", "none":
+		return func(context.Context) error { return nil }, nil
+	case "otlp":
+		spanExporter, err = otlptracehttp.New(ctx)
+	case "stdout":
+		spanExporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	default:
+		return nil, fmt.Errorf("unknown traces exporter %q", exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(ServiceName)))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(spanExporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// NewHostOnlyTracingTransport traces requests through next like otelhttp.NewTransport, but the
+// tracing transport only sees the scheme and host of the URL. It is meant for URLs that are
+// themselves credentials, such as Slack incoming webhooks, which must not end up in url.full.
+func NewHostOnlyTracingTransport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return hideURLTransport{next: otelhttp.NewTransport(restoreURLTransport{next: next})}
+}
+
+type fullURLKey struct{}
+
+// hideURLTransport replaces the request URL with its scheme and host and keeps the full URL in
+// the context, from which restoreURLTransport puts it back below the tracing transport.
+type hideURLTransport struct {
+	next http.RoundTripper
+}
+
+func (t hideURLTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	full := req.URL
+	req = req.Clone(context.WithValue(req.Context(), fullURLKey{}, full))
+	req.URL = &url.URL{Scheme: full.Scheme, Host: full.Host}
+	return t.next.RoundTrip(req)
+}
+
+type restoreURLTransport struct {
+	next http.RoundTripper
+}
+
+func (t restoreURLTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if full, ok := req.Context().Value(fullURLKey{}).(*url.URL); ok {
+		req = req.Clone(req.Context())
+		req.URL = full
+	}
+	return t.next.RoundTrip(req)
+}
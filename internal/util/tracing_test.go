@@ -0,0 +1,41 @@
+package util
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestNewHostOnlyTracingTransport(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.RequestURI()
+	}))
+	defer server.Close()
+	recorder := tracetest.NewSpanRecorder()
+	ctx, parent := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test").Start(context.Background(), "parent")
+
+	client := &http.Client{Transport: NewHostOnlyTracingTransport(server.Client().Transport)}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/services/T000/B000/secret?token=secret", nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	parent.End()
+
+	assert.Equal(t, "/services/T000/B000/secret?token=secret", path)
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	for _, attr := range spans[0].Attributes() {
+		assert.NotContains(t, attr.Value.Emit(), "secret", string(attr.Key))
+		if attr.Key == "url.full" {
+			assert.Equal(t, server.URL, attr.Value.AsString())
+		}
+	}
+}
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS deliveries (
+     id SERIAL PRIMARY KEY,
+     subscription_id INTEGER NOT NULL,
+     kind TEXT NOT NULL,
+     status TEXT NOT NULL,
+     error TEXT NOT NULL DEFAULT '',
+     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_deliveries_created_at ON deliveries (created_at);
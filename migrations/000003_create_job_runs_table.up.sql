@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS job_runs (
+     id SERIAL PRIMARY KEY,
+     job TEXT NOT NULL,
+     scheduled_time TIMESTAMPTZ NOT NULL,
+     status TEXT NOT NULL,
+     owner TEXT NOT NULL,
+     error TEXT NOT NULL DEFAULT '',
+     started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+     finished_at TIMESTAMPTZ,
+     UNIQUE (job, scheduled_time)
+);
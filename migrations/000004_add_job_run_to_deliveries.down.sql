@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_deliveries_job_run_id;
+
+ALTER TABLE deliveries DROP COLUMN IF EXISTS job_run_id;
@@ -0,0 +1,3 @@
+ALTER TABLE deliveries ADD COLUMN IF NOT EXISTS job_run_id INTEGER;
+
+CREATE INDEX IF NOT EXISTS idx_deliveries_job_run_id ON deliveries (job_run_id) WHERE job_run_id IS NOT NULL;
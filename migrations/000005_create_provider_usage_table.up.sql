@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS provider_usage (
+     provider TEXT NOT NULL,
+     period_start TIMESTAMPTZ NOT NULL,
+     calls INTEGER NOT NULL DEFAULT 0,
+     alerted_percent INTEGER NOT NULL DEFAULT 0,
+     updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+     PRIMARY KEY (provider, period_start)
+);
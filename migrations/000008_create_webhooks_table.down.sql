@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS webhooks;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS channel;
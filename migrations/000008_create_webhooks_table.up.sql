@@ -0,0 +1,11 @@
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS channel TEXT NOT NULL DEFAULT 'email';
+
+CREATE TABLE IF NOT EXISTS webhooks (
+     subscription_id INTEGER PRIMARY KEY REFERENCES subscriptions (id) ON DELETE CASCADE,
+     url TEXT NOT NULL,
+     secret TEXT NOT NULL,
+     failures INTEGER NOT NULL DEFAULT 0,
+     last_error TEXT NOT NULL DEFAULT '',
+     disabled_at TIMESTAMPTZ,
+     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_subscriptions_telegram_chat;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS address;
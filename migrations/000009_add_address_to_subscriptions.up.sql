@@ -0,0 +1,3 @@
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS address TEXT NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscriptions_telegram_chat ON subscriptions (address) WHERE channel = 'telegram';
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS observations (
+     id SERIAL PRIMARY KEY,
+     city TEXT NOT NULL,
+     temperature DOUBLE PRECISION NOT NULL,
+     humidity INTEGER NOT NULL,
+     description TEXT NOT NULL,
+     observed_at TIMESTAMPTZ NOT NULL,
+     recorded_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_observations_city_observed_at ON observations (lower(city), observed_at);